@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
@@ -91,6 +92,12 @@ func loadInputFile(path string) (*domain.AppraisalInput, error) {
 	if err := json.Unmarshal(data, &input); err != nil {
 		return nil, fmt.Errorf("parsing input JSON: %w", err)
 	}
+	// A relative usage events file is relative to the input file, not the
+	// working directory.
+	if ue := input.UsageEvents; ue != nil && ue.File != nil && path != "" && path != "-" && !filepath.IsAbs(*ue.File) {
+		file := filepath.Join(filepath.Dir(path), *ue.File)
+		ue.File = &file
+	}
 	return &input, nil
 }
 
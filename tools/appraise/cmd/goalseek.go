@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/analysis"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/spf13/cobra"
+)
+
+var (
+	seekInputFile string
+	seekTarget    string
+	seekVary      string
+	seekMin       float64
+	seekMax       float64
+	seekTolerance float64
+	seekFormat    string
+)
+
+var goalseekCmd = &cobra.Command{
+	Use:   "goalseek <module> <function>",
+	Short: "Solve for the input value that makes an output hit a target",
+	Long: `Vary one input field until a calculator output reaches a target value.
+
+--target is output=value, where output is a JSON path into the result
+(optionally prefixed with the function name); a bare value targets the
+function's primary output. --vary is the JSON path of the input field.
+
+The search expands outward from the field's current value until the output
+crosses the target, then bisects. Non-negative fields stay non-negative
+unless --min says otherwise.
+
+Examples:
+  appraise goalseek financial unit_economics --input f.json --target margin_per_customer=0 --vary financials.direct_cost_per_customer
+  appraise goalseek pricing cost_floor --input data.json --target margin=0 --vary product.price
+  appraise goalseek financial clv_advanced --input f.json --target ltv_to_cac=3 --vary financials.clv_model.monthly_churn
+  appraise goalseek scoring go_no_go --input s.json --target go_no_go.weighted_score=3.0 --vary scoring.dimensions[0].score`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := loadInputFile(seekInputFile)
+		if err != nil {
+			return fmt.Errorf("loading input: %w", err)
+		}
+
+		output, target, err := parseTarget(seekTarget, args[1])
+		if err != nil {
+			return err
+		}
+		opts := analysis.GoalSeekOptions{
+			Output:    output,
+			Target:    target,
+			Field:     seekVary,
+			Tolerance: seekTolerance,
+		}
+		if cmd.Flags().Changed("min") {
+			opts.Min = &seekMin
+		}
+		if cmd.Flags().Changed("max") {
+			opts.Max = &seekMax
+		}
+
+		result, err := analysis.GoalSeek(calculators.NewRegistry(), args[0], args[1], input, opts)
+		if err != nil {
+			return fmt.Errorf("goal seek failed: %w", err)
+		}
+
+		switch seekFormat {
+		case "compact", "llm":
+			return outputCalcCompact(result)
+		case "json":
+			return outputCalcJSON(result)
+		default:
+			return fmt.Errorf("unknown format %q: use \"json\" or \"compact\"", seekFormat)
+		}
+	},
+}
+
+// parseTarget splits "output=value" (or a bare value) and strips an optional
+// "<function>." prefix from the output path.
+func parseTarget(s, function string) (string, float64, error) {
+	output, value := "", s
+	if i := strings.LastIndexByte(s, '='); i >= 0 {
+		output, value = strings.TrimSpace(s[:i]), s[i+1:]
+	}
+	target, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid --target %q: want output=value", s)
+	}
+	output = strings.TrimPrefix(output, function+".")
+	return output, target, nil
+}
+
+func init() {
+	goalseekCmd.Flags().StringVar(&seekInputFile, "input", "", "Path to input JSON file (required)")
+	goalseekCmd.Flags().StringVar(&seekTarget, "target", "", "Target as output=value, e.g. margin_per_customer=0 (required)")
+	goalseekCmd.Flags().StringVar(&seekVary, "vary", "", "Input field to solve for, e.g. product.price (required)")
+	goalseekCmd.Flags().Float64Var(&seekMin, "min", 0, "Lower bound for the field")
+	goalseekCmd.Flags().Float64Var(&seekMax, "max", 0, "Upper bound for the field")
+	goalseekCmd.Flags().Float64Var(&seekTolerance, "tolerance", 0, "Accepted |output - target| (default 1e-6 relative)")
+	goalseekCmd.Flags().StringVar(&seekFormat, "format", "json", "Output format: json (default) | compact | llm")
+	_ = goalseekCmd.MarkFlagRequired("input")
+	_ = goalseekCmd.MarkFlagRequired("target")
+	_ = goalseekCmd.MarkFlagRequired("vary")
+}
@@ -19,7 +19,12 @@ var rootCmd = &cobra.Command{
 Three access modes:
   appraise q '<query>'                          DSL queries (structured reads)
   appraise grep '<pattern>'                     Scoped text search
-  appraise calc <module> <function> --input <f> Direct calculation`,
+  appraise calc <module> <function> --input <f> Direct calculation
+
+What-if analysis:
+  appraise sensitivity <module> <function> --input <f>  Tornado / input ranking
+  appraise scenarios <module> <function> --input <f>    Named scenarios side by side
+  appraise goalseek <module> <function> --input <f>     Solve an input for a target output`,
 }
 
 // Execute runs the root command.
@@ -44,6 +49,11 @@ func init() {
 
 	// Keep the direct calc command.
 	rootCmd.AddCommand(calcCmd)
+
+	// What-if analysis over any calculator.
+	rootCmd.AddCommand(sensitivityCmd)
+	rootCmd.AddCommand(scenariosCmd)
+	rootCmd.AddCommand(goalseekCmd)
 }
 
 // queryCommand creates a "q" subcommand with query normalization.
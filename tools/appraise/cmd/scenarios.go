@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/analysis"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scenInputFile string
+	scenOutput    string
+	scenFormat    string
+)
+
+var scenariosCmd = &cobra.Command{
+	Use:   "scenarios <module> <function>",
+	Short: "Compare a calculator across named input scenarios",
+	Long: `Run a calculator on the base input and on every entry of its "scenarios"
+section, and print the outputs side by side with the change against base.
+
+Each scenario is a JSON merge patch over the base input:
+
+  "scenarios": [
+    {"name": "optimistic",  "patch": {"financials": {"revenue_per_customer": 120}}},
+    {"name": "pessimistic", "patch": {"financials": {"revenue_per_customer": 80, "cogs": 45}}}
+  ]
+
+Objects merge recursively, null removes a field, and arrays are replaced
+whole. By default every top-level numeric output is compared; --output takes
+a comma-separated list of JSON paths into the result instead.
+
+Examples:
+  appraise scenarios financial unit_economics --input f.json
+  appraise scenarios financial projection --input f.json --output npv,irr,break_even_month
+  appraise scenarios pricing bvr --input data.json --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := loadInputFile(scenInputFile)
+		if err != nil {
+			return fmt.Errorf("loading input: %w", err)
+		}
+
+		var outputs []string
+		for _, o := range strings.Split(scenOutput, ",") {
+			if o = strings.TrimSpace(o); o != "" {
+				outputs = append(outputs, o)
+			}
+		}
+
+		result, err := analysis.Scenarios(calculators.NewRegistry(), args[0], args[1], input, outputs)
+		if err != nil {
+			return fmt.Errorf("scenarios failed: %w", err)
+		}
+
+		switch scenFormat {
+		case "table":
+			return analysis.ScenarioTable(os.Stdout, result)
+		case "compact", "llm":
+			return outputCalcCompact(result)
+		case "json":
+			return outputCalcJSON(result)
+		default:
+			return fmt.Errorf("unknown format %q: use \"table\", \"json\", or \"compact\"", scenFormat)
+		}
+	},
+}
+
+func init() {
+	scenariosCmd.Flags().StringVar(&scenInputFile, "input", "", "Path to input JSON file (required)")
+	scenariosCmd.Flags().StringVar(&scenOutput, "output", "", "Outputs to compare (comma-separated JSON paths; default: all top-level numbers)")
+	scenariosCmd.Flags().StringVar(&scenFormat, "format", "table", "Output format: table (default) | json | compact | llm")
+	_ = scenariosCmd.MarkFlagRequired("input")
+}
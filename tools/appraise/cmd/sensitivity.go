@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/analysis"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sensInputFile string
+	sensVary      string
+	sensRange     float64
+	sensOutput    string
+	sensFormat    string
+	sensWidth     int
+)
+
+var sensitivityCmd = &cobra.Command{
+	Use:   "sensitivity <module> <function>",
+	Short: "Rank input assumptions by their effect on a calculator output",
+	Long: `Perturb each numeric input field by ±range, rerun the calculator, and rank
+the fields by the swing in its primary output (tornado data).
+
+--vary all varies every numeric field set in the input; otherwise pass a
+comma-separated list of JSON paths (e.g. financials.cogs,product.price).
+--output overrides the measured output (JSON path into the result).
+
+Examples:
+  appraise sensitivity financial unit_economics --input f.json --vary all --range 0.2
+  appraise sensitivity pricing bvr --input data.json --vary product.price,components[0].standalone_price
+  appraise sensitivity financial clv --input f.json --format chart`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Zero means "default" to the analysis package; an explicit flag
+		// must be a real perturbation.
+		if cmd.Flags().Changed("range") && (sensRange <= 0 || sensRange > 1) {
+			return fmt.Errorf("--range must be greater than 0 and at most 1")
+		}
+
+		input, err := loadInputFile(sensInputFile)
+		if err != nil {
+			return fmt.Errorf("loading input: %w", err)
+		}
+
+		opts := analysis.SensitivityOptions{Output: sensOutput, Range: sensRange}
+		if sensVary != "" && sensVary != "all" {
+			for _, f := range strings.Split(sensVary, ",") {
+				if f = strings.TrimSpace(f); f != "" {
+					opts.Fields = append(opts.Fields, f)
+				}
+			}
+		}
+
+		result, err := analysis.Sensitivity(calculators.NewRegistry(), args[0], args[1], input, opts)
+		if err != nil {
+			return fmt.Errorf("sensitivity failed: %w", err)
+		}
+
+		switch sensFormat {
+		case "chart":
+			return analysis.Tornado(os.Stdout, result, sensWidth)
+		case "compact", "llm":
+			return outputCalcCompact(result)
+		case "json":
+			return outputCalcJSON(result)
+		default:
+			return fmt.Errorf("unknown format %q: use \"json\", \"compact\", or \"chart\"", sensFormat)
+		}
+	},
+}
+
+func init() {
+	sensitivityCmd.Flags().StringVar(&sensInputFile, "input", "", "Path to input JSON file (required)")
+	sensitivityCmd.Flags().StringVar(&sensVary, "vary", "all", "Fields to vary: all | comma-separated JSON paths")
+	sensitivityCmd.Flags().Float64Var(&sensRange, "range", analysis.DefaultSensitivityRange, "Relative perturbation, e.g. 0.2 for ±20%")
+	sensitivityCmd.Flags().StringVar(&sensOutput, "output", "", "Output to measure (JSON path; default: primary output)")
+	sensitivityCmd.Flags().StringVar(&sensFormat, "format", "json", "Output format: json (default) | compact | llm | chart")
+	sensitivityCmd.Flags().IntVar(&sensWidth, "width", 60, "Chart bar width (chart format)")
+	_ = sensitivityCmd.MarkFlagRequired("input")
+}
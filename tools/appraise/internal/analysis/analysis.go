@@ -0,0 +1,114 @@
+// Package analysis runs calculators repeatedly over modified copies of the
+// input: sensitivity (tornado) analysis, named scenarios, and goal seek.
+// Every run goes through the calculators.Registry, so any module function
+// can be analyzed.
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// cloneInput deep-copies input via a JSON round trip, so overrides and
+// patches never reach the caller's input.
+func cloneInput(input *domain.AppraisalInput) (*domain.AppraisalInput, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("encoding input: %w", err)
+	}
+	var out domain.AppraisalInput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("decoding input: %w", err)
+	}
+	return &out, nil
+}
+
+// run executes module.function on input and extracts output.
+func run(registry *calculators.Registry, module, function string, input *domain.AppraisalInput, output string) (float64, error) {
+	result, err := registry.Execute(module, function, input)
+	if err != nil {
+		return 0, err
+	}
+	return OutputValue(result, output)
+}
+
+// OutputValue extracts the number at a JSON path such as "margin_per_customer",
+// "gaps[0].value_gap", or "[0].value" from a calculator result. Booleans
+// read as 1 or 0.
+func OutputValue(result interface{}, path string) (float64, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return 0, fmt.Errorf("encoding result: %w", err)
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return 0, fmt.Errorf("decoding result: %w", err)
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		name, indexes, err := splitIndexes(segment)
+		if err != nil {
+			return 0, fmt.Errorf("output %q: %w", path, err)
+		}
+		if name != "" {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return 0, fmt.Errorf("output %q: %q is not an object", path, name)
+			}
+			if v, ok = obj[name]; !ok {
+				return 0, fmt.Errorf("output %q: no field %q in result", path, name)
+			}
+		}
+		for _, i := range indexes {
+			list, ok := v.([]interface{})
+			if !ok {
+				return 0, fmt.Errorf("output %q: not a list", path)
+			}
+			if i >= len(list) {
+				return 0, fmt.Errorf("output %q: index %d out of range (%d items)", path, i, len(list))
+			}
+			v = list[i]
+		}
+	}
+
+	switch tv := v.(type) {
+	case float64:
+		return tv, nil
+	case bool:
+		if tv {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("output %q is not numeric", path)
+	}
+}
+
+// splitIndexes splits "name[1][2]" into "name" and [1 2]. The name may be
+// empty for a bare "[0]".
+func splitIndexes(segment string) (string, []int, error) {
+	open := strings.IndexByte(segment, '[')
+	if open < 0 {
+		return segment, nil, nil
+	}
+	name, rest := segment[:open], segment[open:]
+	var indexes []int
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return "", nil, fmt.Errorf("malformed segment %q", segment)
+		}
+		i, err := strconv.Atoi(rest[1:end])
+		if err != nil || i < 0 {
+			return "", nil, fmt.Errorf("invalid index in %q", segment)
+		}
+		indexes = append(indexes, i)
+		rest = rest[end+1:]
+	}
+	return name, indexes, nil
+}
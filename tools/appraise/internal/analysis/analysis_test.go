@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func almostEqual(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestOutputValue(t *testing.T) {
+	ratio := 1.25
+	result := struct {
+		Margin float64                    `json:"margin"`
+		Passes bool                       `json:"passes"`
+		Name   string                     `json:"name"`
+		Gaps   []domain.TierGap           `json:"gaps"`
+		Nested []domain.SingleValueResult `json:"nested"`
+	}{
+		Margin: 12.5,
+		Passes: true,
+		Name:   "x",
+		Gaps:   []domain.TierGap{{FromTier: "a", ToTier: "b", ValueToPriceRatio: &ratio}},
+		Nested: []domain.SingleValueResult{{Value: 3}},
+	}
+
+	tests := []struct {
+		path    string
+		want    float64
+		wantErr string
+	}{
+		{path: "margin", want: 12.5},
+		{path: "passes", want: 1},
+		{path: "gaps[0].value_to_price_ratio", want: 1.25},
+		{path: "nested[0].value", want: 3},
+		{path: "name", wantErr: "not numeric"},
+		{path: "missing", wantErr: "no field"},
+		{path: "gaps[3].value_gap", wantErr: "out of range"},
+		{path: "gaps[x]", wantErr: "invalid index"},
+		{path: "margin.value", wantErr: "not an object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := OutputValue(result, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("OutputValue(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	// Root-level lists
+	list := []domain.SingleValueResult{{Value: 0.4}, {Value: 0.7}}
+	if got, err := OutputValue(list, "[1].value"); err != nil || got != 0.7 {
+		t.Errorf("OutputValue([1].value) = %v, %v; want 0.7", got, err)
+	}
+}
@@ -0,0 +1,216 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/fieldpath"
+)
+
+const (
+	maxBracketSteps   = 60
+	maxBisectionSteps = 200
+)
+
+// GoalSeekOptions configures a goal-seek run.
+type GoalSeekOptions struct {
+	Output    string   // JSON path of the output; default: the function's primary output
+	Target    float64  // desired output value
+	Field     string   // input path to solve for
+	Min       *float64 // optional lower bound for the field
+	Max       *float64 // optional upper bound for the field
+	Tolerance float64  // accepted |output - target|; default 1e-6 * max(1, |target|)
+}
+
+// GoalSeek solves for the value of one input field that makes a calculator
+// output equal the target. Starting from the field's current value, it
+// expands outward until the output reaches or crosses the target, then
+// bisects the bracket. Without an explicit Min, a non-negative field is kept
+// non-negative. Calculator errors at trial points (e.g. a churn rate above
+// 1) shrink the search toward the edge of the valid region instead of
+// failing it.
+func GoalSeek(registry *calculators.Registry, module, function string, input *domain.AppraisalInput, opts GoalSeekOptions) (*domain.GoalSeekResult, error) {
+	if opts.Field == "" {
+		return nil, fmt.Errorf("field to vary required")
+	}
+	if opts.Min != nil && opts.Max != nil && *opts.Min >= *opts.Max {
+		return nil, fmt.Errorf("min must be below max")
+	}
+	output := opts.Output
+	if output == "" {
+		output = registry.PrimaryOutput(module, function)
+	}
+	tol := opts.Tolerance
+	if tol <= 0 {
+		tol = 1e-6 * math.Max(1, math.Abs(opts.Target))
+	}
+
+	result := &domain.GoalSeekResult{
+		Module:   module,
+		Function: function,
+		Output:   output,
+		Target:   opts.Target,
+		Field:    opts.Field,
+	}
+
+	start := 0.0
+	if v, err := fieldpath.Get(input, opts.Field); err == nil {
+		start = v
+		result.BaseValue = &v
+	} else if err := fieldpath.Set(&domain.AppraisalInput{}, opts.Field, 0); err != nil {
+		return nil, err
+	}
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if opts.Min != nil {
+		lo = *opts.Min
+	} else if start >= 0 {
+		lo = 0
+	}
+	if opts.Max != nil {
+		hi = *opts.Max
+	}
+	start = math.Min(math.Max(start, lo), hi)
+
+	// f is output - target at x; ok is false when the calculator fails there.
+	evals := 0
+	f := func(x float64) (float64, bool) {
+		evals++
+		out, err := runWith(registry, module, function, input, output, opts.Field, x)
+		if err != nil {
+			return 0, false
+		}
+		return out - opts.Target, true
+	}
+
+	if result.BaseValue != nil {
+		if out, err := run(registry, module, function, input, output); err == nil {
+			result.BaseOutput = &out
+		}
+	}
+
+	f0, ok := f(start)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s fails at %s = %g", module, function, opts.Field, start)
+	}
+	if math.Abs(f0) <= tol {
+		return finishGoalSeek(result, start, f0, evals, tol), nil
+	}
+
+	// crossed reports whether f has reached the target or passed it.
+	crossed := func(fx float64) bool { return fx == 0 || (fx < 0) != (f0 < 0) }
+	near, fNear, far, fFar, from, to, found := bracket(f, crossed, start, f0, lo, hi)
+	if !found {
+		return nil, fmt.Errorf("no value of %s in [%.6g, %.6g] reaches %s = %g", opts.Field, from, to, output, opts.Target)
+	}
+
+	// Bisect down to the crossing point. Narrowing the bracket rather than
+	// stopping at the first point within tolerance also finds the threshold
+	// of step outputs (whole months, pass/fail flags).
+	xtol := 1e-9 * math.Max(1, math.Abs(near))
+	for i := 0; i < maxBisectionSteps && math.Abs(far-near) > xtol; i++ {
+		mid := near + (far-near)/2
+		if mid == near || mid == far {
+			break
+		}
+		fm, ok := f(mid)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s fails at %s = %g inside the bracket", module, function, opts.Field, mid)
+		}
+		if crossed(fm) {
+			far, fFar = mid, fm
+		} else {
+			near, fNear = mid, fm
+		}
+	}
+	if math.Abs(fNear) < math.Abs(fFar) {
+		return finishGoalSeek(result, near, fNear, evals, tol), nil
+	}
+	return finishGoalSeek(result, far, fFar, evals, tol), nil
+}
+
+// searchSide is one direction of the bracket search: the last valid point
+// reached and the limit it may not pass. A failing limit is an invalid trial
+// point rather than a bound the caller set.
+type searchSide struct {
+	x, fx     float64
+	dir       float64 // +1 up, -1 down
+	step      float64
+	limit     float64
+	failed    bool
+	doublings int
+}
+
+// open reports whether the side can still move toward its limit. Doubling
+// steps are capped; halving toward a failing limit stops once the gap is
+// negligible.
+func (s *searchSide) open() bool {
+	gap := s.dir * (s.limit - s.x)
+	if s.failed {
+		return gap > 1e-9*math.Max(1, math.Abs(s.x))
+	}
+	return gap > 0 && s.doublings < maxBracketSteps
+}
+
+// bracket searches outward from start for the nearest point where crossed
+// holds, doubling the step each round, and returns it (far) with the last
+// point before it (near). A failing trial point does not end the search in
+// its direction: it becomes the limit there, and the step is halved toward
+// it until the edge of the valid region is pinned down. Without a bracket,
+// from and to are the valid range searched.
+func bracket(f func(float64) (float64, bool), crossed func(float64) bool, start, f0, lo, hi float64) (near, fNear, far, fFar, from, to float64, ok bool) {
+	step := math.Max(math.Abs(start)*0.1, 0.01)
+	up := &searchSide{x: start, fx: f0, dir: 1, step: step, limit: hi}
+	down := &searchSide{x: start, fx: f0, dir: -1, step: step, limit: lo}
+
+	for up.open() || down.open() {
+		for _, s := range []*searchSide{up, down} {
+			if !s.open() {
+				continue
+			}
+			x := s.x + s.dir*s.step
+			if s.dir*(x-s.limit) >= 0 {
+				x = s.limit
+				if s.failed {
+					x = s.x + (s.limit-s.x)/2
+				}
+			}
+			fx, valid := f(x)
+			switch {
+			case !valid:
+				s.limit, s.failed = x, true
+				s.step = math.Abs(x-s.x) / 2
+			case crossed(fx):
+				return s.x, s.fx, x, fx, 0, 0, true
+			default:
+				s.x, s.fx = x, fx
+				if !s.failed {
+					s.step *= 2
+					s.doublings++
+				}
+			}
+		}
+	}
+
+	from, to = lo, hi
+	if down.failed {
+		from = down.x
+	}
+	if up.failed {
+		to = up.x
+	}
+	return 0, 0, 0, 0, from, to, false
+}
+
+func finishGoalSeek(r *domain.GoalSeekResult, x, fx float64, evals int, tol float64) *domain.GoalSeekResult {
+	r.Solution = x
+	r.AchievedOutput = r.Target + fx
+	r.Converged = math.Abs(fx) <= tol
+	r.Evaluations = evals
+	if r.BaseValue != nil && *r.BaseValue != 0 {
+		change := (x - *r.BaseValue) / math.Abs(*r.BaseValue)
+		r.ChangeFromBase = &change
+	}
+	return r
+}
@@ -0,0 +1,146 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func TestGoalSeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		opts     GoalSeekOptions
+		want     float64
+	}{
+		{
+			// margin = 100 - cost - 20 licensing
+			name:     "break-even direct cost",
+			function: "unit_economics",
+			opts:     GoalSeekOptions{Output: "margin_per_customer", Target: 0, Field: "financials.direct_cost_per_customer"},
+			want:     80,
+		},
+		{
+			name:     "revenue for a target margin %",
+			function: "unit_economics",
+			opts:     GoalSeekOptions{Output: "margin_pct", Target: 0.5, Field: "financials.revenue_per_customer"},
+			want:     140,
+		},
+		{
+			name:     "primary output by default",
+			function: "unit_economics",
+			opts:     GoalSeekOptions{Target: 10, Field: "financials.partner_licensing_cost"},
+			want:     40,
+		},
+		{
+			// Unset field starts from zero: shared cost that wipes out the margin.
+			name:     "unset field",
+			function: "unit_economics",
+			opts:     GoalSeekOptions{Target: 0, Field: "financials.shared_cost_per_customer"},
+			want:     30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GoalSeek(calculators.NewRegistry(), "financial", tt.function, sensitivityInput(), tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(result.Solution-tt.want) > 1e-6 {
+				t.Errorf("Solution = %v, want %v", result.Solution, tt.want)
+			}
+			if !result.Converged || math.Abs(result.AchievedOutput-tt.opts.Target) > 1e-6 {
+				t.Errorf("converged/achieved = %v/%v, want true/%v", result.Converged, result.AchievedOutput, tt.opts.Target)
+			}
+		})
+	}
+}
+
+func TestGoalSeekStepOutput(t *testing.T) {
+	// cost_floor = 50 + 20 = 70; clears_floor flips from 1 to 0 below price 70.
+	input := sensitivityInput()
+	result, err := GoalSeek(calculators.NewRegistry(), "pricing", "cost_floor", input, GoalSeekOptions{
+		Output: "clears_floor", Target: 0, Field: "product.price",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(result.Solution-70) > 1e-6 || result.AchievedOutput != 0 || !result.Converged {
+		t.Errorf("threshold = %v (output %v), want just below 70", result.Solution, result.AchievedOutput)
+	}
+	if result.BaseValue == nil || *result.BaseValue != 100 || result.BaseOutput == nil || *result.BaseOutput != 1 {
+		t.Errorf("base = %v/%v, want 100/1", result.BaseValue, result.BaseOutput)
+	}
+	if result.ChangeFromBase == nil || math.Abs(*result.ChangeFromBase+0.3) > 1e-6 {
+		t.Errorf("ChangeFromBase = %v, want -0.3", result.ChangeFromBase)
+	}
+	if input.Product.Price != 100 {
+		t.Error("GoalSeek modified the input")
+	}
+}
+
+func TestGoalSeekNearInvalidRegion(t *testing.T) {
+	// monthly_churn above 1 fails; the expanding search overshoots to 1.32
+	// and must close in on the edge to find a root at 0.95.
+	input := sensitivityInput()
+	input.Financials.GrossMarginPct = ptr(0.5)
+	input.Financials.CLVModel = &domain.CLVModel{MonthlyChurn: ptr(0.05), DiscountRate: ptr(0)}
+	registry := calculators.NewRegistry()
+	target, err := runWith(registry, "financial", "clv_advanced", input, "clv", "financials.clv_model.monthly_churn", 0.95)
+	if err != nil {
+		t.Fatalf("target: %v", err)
+	}
+
+	result, err := GoalSeek(registry, "financial", "clv_advanced", input, GoalSeekOptions{
+		Target: target, Field: "financials.clv_model.monthly_churn",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(result.Solution-0.95) > 1e-6 || !result.Converged {
+		t.Errorf("Solution = %v (converged %v), want 0.95", result.Solution, result.Converged)
+	}
+
+	// Out of reach: the error names the valid range searched, not [0, +Inf].
+	_, err = GoalSeek(registry, "financial", "clv_advanced", input, GoalSeekOptions{
+		Target: -1, Field: "financials.clv_model.monthly_churn",
+	})
+	if err == nil || !strings.Contains(err.Error(), "in [0, 1]") {
+		t.Errorf("error = %v, want range [0, 1]", err)
+	}
+}
+
+func TestGoalSeekErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    GoalSeekOptions
+		wantErr string
+	}{
+		{"no field", GoalSeekOptions{Target: 0}, "field to vary required"},
+		{"bad bounds", GoalSeekOptions{Field: "financials.cogs", Min: ptr(5), Max: ptr(1)}, "min must be below max"},
+		{"unknown field", GoalSeekOptions{Field: "financials.nope"}, "unknown field"},
+		{
+			"unreachable",
+			GoalSeekOptions{Output: "margin_pct", Target: 2, Field: "financials.revenue_per_customer"},
+			"no value of financials.revenue_per_customer",
+		},
+		{
+			"bounded out of reach",
+			GoalSeekOptions{Target: 0, Field: "financials.direct_cost_per_customer", Max: ptr(60)},
+			"in [0, 60]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GoalSeek(calculators.NewRegistry(), "financial", "unit_economics", sensitivityInput(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// BaseScenario is the name of the unpatched input in scenario comparisons.
+const BaseScenario = "base"
+
+// Scenarios runs module.function on the base input and on every scenario in
+// input.Scenarios, and collects the given outputs (JSON paths into the
+// result) for side-by-side comparison. With no outputs, every top-level
+// numeric field of the base result is compared, falling back to the
+// function's primary output. A failing scenario is reported, not fatal.
+func Scenarios(registry *calculators.Registry, module, function string, input *domain.AppraisalInput, outputs []string) (*domain.ScenarioResult, error) {
+	if len(input.Scenarios) == 0 {
+		return nil, fmt.Errorf("input has no scenarios")
+	}
+	seen := map[string]bool{BaseScenario: true}
+	for _, s := range input.Scenarios {
+		if s.Name == "" {
+			return nil, fmt.Errorf("every scenario needs a name")
+		}
+		if seen[s.Name] {
+			if s.Name == BaseScenario {
+				return nil, fmt.Errorf("scenario name %q is reserved for the unpatched input", BaseScenario)
+			}
+			return nil, fmt.Errorf("duplicate scenario %q", s.Name)
+		}
+		seen[s.Name] = true
+	}
+
+	base, err := cloneInput(input)
+	if err != nil {
+		return nil, err
+	}
+	base.Scenarios = nil
+
+	baseResult, err := registry.Execute(module, function, base)
+	if err != nil {
+		return nil, fmt.Errorf("base run: %w", err)
+	}
+	if len(outputs) == 0 {
+		if outputs, err = numericFields(baseResult); err != nil {
+			return nil, err
+		}
+		if len(outputs) == 0 {
+			outputs = []string{registry.PrimaryOutput(module, function)}
+		}
+	}
+
+	result := &domain.ScenarioResult{
+		Module:   module,
+		Function: function,
+		Outputs:  outputs,
+	}
+	baseOutcome := collect(BaseScenario, baseResult, outputs, nil)
+	if baseOutcome.Error != "" {
+		return nil, fmt.Errorf("base run: %s", baseOutcome.Error)
+	}
+	result.Scenarios = append(result.Scenarios, baseOutcome)
+
+	for _, s := range input.Scenarios {
+		patched, err := ApplyScenario(base, s)
+		if err != nil {
+			return nil, err
+		}
+		r, err := registry.Execute(module, function, patched)
+		if err != nil {
+			result.Scenarios = append(result.Scenarios, domain.ScenarioOutcome{Name: s.Name, Error: err.Error()})
+			continue
+		}
+		result.Scenarios = append(result.Scenarios, collect(s.Name, r, outputs, baseOutcome.Values))
+	}
+	return result, nil
+}
+
+// ApplyScenario returns a copy of base with the scenario's merge patch
+// applied. Patched fields must exist in the input schema.
+func ApplyScenario(base *domain.AppraisalInput, s domain.Scenario) (*domain.AppraisalInput, error) {
+	data, err := json.Marshal(base)
+	if err != nil {
+		return nil, fmt.Errorf("encoding input: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decoding input: %w", err)
+	}
+
+	patched, err := json.Marshal(mergePatch(doc, s.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("scenario %q: %w", s.Name, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	var out domain.AppraisalInput
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("scenario %q: %w", s.Name, err)
+	}
+	out.Scenarios = nil
+	return &out, nil
+}
+
+// mergePatch applies an RFC 7386 JSON merge patch to target.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+// collect extracts outputs from result. Outputs a scenario does not produce
+// (e.g. an optional field left unset) are skipped.
+func collect(name string, result interface{}, outputs []string, base map[string]float64) domain.ScenarioOutcome {
+	outcome := domain.ScenarioOutcome{Name: name}
+	var missing []string
+	for _, path := range outputs {
+		v, err := OutputValue(result, path)
+		if err != nil {
+			missing = append(missing, path)
+			continue
+		}
+		if outcome.Values == nil {
+			outcome.Values = map[string]float64{}
+		}
+		outcome.Values[path] = v
+		if b, ok := base[path]; ok && b != 0 {
+			if outcome.Changes == nil {
+				outcome.Changes = map[string]float64{}
+			}
+			outcome.Changes[path] = (v - b) / math.Abs(b)
+		}
+	}
+	if outcome.Values == nil {
+		outcome.Error = "no output available: " + strings.Join(missing, ", ")
+	}
+	return outcome
+}
+
+// numericFields lists the top-level numeric fields of a result, in field
+// order. Non-object results yield none.
+func numericFields(result interface{}) ([]string, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("encoding result: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil
+	}
+	var fields []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("decoding result: %w", err)
+		}
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("decoding result: %w", err)
+		}
+		if _, ok := value.(json.Number); ok {
+			fields = append(fields, tok.(string))
+		}
+	}
+	return fields, nil
+}
+
+// ScenarioTable writes a comparison table: one row per output, one column per
+// scenario, with the change against base in parentheses.
+func ScenarioTable(w io.Writer, r *domain.ScenarioResult) error {
+	header := []string{"output"}
+	for _, s := range r.Scenarios {
+		header = append(header, s.Name)
+	}
+	rows := [][]string{header}
+	for _, path := range r.Outputs {
+		row := []string{path}
+		for _, s := range r.Scenarios {
+			v, ok := s.Values[path]
+			switch {
+			case s.Error != "":
+				row = append(row, "error")
+			case !ok:
+				row = append(row, "-")
+			case s.Name == BaseScenario:
+				row = append(row, formatNumber(v))
+			default:
+				cell := formatNumber(v)
+				if c, ok := s.Changes[path]; ok && c != 0 {
+					cell += fmt.Sprintf(" (%+.1f%%)", c*100)
+				}
+				row = append(row, cell)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%s.%s\n\n", r.Module, r.Function); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			if i == 0 {
+				fmt.Fprintf(&b, "%-*s", widths[i], cell)
+			} else {
+				fmt.Fprintf(&b, "  %*s", widths[i], cell)
+			}
+		}
+		if _, err := fmt.Fprintln(w, b.String()); err != nil {
+			return err
+		}
+	}
+	for _, s := range r.Scenarios {
+		if s.Error != "" {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", s.Name, s.Error); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
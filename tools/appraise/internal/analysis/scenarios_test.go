@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Cases from RFC 7386 Appendix A.
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.patch, func(t *testing.T) {
+			var target, patch, want interface{}
+			for _, p := range []struct {
+				src string
+				dst *interface{}
+			}{{tt.target, &target}, {tt.patch, &patch}, {tt.want, &want}} {
+				if err := json.Unmarshal([]byte(p.src), p.dst); err != nil {
+					t.Fatalf("bad fixture %s: %v", p.src, err)
+				}
+			}
+			if got := mergePatch(target, patch); !reflect.DeepEqual(got, want) {
+				t.Errorf("mergePatch(%s, %s) = %v, want %s", tt.target, tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	input := sensitivityInput()
+	input.Scenarios = []domain.Scenario{
+		{Name: "optimistic", Patch: map[string]interface{}{
+			"financials": map[string]interface{}{"revenue_per_customer": 120.0},
+		}},
+		{Name: "pessimistic", Patch: map[string]interface{}{
+			"financials": map[string]interface{}{"revenue_per_customer": 80.0, "partner_licensing_cost": nil},
+		}},
+		{Name: "broken", Patch: map[string]interface{}{"financials": nil}},
+	}
+
+	result, err := Scenarios(calculators.NewRegistry(), "financial", "unit_economics", input, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantOutputs := []string{"revenue_per_customer", "cost_per_customer", "margin_per_customer", "margin_pct"}
+	if !reflect.DeepEqual(result.Outputs, wantOutputs) {
+		t.Errorf("Outputs = %v, want %v", result.Outputs, wantOutputs)
+	}
+	if len(result.Scenarios) != 4 {
+		t.Fatalf("got %d scenarios, want base + 3", len(result.Scenarios))
+	}
+
+	base, opt, pess, broken := result.Scenarios[0], result.Scenarios[1], result.Scenarios[2], result.Scenarios[3]
+	if base.Name != BaseScenario || base.Values["margin_per_customer"] != 30 || base.Changes != nil {
+		t.Errorf("base = %+v, want margin 30 and no changes", base)
+	}
+	if !almostEqual(opt.Values["margin_per_customer"], 50) || !almostEqual(opt.Changes["margin_per_customer"], 2.0/3) {
+		t.Errorf("optimistic margin = %v (%v), want 50 (+66.7%%)", opt.Values["margin_per_customer"], opt.Changes["margin_per_customer"])
+	}
+	if !almostEqual(pess.Values["margin_per_customer"], 30) || !almostEqual(pess.Values["cost_per_customer"], 50) {
+		t.Errorf("pessimistic = %+v, want margin 30 and cost 50 (licensing removed)", pess.Values)
+	}
+	if broken.Error == "" || broken.Values != nil {
+		t.Errorf("broken = %+v, want an error", broken)
+	}
+
+	// Input is not modified by the runs.
+	if *input.Financials.RevenuePerCustomer != 100 || input.Financials.PartnerLicensingCost == nil {
+		t.Error("Scenarios modified the input")
+	}
+
+	var buf bytes.Buffer
+	if err := ScenarioTable(&buf, result); err != nil {
+		t.Fatalf("ScenarioTable: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"optimistic", "50 (+66.7%)", "broken: financial data required"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+
+	// Explicit outputs
+	result, err = Scenarios(calculators.NewRegistry(), "financial", "unit_economics", input, []string{"margin_pct"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Outputs, []string{"margin_pct"}) || len(result.Scenarios[1].Values) != 1 {
+		t.Errorf("explicit outputs = %v / %v", result.Outputs, result.Scenarios[1].Values)
+	}
+}
+
+func TestScenariosErrors(t *testing.T) {
+	patch := map[string]interface{}{"financials": map[string]interface{}{"cogs": 1.0}}
+
+	tests := []struct {
+		name      string
+		scenarios []domain.Scenario
+		wantErr   string
+	}{
+		{"none", nil, "no scenarios"},
+		{"unnamed", []domain.Scenario{{Patch: patch}}, "needs a name"},
+		{"reserved", []domain.Scenario{{Name: "base", Patch: patch}}, "reserved"},
+		{"duplicate", []domain.Scenario{{Name: "a", Patch: patch}, {Name: "a", Patch: patch}}, "duplicate scenario"},
+		{
+			"unknown field",
+			[]domain.Scenario{{Name: "typo", Patch: map[string]interface{}{"financials": map[string]interface{}{"cogz": 1.0}}}},
+			`unknown field "cogz"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := sensitivityInput()
+			input.Scenarios = tt.scenarios
+			_, err := Scenarios(calculators.NewRegistry(), "financial", "unit_economics", input, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/fieldpath"
+)
+
+// DefaultSensitivityRange is the default perturbation (±20%).
+const DefaultSensitivityRange = 0.2
+
+// SensitivityOptions configures a sensitivity run.
+type SensitivityOptions struct {
+	Output string   // JSON path of the output; default: the function's primary output
+	Range  float64  // relative perturbation, e.g. 0.2 for ±20%
+	Fields []string // input paths to vary; nil varies every set numeric field
+}
+
+// Sensitivity perturbs each input field by ±Range, reruns module.function for
+// both values, and ranks fields by the swing in the output (tornado data).
+// Fields whose perturbation leaves the output unchanged are listed separately.
+func Sensitivity(registry *calculators.Registry, module, function string, input *domain.AppraisalInput, opts SensitivityOptions) (*domain.SensitivityResult, error) {
+	r := opts.Range
+	if r == 0 {
+		r = DefaultSensitivityRange
+	}
+	if r < 0 || r > 1 {
+		return nil, fmt.Errorf("range must be between 0 and 1")
+	}
+	output := opts.Output
+	if output == "" {
+		output = registry.PrimaryOutput(module, function)
+	}
+
+	base, err := run(registry, module, function, input, output)
+	if err != nil {
+		return nil, fmt.Errorf("base run: %w", err)
+	}
+
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = fieldpath.List(input)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("input has no numeric fields to vary")
+	}
+
+	result := &domain.SensitivityResult{
+		Module:     module,
+		Function:   function,
+		Output:     output,
+		BaseOutput: base,
+		Range:      r,
+	}
+
+	for _, field := range fields {
+		value, err := fieldpath.Get(input, field)
+		if err != nil {
+			return nil, err
+		}
+		row := domain.SensitivityInput{
+			Field:     field,
+			BaseValue: value,
+			LowValue:  value * (1 - r),
+			HighValue: value * (1 + r),
+		}
+
+		var errs []string
+		if out, err := runWith(registry, module, function, input, output, field, row.LowValue); err == nil {
+			row.LowOutput = &out
+		} else {
+			errs = append(errs, fmt.Sprintf("-%.4g%%: %v", r*100, err))
+		}
+		if out, err := runWith(registry, module, function, input, output, field, row.HighValue); err == nil {
+			row.HighOutput = &out
+		} else {
+			errs = append(errs, fmt.Sprintf("+%.4g%%: %v", r*100, err))
+		}
+		row.Error = strings.Join(errs, "; ")
+
+		switch {
+		case row.LowOutput != nil && row.HighOutput != nil:
+			row.Swing = math.Abs(*row.HighOutput - *row.LowOutput)
+			if base != 0 {
+				e := (*row.HighOutput - *row.LowOutput) / math.Abs(base) / (2 * r)
+				row.Elasticity = &e
+			}
+		case row.LowOutput != nil:
+			row.Swing = math.Abs(*row.LowOutput - base)
+		case row.HighOutput != nil:
+			row.Swing = math.Abs(*row.HighOutput - base)
+		}
+
+		if row.Swing == 0 && row.Error == "" {
+			result.Unaffected = append(result.Unaffected, field)
+			continue
+		}
+		result.Inputs = append(result.Inputs, row)
+	}
+
+	sort.SliceStable(result.Inputs, func(i, j int) bool {
+		return result.Inputs[i].Swing > result.Inputs[j].Swing
+	})
+	for i := range result.Inputs {
+		result.Inputs[i].Rank = i + 1
+	}
+	return result, nil
+}
+
+// runWith runs module.function with one field set to value.
+func runWith(registry *calculators.Registry, module, function string, input *domain.AppraisalInput, output, field string, value float64) (float64, error) {
+	in, err := cloneInput(input)
+	if err != nil {
+		return 0, err
+	}
+	if err := fieldpath.Set(in, field, value); err != nil {
+		return 0, err
+	}
+	return run(registry, module, function, in, output)
+}
+
+// Tornado writes an ASCII tornado chart of a sensitivity result. Bars extend
+// left and right of the base output; '-' marks the effect of lowering the
+// input, '+' the effect of raising it. width is the total bar width.
+func Tornado(w io.Writer, r *domain.SensitivityResult, width int) error {
+	if width < 10 {
+		width = 10
+	}
+	half := width / 2
+
+	label := len("input")
+	maxDev := 0.0
+	for _, in := range r.Inputs {
+		label = max(label, len(in.Field))
+		for _, out := range []*float64{in.LowOutput, in.HighOutput} {
+			if out != nil {
+				maxDev = math.Max(maxDev, math.Abs(*out-r.BaseOutput))
+			}
+		}
+	}
+
+	pct := r.Range * 100
+	if _, err := fmt.Fprintf(w, "%s.%s  %s  (base %s, inputs ±%.4g%%)\n\n",
+		r.Module, r.Function, r.Output, formatNumber(r.BaseOutput), pct); err != nil {
+		return err
+	}
+
+	for _, in := range r.Inputs {
+		left, right := bars(in, r.BaseOutput, maxDev, half)
+		if _, err := fmt.Fprintf(w, "%-*s %12s %*s|%-*s %s\n",
+			label, in.Field, outputLabel(in.LowOutput), half, left, half, right, outputLabel(in.HighOutput)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "\n%-*s %12s %*s%s\n", label, "", "", half, "", "^ base"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "- = input -%.4g%%   + = input +%.4g%%   (left column: output at -%.4g%%, right: at +%.4g%%)\n", pct, pct, pct, pct); err != nil {
+		return err
+	}
+	if len(r.Unaffected) > 0 {
+		if _, err := fmt.Fprintf(w, "no effect: %s\n", strings.Join(r.Unaffected, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bars returns the left (below base) and right (above base) bar strings.
+func bars(in domain.SensitivityInput, base, maxDev float64, half int) (string, string) {
+	if maxDev == 0 {
+		return "", ""
+	}
+	var leftLen, rightLen int
+	var leftChar, rightChar byte = ' ', ' '
+	for _, side := range []struct {
+		out  *float64
+		char byte
+	}{{in.LowOutput, '-'}, {in.HighOutput, '+'}} {
+		if side.out == nil {
+			continue
+		}
+		dev := *side.out - base
+		n := int(math.Round(math.Abs(dev) / maxDev * float64(half)))
+		if dev < 0 && n > leftLen {
+			leftLen, leftChar = n, side.char
+		}
+		if dev > 0 && n > rightLen {
+			rightLen, rightChar = n, side.char
+		}
+	}
+	return strings.Repeat(string(leftChar), leftLen), strings.Repeat(string(rightChar), rightLen)
+}
+
+func outputLabel(v *float64) string {
+	if v == nil {
+		return "error"
+	}
+	return formatNumber(*v)
+}
+
+func formatNumber(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e12 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.4g", v)
+}
@@ -0,0 +1,144 @@
+package analysis
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/calculators"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func sensitivityInput() *domain.AppraisalInput {
+	return &domain.AppraisalInput{
+		Product: &domain.ProductDefinition{Name: "Bundle", Price: 100},
+		Financials: &domain.FinancialData{
+			AverageCustomerCount:  ptr(100),
+			RevenuePerCustomer:    ptr(100),
+			DirectCostPerCustomer: ptr(50),
+			PartnerLicensingCost:  ptr(20),
+			FixedCosts:            ptr(10000),
+		},
+	}
+}
+
+func TestSensitivity(t *testing.T) {
+	result, err := Sensitivity(calculators.NewRegistry(), "financial", "unit_economics", sensitivityInput(), SensitivityOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Output != "margin_per_customer" || result.BaseOutput != 30 || result.Range != DefaultSensitivityRange {
+		t.Errorf("output/base/range = %s/%v/%v, want margin_per_customer/30/0.2", result.Output, result.BaseOutput, result.Range)
+	}
+
+	want := []struct {
+		field       string
+		low, high   float64
+		swing, elas float64
+	}{
+		{"financials.revenue_per_customer", 10, 50, 40, 40.0 / 30 / 0.4},
+		{"financials.direct_cost_per_customer", 40, 20, 20, -20.0 / 30 / 0.4},
+		{"financials.partner_licensing_cost", 34, 26, 8, -8.0 / 30 / 0.4},
+	}
+	if len(result.Inputs) != len(want) {
+		t.Fatalf("got %d ranked inputs, want %d: %+v", len(result.Inputs), len(want), result.Inputs)
+	}
+	for i, w := range want {
+		in := result.Inputs[i]
+		if in.Rank != i+1 || in.Field != w.field {
+			t.Errorf("rank %d = %s, want %s", in.Rank, in.Field, w.field)
+		}
+		if in.LowOutput == nil || in.HighOutput == nil ||
+			math.Abs(*in.LowOutput-w.low) > 1e-9 || math.Abs(*in.HighOutput-w.high) > 1e-9 {
+			t.Errorf("%s low/high = %v/%v, want %v/%v", in.Field, in.LowOutput, in.HighOutput, w.low, w.high)
+		}
+		if math.Abs(in.Swing-w.swing) > 1e-9 {
+			t.Errorf("%s swing = %v, want %v", in.Field, in.Swing, w.swing)
+		}
+		if in.Elasticity == nil || math.Abs(*in.Elasticity-w.elas) > 1e-9 {
+			t.Errorf("%s elasticity = %v, want %v", in.Field, in.Elasticity, w.elas)
+		}
+	}
+
+	unaffected := strings.Join(result.Unaffected, ",")
+	if unaffected != "product.price,financials.fixed_costs,financials.average_customer_count" {
+		t.Errorf("Unaffected = %v", result.Unaffected)
+	}
+}
+
+func TestSensitivityFieldsAndErrors(t *testing.T) {
+	registry := calculators.NewRegistry()
+
+	// Lowering the price below variable cost makes break_even fail.
+	input := sensitivityInput()
+	input.Financials.VariableCostPerUnit = ptr(90)
+	result, err := Sensitivity(registry, "financial", "break_even", input, SensitivityOptions{
+		Fields: []string{"product.price"},
+		Range:  0.5,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	in := result.Inputs[0]
+	if in.LowOutput != nil || in.HighOutput == nil || !strings.Contains(in.Error, "contribution margin") {
+		t.Errorf("row = %+v, want low run error and high output", in)
+	}
+	// Base 10000/10 = 1000 units; at price 150: 10000/60
+	if math.Abs(in.Swing-(1000-10000.0/60)) > 1e-9 {
+		t.Errorf("swing = %v, want %v", in.Swing, 1000-10000.0/60)
+	}
+
+	tests := []struct {
+		name    string
+		module  string
+		opts    SensitivityOptions
+		wantErr string
+	}{
+		{"bad range", "financial", SensitivityOptions{Range: 1.5}, "range must be between"},
+		{"unset field", "financial", SensitivityOptions{Fields: []string{"financials.cogs"}}, "not set"},
+		{"unknown output", "financial", SensitivityOptions{Output: "nope"}, "no field"},
+		{"base run fails", "pricing", SensitivityOptions{}, "base run"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := "unit_economics"
+			if tt.module == "pricing" {
+				fn = "bvr"
+			}
+			_, err := Sensitivity(registry, tt.module, fn, sensitivityInput(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTornado(t *testing.T) {
+	result, err := Sensitivity(calculators.NewRegistry(), "financial", "unit_economics", sensitivityInput(), SensitivityOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Tornado(&buf, result, 20); err != nil {
+		t.Fatalf("Tornado: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+
+	if !strings.HasPrefix(lines[0], "financial.unit_economics  margin_per_customer  (base 30, inputs ±20%)") {
+		t.Errorf("header = %q", lines[0])
+	}
+	// Largest swing gets the full half-width on both sides.
+	if !strings.Contains(lines[2], "----------|++++++++++ 50") {
+		t.Errorf("revenue row = %q", lines[2])
+	}
+	// Cost rows are mirrored: raising cost lowers the output.
+	if !strings.Contains(lines[3], "+++++|-----") {
+		t.Errorf("cost row = %q", lines[3])
+	}
+	if !strings.Contains(buf.String(), "no effect: product.price") {
+		t.Errorf("missing unaffected line:\n%s", buf.String())
+	}
+}
@@ -6,12 +6,22 @@
 //   CrossSubsidyAnalysis - Net margin flows between high/low margin components
 //   ComponentActivation  - Share activating each component within 30 days
 //   MultiComponentUsage  - Share of customers using 3+ components
+//   CrossSubsidyStress   - Cross-subsidy sustainability under usage/price shocks
+//   Swappable            - "Pick K of N" usage, cost exposure, and BVR range
+//   RemovalImpact        - Ranked "what if we drop component X" analysis
+//   OverProvisioning     - Wasted allowance cost and right-sized allowances
+//   PartnerCostRatio     - Partner/licensing cost vs premium revenue delta, partner concentration
+//   Engagement           - Activation, monthly active rate, frequency, depth from raw usage events
 package bundle
 
 import (
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/stats"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
 )
 
 // Calculator implements all bundle module functions.
@@ -121,12 +131,7 @@ func (c *Calculator) DeadWeightRatio(input *domain.AppraisalInput) (*domain.Dead
 
 	deadCount := 0
 	for _, comp := range input.Components {
-		usage := 0.0
-		if comp.MonthlyActiveRate != nil {
-			usage = *comp.MonthlyActiveRate
-		} else if comp.UsageForecast != nil {
-			usage = *comp.UsageForecast
-		}
+		usage := componentUsage(comp)
 
 		result.ComponentUsage[comp.Name] = usage
 
@@ -158,12 +163,7 @@ func (c *Calculator) CrossSubsidyAnalysis(input *domain.AppraisalInput) (*domain
 		if comp.RevenueContrib != nil {
 			rev = *comp.RevenueContrib
 		}
-		cost := 0.0
-		if comp.DirectCost != nil {
-			cost = *comp.DirectCost
-		} else if comp.MarginalCost != nil {
-			cost = *comp.MarginalCost
-		}
+		cost := componentCost(comp)
 
 		margin := rev - cost
 		totalMargin += margin
@@ -190,6 +190,114 @@ func (c *Calculator) CrossSubsidyAnalysis(input *domain.AppraisalInput) (*domain
 	return result, nil
 }
 
+// CrossSubsidyStress applies per-component usage and price shocks to the
+// cross-subsidy model and finds where net margin turns negative.
+//
+// Usage shocks scale component cost (cost * (1 + usage_shock)); price shocks
+// scale component revenue (revenue * (1 + price_shock)). If no component sets
+// a shock, subsidy recipients are stressed at 2x usage ("usage exceeds
+// forecast by 2x" scenario).
+//
+// Net margin is linear in the shocks, so break points are exact:
+//   shock_break_point          = M0 / (M0 - M_stressed), as a multiple of the given shocks
+//   recipient_usage_break_even = M0 / recipient cost
+//   source_price_break_even    = M0 / source revenue (nil if > 100%)
+// where M0 is the unstressed net margin. All break points are 0 when M0 <= 0.
+func (c *Calculator) CrossSubsidyStress(input *domain.AppraisalInput) (*domain.CrossSubsidyStressResult, error) {
+	base, err := c.CrossSubsidyAnalysis(input)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultShocks := true
+	for _, comp := range input.Components {
+		if comp.UsageShock != nil || comp.PriceShock != nil {
+			defaultShocks = false
+			break
+		}
+	}
+
+	// Roles are looked up by component name.
+	seen := make(map[string]bool, len(input.Components))
+	for _, comp := range input.Components {
+		if seen[comp.Name] {
+			return nil, fmt.Errorf("duplicate component %q", comp.Name)
+		}
+		seen[comp.Name] = true
+	}
+
+	roles := make(map[string]domain.CrossSubsidyComponent)
+	sourceRevenue, recipientCost := 0.0, 0.0
+	for _, csc := range base.Sources {
+		roles[csc.Name] = csc
+		sourceRevenue += csc.Revenue
+	}
+	for _, csc := range base.Recipients {
+		roles[csc.Name] = csc
+		recipientCost += csc.Cost
+	}
+
+	m0 := base.NetMargin
+	result := &domain.CrossSubsidyStressResult{
+		BaseNetMargin: m0,
+		DefaultShocks: defaultShocks,
+	}
+
+	for _, comp := range input.Components {
+		csc := roles[comp.Name]
+		sc := domain.CrossSubsidyStressComponent{
+			Name:       comp.Name,
+			Role:       csc.Role,
+			BaseMargin: csc.NetMargin,
+		}
+		switch {
+		case defaultShocks && csc.Role == "recipient":
+			sc.UsageShock = 1.0
+		case !defaultShocks:
+			if comp.UsageShock != nil {
+				sc.UsageShock = *comp.UsageShock
+			}
+			if comp.PriceShock != nil {
+				sc.PriceShock = *comp.PriceShock
+			}
+		}
+
+		sc.StressedRevenue = csc.Revenue * (1.0 + sc.PriceShock)
+		sc.StressedCost = csc.Cost * (1.0 + sc.UsageShock)
+		sc.StressedMargin = sc.StressedRevenue - sc.StressedCost
+		sc.UsageBreakPoint = breakPoint(m0, csc.Cost, false)
+		sc.PriceBreakPoint = breakPoint(m0, csc.Revenue, true)
+
+		result.StressedNetMargin += sc.StressedMargin
+		result.Components = append(result.Components, sc)
+	}
+
+	result.SurvivesStress = result.StressedNetMargin > 0
+	result.RecipientUsageBreakEven = breakPoint(m0, recipientCost, false)
+	result.SourcePriceBreakEven = breakPoint(m0, sourceRevenue, true)
+	result.ShockBreakPoint = breakPoint(m0, m0-result.StressedNetMargin, false)
+
+	return result, nil
+}
+
+// breakPoint returns the shock size at which margin m0 is fully eroded by a
+// linear loss of `exposure` per unit of shock. Returns nil if the margin is
+// never eroded (no exposure) or, for price shocks, if it needs a drop >100%.
+func breakPoint(m0, exposure float64, capAtOne bool) *float64 {
+	if m0 <= 0 {
+		zero := 0.0
+		return &zero
+	}
+	if exposure <= 0 {
+		return nil
+	}
+	bp := m0 / exposure
+	if capAtOne && bp > 1.0 {
+		return nil
+	}
+	return &bp
+}
+
 // ComponentActivation calculates the share of customers activating each component
 // within 30 days. Returns per-component activation rates.
 // Target: >70% for Leaders, >40% for Fillers.
@@ -252,3 +360,633 @@ func (c *Calculator) MultiComponentUsage(input *domain.AppraisalInput) (*domain.
 		Interpretation: interp,
 	}, nil
 }
+
+// Swappable evaluates a "pick K of N" bundle: a fixed core plus a choice layer
+// from which each customer selects product.swap_picks components.
+//
+// Customers are assumed to pick the components they would use. Pick share is
+// proportional to forced-inclusion usage (capped at 100%), and usage among
+// pickers = usage / pick share (capped at 100%). Expected dead weight ratio is
+// the expected share of a customer's components below 20% usage.
+// Cost exposure: best/worst case = fixed cost + cheapest/most expensive K picks.
+// BVR range = (fixed standalone + lowest/highest K standalone prices) / price.
+func (c *Calculator) Swappable(input *domain.AppraisalInput) (*domain.SwappableResult, error) {
+	if len(input.Components) == 0 {
+		return nil, fmt.Errorf("component data required")
+	}
+	if input.Product == nil {
+		return nil, fmt.Errorf("product definition required")
+	}
+	if input.Product.Price <= 0 {
+		return nil, fmt.Errorf("product price must be positive")
+	}
+
+	const usageThreshold = 0.20
+	const ratioThreshold = 0.40
+
+	var fixed, swappable []domain.ComponentData
+	for _, comp := range input.Components {
+		if isSwappable(input, comp) {
+			swappable = append(swappable, comp)
+		} else {
+			fixed = append(fixed, comp)
+		}
+	}
+	if len(swappable) == 0 {
+		return nil, fmt.Errorf("at least one swappable component required (is_swappable)")
+	}
+	if input.Product.SwapPicks == nil {
+		return nil, fmt.Errorf("product.swap_picks required")
+	}
+	k := *input.Product.SwapPicks
+	if k < 1 || k > len(swappable) {
+		return nil, fmt.Errorf("swap_picks must be between 1 and %d, got %d", len(swappable), k)
+	}
+
+	result := &domain.SwappableResult{
+		FixedComponents:     len(fixed),
+		SwappableComponents: len(swappable),
+		Picks:               k,
+		Threshold:           ratioThreshold,
+		BundlePrice:         input.Product.Price,
+	}
+
+	fixedCost, fixedStandalone, fixedDead := 0.0, 0.0, 0
+	for _, comp := range fixed {
+		fixedCost += componentCost(comp)
+		fixedStandalone += standalonePrice(input, comp)
+		if componentUsage(comp) < usageThreshold {
+			fixedDead++
+		}
+	}
+
+	usages := make([]float64, len(swappable))
+	for i, comp := range swappable {
+		usages[i] = componentUsage(comp)
+	}
+	shares := pickShares(usages, k)
+
+	forcedDead := fixedDead
+	expectedDead := float64(fixedDead)
+	costs := make([]float64, len(swappable))
+	prices := make([]float64, len(swappable))
+	expectedCost, expectedStandalone := fixedCost, fixedStandalone
+
+	for i, comp := range swappable {
+		costs[i] = componentCost(comp)
+		prices[i] = standalonePrice(input, comp)
+
+		usageWhenPicked := 0.0
+		if shares[i] > 0 {
+			usageWhenPicked = usages[i] / shares[i]
+			if usageWhenPicked > 1.0 {
+				usageWhenPicked = 1.0
+			}
+		}
+		dead := usageWhenPicked < usageThreshold
+
+		if usages[i] < usageThreshold {
+			forcedDead++
+		}
+		if dead {
+			expectedDead += shares[i]
+		}
+		expectedCost += shares[i] * costs[i]
+		expectedStandalone += shares[i] * prices[i]
+
+		result.Slots = append(result.Slots, domain.SwappableSlot{
+			Name:            comp.Name,
+			UsageRate:       usages[i],
+			PickShare:       shares[i],
+			UsageWhenPicked: usageWhenPicked,
+			Cost:            costs[i],
+			StandalonePrice: prices[i],
+			DeadWeight:      dead,
+		})
+	}
+
+	result.ForcedDeadWeightRatio = float64(forcedDead) / float64(len(input.Components))
+	result.ExpectedDeadWeightRatio = expectedDead / float64(len(fixed)+k)
+	result.Passes = result.ExpectedDeadWeightRatio < ratioThreshold
+
+	result.ExpectedCost = expectedCost
+	result.BestCaseCost = fixedCost + sumExtreme(costs, k, false)
+	result.WorstCaseCost = fixedCost + sumExtreme(costs, k, true)
+	result.WorstCaseMargin = input.Product.Price - result.WorstCaseCost
+
+	result.BVRMin = (fixedStandalone + sumExtreme(prices, k, false)) / input.Product.Price
+	result.BVRExpected = expectedStandalone / input.Product.Price
+	result.BVRMax = (fixedStandalone + sumExtreme(prices, k, true)) / input.Product.Price
+
+	return result, nil
+}
+
+// RemovalImpact recomputes the bundle without each component in turn:
+// BVR, bundle discount, cost floor margin, dead weight ratio, and L/F/K counts.
+//
+// Removing a component drops it from both component data and the product
+// definition, subtracts its cost from direct_cost_per_customer (when set), and
+// applies its removal_price_delta to the bundle price.
+// Candidates are ranked killers first, then fillers, then leaders; within a
+// class by cost floor margin gained, then by smallest BVR loss.
+func (c *Calculator) RemovalImpact(input *domain.AppraisalInput) (*domain.RemovalImpactResult, error) {
+	if len(input.Components) < 2 {
+		return nil, fmt.Errorf("at least 2 components required for removal analysis")
+	}
+
+	lfk, err := c.ClassifyComponents(input)
+	if err != nil {
+		return nil, err
+	}
+	classOf := make(map[string]string)
+	for _, cls := range lfk.Classifications {
+		classOf[cls.Name] = cls.Classification
+	}
+
+	baseline, err := c.removalScenario(input)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.RemovalImpactResult{Baseline: *baseline}
+
+	for _, comp := range input.Components {
+		scenario, err := c.removalScenario(withoutComponent(input, comp))
+		if err != nil {
+			return nil, fmt.Errorf("removing %q: %w", comp.Name, err)
+		}
+
+		cand := domain.RemovalCandidate{
+			Component:       comp.Name,
+			Classification:  classOf[comp.Name],
+			RemovalScenario: *scenario,
+			DeadWeightDelta: scenario.DeadWeightRatio - baseline.DeadWeightRatio,
+		}
+		cand.BVRDelta = delta(scenario.BVR, baseline.BVR)
+		cand.BundleDiscountDelta = delta(scenario.BundleDiscount, baseline.BundleDiscount)
+		cand.CostFloorMarginDelta = delta(scenario.CostFloorMargin, baseline.CostFloorMargin)
+
+		result.Candidates = append(result.Candidates, cand)
+	}
+
+	classRank := map[string]int{"killer": 0, "filler": 1, "leader": 2}
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		a, b := result.Candidates[i], result.Candidates[j]
+		if classRank[a.Classification] != classRank[b.Classification] {
+			return classRank[a.Classification] < classRank[b.Classification]
+		}
+		if am, bm := valuation.ValueOr(a.CostFloorMarginDelta, 0), valuation.ValueOr(b.CostFloorMarginDelta, 0); am != bm {
+			return am > bm
+		}
+		return valuation.ValueOr(a.BVRDelta, 0) > valuation.ValueOr(b.BVRDelta, 0)
+	})
+	for i := range result.Candidates {
+		result.Candidates[i].Rank = i + 1
+	}
+
+	return result, nil
+}
+
+// OverProvisioning measures waste in usage-capped components (allowances).
+// Only components with an allowance are analyzed.
+//
+// Provisioned cost = allowance * unit cost per customer.
+// Wasted cost = mean(max(0, allowance - usage)) * unit cost.
+// Cap hit share = customers using >= allowance / customers observed.
+// Suggested allowance = usage at allowance_percentile (default 90th).
+// Waste share >30% is high, 20-30% moderate (typical industry range).
+func (c *Calculator) OverProvisioning(input *domain.AppraisalInput) (*domain.OverProvisioningResult, error) {
+	if len(input.Components) == 0 {
+		return nil, fmt.Errorf("component data required")
+	}
+
+	const defaultPercentile = 0.90
+
+	result := &domain.OverProvisioningResult{}
+	for _, comp := range input.Components {
+		if comp.Allowance == nil {
+			continue
+		}
+		if *comp.Allowance <= 0 {
+			return nil, fmt.Errorf("component %q: allowance must be positive", comp.Name)
+		}
+		if len(comp.UsageDistribution) == 0 {
+			return nil, fmt.Errorf("component %q: usage_distribution required", comp.Name)
+		}
+		if comp.UnitCost == nil {
+			return nil, fmt.Errorf("component %q: unit_cost required", comp.Name)
+		}
+
+		percentile := defaultPercentile
+		if comp.AllowancePercentile != nil {
+			percentile = *comp.AllowancePercentile
+		}
+		if percentile <= 0 || percentile > 1 {
+			return nil, fmt.Errorf("component %q: allowance_percentile must be in (0, 1]", comp.Name)
+		}
+
+		allowance := *comp.Allowance
+		unitCost := *comp.UnitCost
+
+		used, unused, capHits := 0.0, 0.0, 0
+		for _, u := range comp.UsageDistribution {
+			if u >= allowance {
+				capHits++
+				used += allowance
+			} else {
+				used += u
+				unused += allowance - u
+			}
+		}
+		n := float64(len(comp.UsageDistribution))
+
+		suggested := stats.Percentile(comp.UsageDistribution, percentile)
+		opc := domain.OverProvisionedComponent{
+			Name:               comp.Name,
+			Allowance:          allowance,
+			UnitCost:           unitCost,
+			Customers:          len(comp.UsageDistribution),
+			MeanUsage:          stats.Mean(comp.UsageDistribution),
+			MedianUsage:        stats.Percentile(comp.UsageDistribution, 0.5),
+			Utilization:        used / n / allowance,
+			ProvisionedCost:    allowance * unitCost,
+			WastedCost:         unused / n * unitCost,
+			CapHitShare:        float64(capHits) / n,
+			Percentile:         percentile,
+			SuggestedAllowance: suggested,
+			SavingsPerCustomer: (allowance - suggested) * unitCost,
+		}
+		if opc.ProvisionedCost > 0 {
+			opc.WasteShare = opc.WastedCost / opc.ProvisionedCost
+		}
+
+		result.ProvisionedCost += opc.ProvisionedCost
+		result.WastedCost += opc.WastedCost
+		result.Components = append(result.Components, opc)
+	}
+
+	if len(result.Components) == 0 {
+		return nil, fmt.Errorf("at least one component with allowance required")
+	}
+
+	if result.ProvisionedCost > 0 {
+		result.WasteShare = result.WastedCost / result.ProvisionedCost
+	}
+	switch {
+	case result.WasteShare > 0.30:
+		result.Interpretation = "high_over_provisioning"
+	case result.WasteShare >= 0.20:
+		result.Interpretation = "moderate_over_provisioning"
+	default:
+		result.Interpretation = "right_sized"
+	}
+
+	return result, nil
+}
+
+// PartnerCostRatio calculates (partner + licensing cost per customer) / premium revenue delta.
+// Premium revenue delta = (premium_revenue - base_revenue) / average_customer_count.
+// Target: <30%.
+//
+// Partner cost is attributed per component via component.partner (direct cost,
+// falling back to marginal cost). If partner_licensing_cost exceeds the
+// attributed sum, the remainder is reported as "unattributed"; below it is an
+// error, since the inputs disagree.
+// Concentration: HHI of attributed partner cost shares (>=0.50 high, >=0.25 moderate).
+// Margin dependence = partner cost / margin per customer, i.e. the share of
+// margin lost if that partner doubles its price.
+func (c *Calculator) PartnerCostRatio(input *domain.AppraisalInput) (*domain.PartnerCostResult, error) {
+	if input.Financials == nil {
+		return nil, fmt.Errorf("financial data required")
+	}
+	f := input.Financials
+
+	if f.AverageCustomerCount == nil || *f.AverageCustomerCount <= 0 {
+		return nil, fmt.Errorf("average_customer_count required and must be positive")
+	}
+	if f.PremiumRevenue == nil || f.BaseRevenue == nil {
+		return nil, fmt.Errorf("premium_revenue and base_revenue required")
+	}
+
+	revenueDelta := (*f.PremiumRevenue - *f.BaseRevenue) / *f.AverageCustomerCount
+	if revenueDelta <= 0 {
+		return nil, fmt.Errorf("premium revenue delta must be positive")
+	}
+
+	var partners []domain.PartnerCost
+	index := make(map[string]int)
+	attributed := 0.0
+	for _, comp := range input.Components {
+		if comp.Partner == nil {
+			continue
+		}
+		i, ok := index[*comp.Partner]
+		if !ok {
+			i = len(partners)
+			index[*comp.Partner] = i
+			partners = append(partners, domain.PartnerCost{Partner: *comp.Partner})
+		}
+		cost := componentCost(comp)
+		partners[i].Cost += cost
+		partners[i].Components = append(partners[i].Components, comp.Name)
+		attributed += cost
+	}
+
+	total := attributed
+	if f.PartnerLicensingCost != nil {
+		if *f.PartnerLicensingCost < attributed-1e-9 {
+			return nil, fmt.Errorf("partner_licensing_cost %g is below the %g attributed to component partners", *f.PartnerLicensingCost, attributed)
+		}
+		total = *f.PartnerLicensingCost
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("partner_licensing_cost or component partner attribution required")
+	}
+
+	revenue := *f.PremiumRevenue / *f.AverageCustomerCount
+	if f.RevenuePerCustomer != nil {
+		revenue = *f.RevenuePerCustomer
+	}
+	cost := total
+	if f.DirectCostPerCustomer != nil {
+		cost += *f.DirectCostPerCustomer
+	}
+	if f.SharedCostPerCustomer != nil {
+		cost += *f.SharedCostPerCustomer
+	}
+	if f.CustomerServiceCost != nil {
+		cost += *f.CustomerServiceCost
+	}
+	margin := revenue - cost
+
+	const threshold = 0.30
+	result := &domain.PartnerCostResult{
+		PartnerCostPerCustomer: total,
+		PremiumRevenueDelta:    revenueDelta,
+		Ratio:                  total / revenueDelta,
+		Threshold:              threshold,
+		MarginPerCustomer:      margin,
+	}
+	result.Passes = result.Ratio < threshold
+	if result.Passes {
+		result.Interpretation = "partner_costs_within_threshold"
+	} else {
+		result.Interpretation = "partner_costs_exceed_threshold"
+	}
+
+	sort.SliceStable(partners, func(i, j int) bool { return partners[i].Cost > partners[j].Cost })
+	for i := range partners {
+		p := &partners[i]
+		if attributed > 0 {
+			share := p.Cost / attributed
+			result.HHI += share * share
+		}
+		fillPartnerCost(p, total, revenueDelta, margin)
+	}
+	if len(partners) > 0 {
+		result.TopPartner = partners[0].Partner
+		result.TopPartnerMarginDependence = partners[0].MarginDependence
+	}
+	if total > attributed {
+		unattributed := domain.PartnerCost{Partner: "unattributed", Cost: total - attributed}
+		fillPartnerCost(&unattributed, total, revenueDelta, margin)
+		partners = append(partners, unattributed)
+	}
+	result.Partners = partners
+
+	switch {
+	case result.HHI >= 0.50:
+		result.Concentration = "high"
+	case result.HHI >= 0.25:
+		result.Concentration = "moderate"
+	default:
+		result.Concentration = "low"
+	}
+
+	return result, nil
+}
+
+// fillPartnerCost derives the share and margin dependency fields from p.Cost.
+func fillPartnerCost(p *domain.PartnerCost, total, revenueDelta, margin float64) {
+	p.CostShare = p.Cost / total
+	p.DeltaRatio = p.Cost / revenueDelta
+	if margin > 0 {
+		dependence := p.Cost / margin
+		p.MarginDependence = &dependence
+		if p.Cost > 0 {
+			increase := margin / p.Cost
+			p.BreakEvenPriceIncrease = &increase
+		}
+	}
+}
+
+// removalScenario computes the metrics tracked by RemovalImpact for one composition.
+func (c *Calculator) removalScenario(input *domain.AppraisalInput) (*domain.RemovalScenario, error) {
+	scenario := &domain.RemovalScenario{}
+	if input.Product != nil {
+		scenario.BundlePrice = input.Product.Price
+	}
+
+	if bvr, _, err := valuation.BVR(input.Product); err == nil {
+		scenario.BVR = &bvr
+	}
+	if discount, err := valuation.BundleDiscount(input.Product); err == nil {
+		scenario.BundleDiscount = &discount
+	}
+	if input.Product != nil && input.Financials != nil {
+		if floor, _, err := valuation.CostFloor(input.Financials); err == nil {
+			margin := input.Product.Price - floor
+			scenario.CostFloorMargin = &margin
+		}
+	}
+
+	dw, err := c.DeadWeightRatio(input)
+	if err != nil {
+		return nil, err
+	}
+	scenario.DeadWeightRatio = dw.DeadWeightRatio
+
+	lfk, err := c.ClassifyComponents(input)
+	if err != nil {
+		return nil, err
+	}
+	scenario.Leaders = lfk.Leaders
+	scenario.Fillers = lfk.Fillers
+	scenario.Killers = lfk.Killers
+
+	return scenario, nil
+}
+
+// withoutComponent returns a copy of input with the named component removed.
+// The original input is not modified.
+func withoutComponent(input *domain.AppraisalInput, comp domain.ComponentData) *domain.AppraisalInput {
+	out := *input
+
+	out.Components = nil
+	for _, cd := range input.Components {
+		if cd.Name != comp.Name {
+			out.Components = append(out.Components, cd)
+		}
+	}
+
+	cost := componentCost(comp)
+	if input.Product != nil {
+		product := *input.Product
+		product.Components = nil
+		for _, pc := range input.Product.Components {
+			if pc.Name == comp.Name {
+				if comp.DirectCost == nil && comp.MarginalCost == nil && pc.MarginalCost != nil {
+					cost = *pc.MarginalCost
+				}
+				continue
+			}
+			product.Components = append(product.Components, pc)
+		}
+		if comp.RemovalPriceDelta != nil {
+			product.Price += *comp.RemovalPriceDelta
+		}
+		out.Product = &product
+	}
+
+	// A partner-supplied component is paid for in partner_licensing_cost
+	// (see PartnerCostRatio); any other component in the direct cost.
+	if input.Financials != nil {
+		fin := *input.Financials
+		costField := &fin.DirectCostPerCustomer
+		if comp.Partner != nil && fin.PartnerLicensingCost != nil {
+			costField = &fin.PartnerLicensingCost
+		}
+		if *costField != nil {
+			remaining := math.Max(0, **costField-cost)
+			*costField = &remaining
+		}
+		out.Financials = &fin
+	}
+
+	return &out
+}
+
+// delta returns after - before, or nil if either side is unavailable.
+func delta(after, before *float64) *float64 {
+	if after == nil || before == nil {
+		return nil
+	}
+	d := *after - *before
+	return &d
+}
+
+// pickShares distributes k picks across components in proportion to usage.
+// Shares are capped at 1.0; the excess is redistributed among the rest.
+// With no usage data every component gets an equal k/n share.
+func pickShares(usages []float64, k int) []float64 {
+	n := len(usages)
+	shares := make([]float64, n)
+	capped := make([]bool, n)
+	remaining := float64(k)
+
+	for {
+		weight := 0.0
+		free := 0
+		for i, u := range usages {
+			if !capped[i] {
+				weight += u
+				free++
+			}
+		}
+		if free == 0 {
+			return shares
+		}
+
+		changed := false
+		for i, u := range usages {
+			if capped[i] {
+				continue
+			}
+			if weight > 0 {
+				shares[i] = remaining * u / weight
+			} else {
+				shares[i] = remaining / float64(free)
+			}
+			if shares[i] > 1.0 {
+				shares[i] = 1.0
+				capped[i] = true
+				remaining -= 1.0
+				changed = true
+			}
+		}
+		if !changed {
+			return shares
+		}
+	}
+}
+
+// sumExtreme sums the k largest (or smallest) values.
+func sumExtreme(values []float64, k int, largest bool) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for i := 0; i < k && i < len(sorted); i++ {
+		if largest {
+			sum += sorted[len(sorted)-1-i]
+		} else {
+			sum += sorted[i]
+		}
+	}
+	return sum
+}
+
+// componentUsage returns actual monthly active rate, falling back to forecast.
+func componentUsage(comp domain.ComponentData) float64 {
+	if comp.MonthlyActiveRate != nil {
+		return *comp.MonthlyActiveRate
+	}
+	if comp.UsageForecast != nil {
+		return *comp.UsageForecast
+	}
+	return 0
+}
+
+// componentCost returns direct cost, falling back to marginal cost.
+func componentCost(comp domain.ComponentData) float64 {
+	if comp.DirectCost != nil {
+		return *comp.DirectCost
+	}
+	if comp.MarginalCost != nil {
+		return *comp.MarginalCost
+	}
+	return 0
+}
+
+// productComponent finds the product definition entry matching a component name.
+func productComponent(input *domain.AppraisalInput, name string) *domain.Component {
+	if input.Product == nil {
+		return nil
+	}
+	for i := range input.Product.Components {
+		if input.Product.Components[i].Name == name {
+			return &input.Product.Components[i]
+		}
+	}
+	return nil
+}
+
+// isSwappable reads is_swappable from component data, then from the product definition.
+func isSwappable(input *domain.AppraisalInput, comp domain.ComponentData) bool {
+	if comp.IsSwappable != nil {
+		return *comp.IsSwappable
+	}
+	if pc := productComponent(input, comp.Name); pc != nil && pc.IsSwappable != nil {
+		return *pc.IsSwappable
+	}
+	return false
+}
+
+// standalonePrice reads standalone price from component data, then from the product definition.
+func standalonePrice(input *domain.AppraisalInput, comp domain.ComponentData) float64 {
+	if comp.StandalonePrice != nil {
+		return *comp.StandalonePrice
+	}
+	if pc := productComponent(input, comp.Name); pc != nil {
+		return pc.StandalonePrice
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package bundle
 
 import (
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
@@ -254,7 +255,7 @@ func TestClassifyComponents(t *testing.T) {
 				if err == nil {
 					t.Fatalf("expected error containing %q, got nil", tt.errContains)
 				}
-				if tt.errContains != "" && !containsStr(err.Error(), tt.errContains) {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
 				}
 				return
@@ -463,7 +464,7 @@ func TestDeadWeightRatio(t *testing.T) {
 				if err == nil {
 					t.Fatalf("expected error containing %q, got nil", tt.errContains)
 				}
-				if tt.errContains != "" && !containsStr(err.Error(), tt.errContains) {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
 				}
 				return
@@ -649,7 +650,7 @@ func TestCrossSubsidyAnalysis(t *testing.T) {
 				if err == nil {
 					t.Fatalf("expected error containing %q, got nil", tt.errContains)
 				}
-				if tt.errContains != "" && !containsStr(err.Error(), tt.errContains) {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
 				}
 				return
@@ -690,6 +691,158 @@ func TestCrossSubsidyAnalysis(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// CrossSubsidyStress tests
+// ---------------------------------------------------------------------------
+
+func TestCrossSubsidyStress(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name               string
+		components         []domain.ComponentData
+		wantDefault        bool
+		wantStressed       float64
+		wantSurvives       bool
+		wantShockBP        *float64
+		wantRecipientBE    *float64
+		wantSourcePriceBE  *float64
+		wantComponentUsage map[string]*float64
+		wantComponentPrice map[string]*float64
+	}{
+		{
+			name: "default_shocks_double_recipient_usage",
+			components: []domain.ComponentData{
+				{Name: "Core", RevenueContrib: ptr(100), DirectCost: ptr(30)},
+				{Name: "Add-on", RevenueContrib: ptr(20), DirectCost: ptr(50)},
+			},
+			// M0 = 70 - 30 = 40; Add-on cost doubles to 100 -> 70 - 80 = -10
+			wantDefault:       true,
+			wantStressed:      -10,
+			wantSurvives:      false,
+			wantShockBP:       ptr(0.8), // 40 / (40 - -10)
+			wantRecipientBE:   ptr(0.8), // 40 / 50
+			wantSourcePriceBE: ptr(0.4), // 40 / 100
+			wantComponentUsage: map[string]*float64{
+				"Core":   ptr(40.0 / 30.0),
+				"Add-on": ptr(0.8),
+			},
+			wantComponentPrice: map[string]*float64{
+				"Core":   ptr(0.4),
+				"Add-on": nil, // would need a 200% price drop
+			},
+		},
+		{
+			name: "custom_shocks_survive",
+			components: []domain.ComponentData{
+				{Name: "Core", RevenueContrib: ptr(100), DirectCost: ptr(30), PriceShock: ptr(-0.1)},
+				{Name: "Add-on", RevenueContrib: ptr(20), DirectCost: ptr(50), UsageShock: ptr(0.2)},
+			},
+			// Core: 90 - 30 = 60; Add-on: 20 - 60 = -40 -> 20
+			wantStressed:      20,
+			wantSurvives:      true,
+			wantShockBP:       ptr(2), // 40 / (40 - 20)
+			wantRecipientBE:   ptr(0.8),
+			wantSourcePriceBE: ptr(0.4),
+		},
+		{
+			name: "favorable_shocks_never_break",
+			components: []domain.ComponentData{
+				{Name: "Core", RevenueContrib: ptr(100), DirectCost: ptr(30), PriceShock: ptr(0.1)},
+				{Name: "Add-on", RevenueContrib: ptr(20), DirectCost: ptr(50)},
+			},
+			wantStressed:      50,
+			wantSurvives:      true,
+			wantShockBP:       nil,
+			wantRecipientBE:   ptr(0.8),
+			wantSourcePriceBE: ptr(0.4),
+		},
+		{
+			name: "already_negative_breaks_at_zero",
+			components: []domain.ComponentData{
+				{Name: "A", RevenueContrib: ptr(10), DirectCost: ptr(50)},
+				{Name: "B", RevenueContrib: ptr(5), DirectCost: ptr(30)},
+			},
+			// Both recipients double: (10 - 100) + (5 - 60) = -145
+			wantDefault:       true,
+			wantStressed:      -145,
+			wantSurvives:      false,
+			wantShockBP:       ptr(0),
+			wantRecipientBE:   ptr(0),
+			wantSourcePriceBE: ptr(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.CrossSubsidyStress(&domain.AppraisalInput{Components: tt.components})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.DefaultShocks != tt.wantDefault {
+				t.Errorf("DefaultShocks = %v, want %v", result.DefaultShocks, tt.wantDefault)
+			}
+			if !almostEqual(result.StressedNetMargin, tt.wantStressed, epsilon) {
+				t.Errorf("StressedNetMargin = %v, want %v", result.StressedNetMargin, tt.wantStressed)
+			}
+			if result.SurvivesStress != tt.wantSurvives {
+				t.Errorf("SurvivesStress = %v, want %v", result.SurvivesStress, tt.wantSurvives)
+			}
+			checkOptional(t, "ShockBreakPoint", result.ShockBreakPoint, tt.wantShockBP)
+			checkOptional(t, "RecipientUsageBreakEven", result.RecipientUsageBreakEven, tt.wantRecipientBE)
+			checkOptional(t, "SourcePriceBreakEven", result.SourcePriceBreakEven, tt.wantSourcePriceBE)
+			for _, sc := range result.Components {
+				if want, ok := tt.wantComponentUsage[sc.Name]; ok {
+					checkOptional(t, sc.Name+" UsageBreakPoint", sc.UsageBreakPoint, want)
+				}
+				if want, ok := tt.wantComponentPrice[sc.Name]; ok {
+					checkOptional(t, sc.Name+" PriceBreakPoint", sc.PriceBreakPoint, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCrossSubsidyStressErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		components  []domain.ComponentData
+		errContains string
+	}{
+		{"no_components", nil, "component data required"},
+		{
+			"duplicate_name",
+			[]domain.ComponentData{
+				{Name: "Core", RevenueContrib: ptr(100), DirectCost: ptr(30)},
+				{Name: "Core", RevenueContrib: ptr(20), DirectCost: ptr(50)},
+			},
+			`duplicate component "Core"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New().CrossSubsidyStress(&domain.AppraisalInput{Components: tt.components})
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
+
+// checkOptional compares an optional float result against an optional expectation.
+func checkOptional(t *testing.T, field string, got, want *float64) {
+	t.Helper()
+	switch {
+	case want == nil && got != nil:
+		t.Errorf("%s = %v, want nil", field, *got)
+	case want != nil && got == nil:
+		t.Errorf("%s = nil, want %v", field, *want)
+	case want != nil && !almostEqual(*got, *want, epsilon):
+		t.Errorf("%s = %v, want %v", field, *got, *want)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // ComponentActivation tests
 // ---------------------------------------------------------------------------
@@ -836,7 +989,7 @@ func TestComponentActivation(t *testing.T) {
 				if err == nil {
 					t.Fatalf("expected error containing %q, got nil", tt.errContains)
 				}
-				if tt.errContains != "" && !containsStr(err.Error(), tt.errContains) {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
 				}
 				return
@@ -854,7 +1007,7 @@ func TestComponentActivation(t *testing.T) {
 					t.Errorf("component %q: rate = %v, want %v", compName, r.Value, expectedRate)
 				}
 				expectedInterp, ok := tt.wantInterps[compName]
-				if ok && !containsStr(r.Interpretation, expectedInterp) {
+				if ok && !strings.Contains(r.Interpretation, expectedInterp) {
 					t.Errorf("component %q: interpretation = %q, want to contain %q", compName, r.Interpretation, expectedInterp)
 				}
 			}
@@ -1001,7 +1154,7 @@ func TestMultiComponentUsage(t *testing.T) {
 				if err == nil {
 					t.Fatalf("expected error containing %q, got nil", tt.errContains)
 				}
-				if tt.errContains != "" && !containsStr(err.Error(), tt.errContains) {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
 					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
 				}
 				return
@@ -1020,29 +1173,726 @@ func TestMultiComponentUsage(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// TestNew verifies constructor
+// Swappable tests
 // ---------------------------------------------------------------------------
 
-func TestNew(t *testing.T) {
+func TestSwappable(t *testing.T) {
+	one, two := 1, 2
 	calc := New()
-	if calc == nil {
-		t.Fatal("New() returned nil")
+
+	tests := []struct {
+		name             string
+		input            *domain.AppraisalInput
+		wantExpectedDW   float64
+		wantForcedDW     float64
+		wantPasses       bool
+		wantExpectedCost float64
+		wantBestCost     float64
+		wantWorstCost    float64
+		wantBVRMin       float64
+		wantBVRExpected  float64
+		wantBVRMax       float64
+		wantShares       map[string]float64
+		wantErr          bool
+		errContains      string
+	}{
+		{
+			name: "pick_two_of_three_with_capped_share",
+			input: &domain.AppraisalInput{
+				Product: &domain.ProductDefinition{Name: "Flex", Price: 30, SwapPicks: &two},
+				Components: []domain.ComponentData{
+					{Name: "Core", MonthlyActiveRate: ptr(0.8), DirectCost: ptr(5), StandalonePrice: ptr(20)},
+					{Name: "A", MonthlyActiveRate: ptr(0.6), DirectCost: ptr(4), StandalonePrice: ptr(10), IsSwappable: boolPtr(true)},
+					{Name: "B", MonthlyActiveRate: ptr(0.3), DirectCost: ptr(2), StandalonePrice: ptr(8), IsSwappable: boolPtr(true)},
+					{Name: "C", MonthlyActiveRate: ptr(0.1), DirectCost: ptr(6), StandalonePrice: ptr(12), IsSwappable: boolPtr(true)},
+				},
+			},
+			// Shares: A capped at 1.0, remaining 1.0 split B:C = 0.3:0.1 -> 0.75, 0.25
+			// Usage when picked: A 0.6, B 0.4, C 0.4 -> no dead weight slots
+			// Forced: C below 20% -> 1/4
+			wantExpectedDW:   0.0,
+			wantForcedDW:     0.25,
+			wantPasses:       true,
+			wantExpectedCost: 12.0, // 5 + 4 + 0.75*2 + 0.25*6
+			wantBestCost:     11.0, // 5 + 2 + 4
+			wantWorstCost:    15.0, // 5 + 6 + 4
+			wantBVRMin:       38.0 / 30.0,
+			wantBVRExpected:  39.0 / 30.0,
+			wantBVRMax:       42.0 / 30.0,
+			wantShares:       map[string]float64{"A": 1.0, "B": 0.75, "C": 0.25},
+		},
+		{
+			name: "swappable_flag_from_product_definition_all_dead",
+			input: &domain.AppraisalInput{
+				Product: &domain.ProductDefinition{
+					Name:      "Menu",
+					Price:     10,
+					SwapPicks: &one,
+					Components: []domain.Component{
+						{Name: "P", StandalonePrice: 4, IsSwappable: boolPtr(true)},
+						{Name: "Q", StandalonePrice: 4, IsSwappable: boolPtr(true)},
+						{Name: "R", StandalonePrice: 2, IsSwappable: boolPtr(true)},
+					},
+				},
+				Components: []domain.ComponentData{
+					{Name: "X", UsageForecast: ptr(0.5), StandalonePrice: ptr(8)},
+					{Name: "P", UsageForecast: ptr(0.05)},
+					{Name: "Q", UsageForecast: ptr(0.05)},
+					{Name: "R", UsageForecast: ptr(0.02)},
+				},
+			},
+			// Every slot's usage when picked = 0.12 -> expected dead = 1 / (1 + 1)
+			wantExpectedDW:   0.5,
+			wantForcedDW:     0.75,
+			wantPasses:       false,
+			wantExpectedCost: 0,
+			wantBestCost:     0,
+			wantWorstCost:    0,
+			wantBVRMin:       1.0, // (8 + 2) / 10
+			wantBVRExpected:  (8 + 4*(0.05/0.12)*2 + 2*(0.02/0.12)) / 10,
+			wantBVRMax:       1.2, // (8 + 4) / 10
+			wantShares:       map[string]float64{"P": 0.05 / 0.12, "Q": 0.05 / 0.12, "R": 0.02 / 0.12},
+		},
+		{
+			name: "no_usage_data_splits_evenly",
+			input: &domain.AppraisalInput{
+				Product: &domain.ProductDefinition{Name: "Even", Price: 10, SwapPicks: &one},
+				Components: []domain.ComponentData{
+					{Name: "A", IsSwappable: boolPtr(true), StandalonePrice: ptr(6)},
+					{Name: "B", IsSwappable: boolPtr(true), StandalonePrice: ptr(10)},
+				},
+			},
+			wantExpectedDW:  1.0,
+			wantForcedDW:    1.0,
+			wantPasses:      false,
+			wantBVRMin:      0.6,
+			wantBVRExpected: 0.8,
+			wantBVRMax:      1.0,
+			wantShares:      map[string]float64{"A": 0.5, "B": 0.5},
+		},
+		// Error cases
+		{
+			name:        "no_components",
+			input:       &domain.AppraisalInput{Product: &domain.ProductDefinition{Price: 10}},
+			wantErr:     true,
+			errContains: "component data required",
+		},
+		{
+			name: "no_product",
+			input: &domain.AppraisalInput{
+				Components: []domain.ComponentData{{Name: "A", IsSwappable: boolPtr(true)}},
+			},
+			wantErr:     true,
+			errContains: "product definition required",
+		},
+		{
+			name: "no_swappable_components",
+			input: &domain.AppraisalInput{
+				Product:    &domain.ProductDefinition{Price: 10, SwapPicks: &one},
+				Components: []domain.ComponentData{{Name: "A"}},
+			},
+			wantErr:     true,
+			errContains: "swappable component required",
+		},
+		{
+			name: "missing_swap_picks",
+			input: &domain.AppraisalInput{
+				Product:    &domain.ProductDefinition{Price: 10},
+				Components: []domain.ComponentData{{Name: "A", IsSwappable: boolPtr(true)}},
+			},
+			wantErr:     true,
+			errContains: "swap_picks required",
+		},
+		{
+			name: "picks_exceed_pool",
+			input: &domain.AppraisalInput{
+				Product:    &domain.ProductDefinition{Price: 10, SwapPicks: &two},
+				Components: []domain.ComponentData{{Name: "A", IsSwappable: boolPtr(true)}},
+			},
+			wantErr:     true,
+			errContains: "swap_picks must be between 1 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Swappable(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error containing %q, got nil", tt.errContains)
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !almostEqual(result.ExpectedDeadWeightRatio, tt.wantExpectedDW, epsilon) {
+				t.Errorf("ExpectedDeadWeightRatio = %v, want %v", result.ExpectedDeadWeightRatio, tt.wantExpectedDW)
+			}
+			if !almostEqual(result.ForcedDeadWeightRatio, tt.wantForcedDW, epsilon) {
+				t.Errorf("ForcedDeadWeightRatio = %v, want %v", result.ForcedDeadWeightRatio, tt.wantForcedDW)
+			}
+			if result.Passes != tt.wantPasses {
+				t.Errorf("Passes = %v, want %v", result.Passes, tt.wantPasses)
+			}
+			if !almostEqual(result.ExpectedCost, tt.wantExpectedCost, epsilon) {
+				t.Errorf("ExpectedCost = %v, want %v", result.ExpectedCost, tt.wantExpectedCost)
+			}
+			if !almostEqual(result.BestCaseCost, tt.wantBestCost, epsilon) {
+				t.Errorf("BestCaseCost = %v, want %v", result.BestCaseCost, tt.wantBestCost)
+			}
+			if !almostEqual(result.WorstCaseCost, tt.wantWorstCost, epsilon) {
+				t.Errorf("WorstCaseCost = %v, want %v", result.WorstCaseCost, tt.wantWorstCost)
+			}
+			if !almostEqual(result.BVRMin, tt.wantBVRMin, epsilon) {
+				t.Errorf("BVRMin = %v, want %v", result.BVRMin, tt.wantBVRMin)
+			}
+			if !almostEqual(result.BVRExpected, tt.wantBVRExpected, epsilon) {
+				t.Errorf("BVRExpected = %v, want %v", result.BVRExpected, tt.wantBVRExpected)
+			}
+			if !almostEqual(result.BVRMax, tt.wantBVRMax, epsilon) {
+				t.Errorf("BVRMax = %v, want %v", result.BVRMax, tt.wantBVRMax)
+			}
+			totalShare := 0.0
+			for _, slot := range result.Slots {
+				want, ok := tt.wantShares[slot.Name]
+				if !ok {
+					t.Errorf("unexpected slot %q", slot.Name)
+					continue
+				}
+				if !almostEqual(slot.PickShare, want, epsilon) {
+					t.Errorf("slot %q PickShare = %v, want %v", slot.Name, slot.PickShare, want)
+				}
+				totalShare += slot.PickShare
+			}
+			if !almostEqual(totalShare, float64(result.Picks), epsilon) {
+				t.Errorf("pick shares sum to %v, want %d", totalShare, result.Picks)
+			}
+		})
 	}
 }
 
 // ---------------------------------------------------------------------------
-// Helpers
+// RemovalImpact tests
 // ---------------------------------------------------------------------------
 
-func containsStr(s, substr string) bool {
-	return len(s) >= len(substr) && searchStr(s, substr)
+func TestRemovalImpact(t *testing.T) {
+	calc := New()
+
+	input := &domain.AppraisalInput{
+		Product: &domain.ProductDefinition{
+			Name:  "Entertainment Pack",
+			Price: 50,
+			Components: []domain.Component{
+				{Name: "Music", StandalonePrice: 30},
+				{Name: "Video", StandalonePrice: 25},
+				{Name: "Games", StandalonePrice: 15},
+			},
+		},
+		Financials: &domain.FinancialData{
+			DirectCostPerCustomer: ptr(20),
+			SharedCostPerCustomer: ptr(5),
+		},
+		Components: []domain.ComponentData{
+			{Name: "Music", PerceivedValue: ptr(4.5), MarginalCost: ptr(8), MonthlyActiveRate: ptr(0.7), DrivesPurchase: boolPtr(true)},
+			{Name: "Video", PerceivedValue: ptr(3.0), MarginalCost: ptr(2), MonthlyActiveRate: ptr(0.5)},
+			{Name: "Games", PerceivedValue: ptr(1.0), MarginalCost: ptr(10), MonthlyActiveRate: ptr(0.1), RemovalPriceDelta: ptr(-5)},
+		},
+	}
+
+	result, err := calc.RemovalImpact(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Baseline: BVR 70/50, discount 1-50/70, floor 25 -> margin 25, DW 1/3
+	base := result.Baseline
+	if base.BVR == nil || !almostEqual(*base.BVR, 1.4, epsilon) {
+		t.Errorf("baseline BVR = %v, want 1.4", base.BVR)
+	}
+	if base.CostFloorMargin == nil || !almostEqual(*base.CostFloorMargin, 25, epsilon) {
+		t.Errorf("baseline CostFloorMargin = %v, want 25", base.CostFloorMargin)
+	}
+	if !almostEqual(base.DeadWeightRatio, 1.0/3.0, epsilon) {
+		t.Errorf("baseline DeadWeightRatio = %v, want 1/3", base.DeadWeightRatio)
+	}
+	if base.Leaders != 1 || base.Fillers != 1 || base.Killers != 1 {
+		t.Errorf("baseline L/F/K = %d/%d/%d, want 1/1/1", base.Leaders, base.Fillers, base.Killers)
+	}
+
+	wantOrder := []string{"Games", "Video", "Music"}
+	if len(result.Candidates) != len(wantOrder) {
+		t.Fatalf("got %d candidates, want %d", len(result.Candidates), len(wantOrder))
+	}
+	for i, name := range wantOrder {
+		if result.Candidates[i].Component != name {
+			t.Errorf("rank %d = %q, want %q", i+1, result.Candidates[i].Component, name)
+		}
+		if result.Candidates[i].Rank != i+1 {
+			t.Errorf("candidate %q Rank = %d, want %d", name, result.Candidates[i].Rank, i+1)
+		}
+	}
+
+	// Drop Games: price 45, standalone 55, direct 10 -> floor 15, margin 30
+	games := result.Candidates[0]
+	if games.Classification != "killer" {
+		t.Errorf("Games classification = %q, want killer", games.Classification)
+	}
+	if !almostEqual(games.BundlePrice, 45, epsilon) {
+		t.Errorf("Games BundlePrice = %v, want 45", games.BundlePrice)
+	}
+	if games.BVR == nil || !almostEqual(*games.BVR, 55.0/45.0, epsilon) {
+		t.Errorf("Games BVR = %v, want %v", games.BVR, 55.0/45.0)
+	}
+	if games.BundleDiscount == nil || !almostEqual(*games.BundleDiscount, 1-45.0/55.0, epsilon) {
+		t.Errorf("Games BundleDiscount = %v, want %v", games.BundleDiscount, 1-45.0/55.0)
+	}
+	if games.CostFloorMarginDelta == nil || !almostEqual(*games.CostFloorMarginDelta, 5, epsilon) {
+		t.Errorf("Games CostFloorMarginDelta = %v, want 5", games.CostFloorMarginDelta)
+	}
+	if !almostEqual(games.DeadWeightRatio, 0, epsilon) || !almostEqual(games.DeadWeightDelta, -1.0/3.0, epsilon) {
+		t.Errorf("Games DeadWeight = %v (delta %v), want 0 (delta -1/3)", games.DeadWeightRatio, games.DeadWeightDelta)
+	}
+	if games.Killers != 0 || games.Leaders != 1 || games.Fillers != 1 {
+		t.Errorf("Games L/F/K = %d/%d/%d, want 1/1/0", games.Leaders, games.Fillers, games.Killers)
+	}
+
+	// Drop Music: standalone 40 -> BVR 0.8, direct 12 -> margin 33
+	music := result.Candidates[2]
+	if music.BVRDelta == nil || !almostEqual(*music.BVRDelta, -0.6, epsilon) {
+		t.Errorf("Music BVRDelta = %v, want -0.6", music.BVRDelta)
+	}
+	if music.CostFloorMargin == nil || !almostEqual(*music.CostFloorMargin, 33, epsilon) {
+		t.Errorf("Music CostFloorMargin = %v, want 33", music.CostFloorMargin)
+	}
+
+	// Input must be left untouched
+	if len(input.Components) != 3 || len(input.Product.Components) != 3 {
+		t.Error("input components were modified")
+	}
+	if input.Product.Price != 50 || *input.Financials.DirectCostPerCustomer != 20 {
+		t.Error("input price or costs were modified")
+	}
 }
 
-func searchStr(s, sub string) bool {
-	for i := 0; i <= len(s)-len(sub); i++ {
-		if s[i:i+len(sub)] == sub {
-			return true
+func TestRemovalImpactPartnerComponent(t *testing.T) {
+	partner := "StreamCo"
+	calc := New()
+
+	result, err := calc.RemovalImpact(&domain.AppraisalInput{
+		Product: &domain.ProductDefinition{
+			Name:  "Entertainment Pack",
+			Price: 50,
+			Components: []domain.Component{
+				{Name: "Music", StandalonePrice: 30},
+				{Name: "Video", StandalonePrice: 25},
+			},
+		},
+		Financials: &domain.FinancialData{
+			DirectCostPerCustomer: ptr(10),
+			PartnerLicensingCost:  ptr(12),
+		},
+		Components: []domain.ComponentData{
+			{Name: "Music", PerceivedValue: ptr(4.5), MarginalCost: ptr(10), MonthlyActiveRate: ptr(0.7)},
+			{Name: "Video", PerceivedValue: ptr(3.0), MarginalCost: ptr(8), MonthlyActiveRate: ptr(0.5), Partner: &partner},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Baseline floor 10 + 12 = 22 -> margin 28. Dropping Video takes its 8
+	// out of the partner cost: floor 14 -> margin 36. Dropping Music takes
+	// 10 out of the direct cost: floor 12 -> margin 38.
+	want := map[string]float64{"Video": 8, "Music": 10}
+	for _, cand := range result.Candidates {
+		if cand.CostFloorMarginDelta == nil || !almostEqual(*cand.CostFloorMarginDelta, want[cand.Component], epsilon) {
+			t.Errorf("%s CostFloorMarginDelta = %v, want %v", cand.Component, cand.CostFloorMarginDelta, want[cand.Component])
+		}
+	}
+}
+
+func TestRemovalImpactWithoutPricingData(t *testing.T) {
+	calc := New()
+
+	result, err := calc.RemovalImpact(&domain.AppraisalInput{
+		Components: []domain.ComponentData{
+			{Name: "A", PerceivedValue: ptr(3.0), MarginalCost: ptr(1), UsageForecast: ptr(0.5)},
+			{Name: "B", PerceivedValue: ptr(3.0), MarginalCost: ptr(4), UsageForecast: ptr(0.1)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Baseline.BVR != nil || result.Baseline.CostFloorMargin != nil {
+		t.Error("expected nil pricing metrics without product/financial data")
+	}
+	for _, cand := range result.Candidates {
+		if cand.BVRDelta != nil {
+			t.Errorf("candidate %q BVRDelta = %v, want nil", cand.Component, *cand.BVRDelta)
 		}
 	}
-	return false
+	// Both fillers: ranked by BVR/margin ties, then stable input order.
+	if result.Candidates[0].Component != "A" {
+		t.Errorf("rank 1 = %q, want A", result.Candidates[0].Component)
+	}
+}
+
+func TestRemovalImpactErrors(t *testing.T) {
+	calc := New()
+
+	_, err := calc.RemovalImpact(&domain.AppraisalInput{
+		Components: []domain.ComponentData{{Name: "Only"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "at least 2 components required") {
+		t.Fatalf("expected error about 2 components, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// OverProvisioning tests
+// ---------------------------------------------------------------------------
+
+func TestOverProvisioning(t *testing.T) {
+	calc := New()
+
+	result, err := calc.OverProvisioning(&domain.AppraisalInput{
+		Components: []domain.ComponentData{
+			{
+				Name:              "Data",
+				Allowance:         ptr(10),
+				UnitCost:          ptr(0.5),
+				UsageDistribution: []float64{1, 2, 3, 4, 10, 12, 5, 6, 7, 0},
+			},
+			{
+				Name:                "Minutes",
+				Allowance:           ptr(100),
+				UnitCost:            ptr(0.01),
+				UsageDistribution:   []float64{80, 90, 100, 110},
+				AllowancePercentile: ptr(0.5),
+			},
+			{Name: "Streaming"}, // no allowance: skipped
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Components) != 2 {
+		t.Fatalf("got %d components, want 2", len(result.Components))
+	}
+
+	data := result.Components[0]
+	// Unused: 9+8+7+6+0+0+5+4+3+10 = 52 -> 5.2 per customer * 0.5
+	if !almostEqual(data.WastedCost, 2.6, epsilon) {
+		t.Errorf("Data WastedCost = %v, want 2.6", data.WastedCost)
+	}
+	if !almostEqual(data.ProvisionedCost, 5, epsilon) {
+		t.Errorf("Data ProvisionedCost = %v, want 5", data.ProvisionedCost)
+	}
+	if !almostEqual(data.WasteShare, 0.52, epsilon) {
+		t.Errorf("Data WasteShare = %v, want 0.52", data.WasteShare)
+	}
+	if !almostEqual(data.Utilization, 0.48, epsilon) {
+		t.Errorf("Data Utilization = %v, want 0.48", data.Utilization)
+	}
+	if !almostEqual(data.CapHitShare, 0.2, epsilon) {
+		t.Errorf("Data CapHitShare = %v, want 0.2", data.CapHitShare)
+	}
+	if !almostEqual(data.MeanUsage, 5, epsilon) || !almostEqual(data.MedianUsage, 4.5, epsilon) {
+		t.Errorf("Data mean/median = %v/%v, want 5/4.5", data.MeanUsage, data.MedianUsage)
+	}
+	// Default p90 of sorted [0..7,10,12] = 10 + 0.1*(12-10)
+	if !almostEqual(data.Percentile, 0.9, epsilon) || !almostEqual(data.SuggestedAllowance, 10.2, epsilon) {
+		t.Errorf("Data suggested = p%v %v, want p0.9 10.2", data.Percentile, data.SuggestedAllowance)
+	}
+	if !almostEqual(data.SavingsPerCustomer, -0.1, epsilon) {
+		t.Errorf("Data SavingsPerCustomer = %v, want -0.1", data.SavingsPerCustomer)
+	}
+
+	minutes := result.Components[1]
+	if !almostEqual(minutes.WastedCost, 0.075, epsilon) {
+		t.Errorf("Minutes WastedCost = %v, want 0.075", minutes.WastedCost)
+	}
+	if !almostEqual(minutes.SuggestedAllowance, 95, epsilon) || !almostEqual(minutes.SavingsPerCustomer, 0.05, epsilon) {
+		t.Errorf("Minutes suggested/savings = %v/%v, want 95/0.05", minutes.SuggestedAllowance, minutes.SavingsPerCustomer)
+	}
+	if !almostEqual(minutes.CapHitShare, 0.5, epsilon) {
+		t.Errorf("Minutes CapHitShare = %v, want 0.5", minutes.CapHitShare)
+	}
+
+	if !almostEqual(result.ProvisionedCost, 6, epsilon) || !almostEqual(result.WastedCost, 2.675, epsilon) {
+		t.Errorf("totals = %v/%v, want 6/2.675", result.ProvisionedCost, result.WastedCost)
+	}
+	if result.Interpretation != "high_over_provisioning" {
+		t.Errorf("Interpretation = %q, want high_over_provisioning", result.Interpretation)
+	}
+}
+
+func TestOverProvisioningInterpretation(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name  string
+		usage []float64
+		want  string
+	}{
+		// unused 1+0+0+2 = 3 over 4 customers / 10 allowance = 7.5%
+		{name: "right_sized", usage: []float64{9, 10, 10, 8}, want: "right_sized"},
+		// unused 2+2+3+3 = 10 / 4 / 10 = 25%
+		{name: "moderate", usage: []float64{8, 8, 7, 7}, want: "moderate_over_provisioning"},
+		{name: "high", usage: []float64{1, 2, 3, 4}, want: "high_over_provisioning"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.OverProvisioning(&domain.AppraisalInput{
+				Components: []domain.ComponentData{
+					{Name: "Data", Allowance: ptr(10), UnitCost: ptr(1), UsageDistribution: tt.usage},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Interpretation != tt.want {
+				t.Errorf("Interpretation = %q, want %q (waste share %v)", result.Interpretation, tt.want, result.WasteShare)
+			}
+		})
+	}
+}
+
+func TestOverProvisioningErrors(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name        string
+		components  []domain.ComponentData
+		errContains string
+	}{
+		{name: "no_components", errContains: "component data required"},
+		{
+			name:        "no_allowances",
+			components:  []domain.ComponentData{{Name: "A"}},
+			errContains: "at least one component with allowance required",
+		},
+		{
+			name:        "missing_distribution",
+			components:  []domain.ComponentData{{Name: "A", Allowance: ptr(10), UnitCost: ptr(1)}},
+			errContains: "usage_distribution required",
+		},
+		{
+			name:        "missing_unit_cost",
+			components:  []domain.ComponentData{{Name: "A", Allowance: ptr(10), UsageDistribution: []float64{1}}},
+			errContains: "unit_cost required",
+		},
+		{
+			name:        "non_positive_allowance",
+			components:  []domain.ComponentData{{Name: "A", Allowance: ptr(0), UnitCost: ptr(1), UsageDistribution: []float64{1}}},
+			errContains: "allowance must be positive",
+		},
+		{
+			name: "bad_percentile",
+			components: []domain.ComponentData{
+				{Name: "A", Allowance: ptr(10), UnitCost: ptr(1), UsageDistribution: []float64{1}, AllowancePercentile: ptr(90)},
+			},
+			errContains: "allowance_percentile must be in (0, 1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.OverProvisioning(&domain.AppraisalInput{Components: tt.components})
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.errContains)
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// PartnerCostRatio tests
+// ---------------------------------------------------------------------------
+
+func TestPartnerCostRatio(t *testing.T) {
+	streamCo, filmCo := "StreamCo", "FilmCo"
+	calc := New()
+
+	result, err := calc.PartnerCostRatio(&domain.AppraisalInput{
+		Financials: &domain.FinancialData{
+			AverageCustomerCount:  ptr(100),
+			PremiumRevenue:        ptr(5000),
+			BaseRevenue:           ptr(3000),
+			RevenuePerCustomer:    ptr(50),
+			DirectCostPerCustomer: ptr(10),
+			SharedCostPerCustomer: ptr(2),
+			PartnerLicensingCost:  ptr(8),
+		},
+		Components: []domain.ComponentData{
+			{Name: "Music", Partner: &streamCo, DirectCost: ptr(3)},
+			{Name: "Video", Partner: &filmCo, DirectCost: ptr(2)},
+			{Name: "Cloud", DirectCost: ptr(1)},
+			{Name: "Kids Video", Partner: &filmCo, MarginalCost: ptr(1)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Delta: (5000 - 3000) / 100 = 20; partner cost 8 -> 40%
+	if !almostEqual(result.PremiumRevenueDelta, 20, epsilon) {
+		t.Errorf("PremiumRevenueDelta = %v, want 20", result.PremiumRevenueDelta)
+	}
+	if !almostEqual(result.PartnerCostPerCustomer, 8, epsilon) {
+		t.Errorf("PartnerCostPerCustomer = %v, want 8", result.PartnerCostPerCustomer)
+	}
+	if !almostEqual(result.Ratio, 0.4, epsilon) || result.Passes {
+		t.Errorf("Ratio = %v (passes %v), want 0.4 failing", result.Ratio, result.Passes)
+	}
+	if result.Interpretation != "partner_costs_exceed_threshold" {
+		t.Errorf("Interpretation = %q", result.Interpretation)
+	}
+	// Margin: 50 - (10 + 2 + 8) = 30
+	if !almostEqual(result.MarginPerCustomer, 30, epsilon) {
+		t.Errorf("MarginPerCustomer = %v, want 30", result.MarginPerCustomer)
+	}
+	// Attributed shares 3/6 and 3/6 -> HHI 0.5
+	if !almostEqual(result.HHI, 0.5, epsilon) || result.Concentration != "high" {
+		t.Errorf("HHI = %v (%s), want 0.5 high", result.HHI, result.Concentration)
+	}
+	if result.TopPartner != "StreamCo" {
+		t.Errorf("TopPartner = %q, want StreamCo", result.TopPartner)
+	}
+	if result.TopPartnerMarginDependence == nil || !almostEqual(*result.TopPartnerMarginDependence, 0.1, epsilon) {
+		t.Errorf("TopPartnerMarginDependence = %v, want 0.1", result.TopPartnerMarginDependence)
+	}
+
+	if len(result.Partners) != 3 {
+		t.Fatalf("got %d partners, want 3", len(result.Partners))
+	}
+	film := result.Partners[1]
+	if film.Partner != "FilmCo" || len(film.Components) != 2 {
+		t.Errorf("partner[1] = %q with %v, want FilmCo with 2 components", film.Partner, film.Components)
+	}
+	if !almostEqual(film.CostShare, 0.375, epsilon) || !almostEqual(film.DeltaRatio, 0.15, epsilon) {
+		t.Errorf("FilmCo share/delta ratio = %v/%v, want 0.375/0.15", film.CostShare, film.DeltaRatio)
+	}
+	if film.BreakEvenPriceIncrease == nil || !almostEqual(*film.BreakEvenPriceIncrease, 10, epsilon) {
+		t.Errorf("FilmCo BreakEvenPriceIncrease = %v, want 10", film.BreakEvenPriceIncrease)
+	}
+	rest := result.Partners[2]
+	if rest.Partner != "unattributed" || !almostEqual(rest.Cost, 2, epsilon) {
+		t.Errorf("partner[2] = %q %v, want unattributed 2", rest.Partner, rest.Cost)
+	}
+}
+
+func TestPartnerCostRatioSinglePartner(t *testing.T) {
+	partner := "InsureCo"
+	calc := New()
+
+	result, err := calc.PartnerCostRatio(&domain.AppraisalInput{
+		Financials: &domain.FinancialData{
+			AverageCustomerCount: ptr(10),
+			PremiumRevenue:       ptr(500),
+			BaseRevenue:          ptr(300),
+		},
+		Components: []domain.ComponentData{
+			{Name: "Insurance", Partner: &partner, DirectCost: ptr(4)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Delta 20, cost 4 -> 20%; revenue falls back to premium RPC 50 -> margin 46
+	if !almostEqual(result.Ratio, 0.2, epsilon) || !result.Passes {
+		t.Errorf("Ratio = %v (passes %v), want 0.2 passing", result.Ratio, result.Passes)
+	}
+	if !almostEqual(result.MarginPerCustomer, 46, epsilon) {
+		t.Errorf("MarginPerCustomer = %v, want 46", result.MarginPerCustomer)
+	}
+	if !almostEqual(result.HHI, 1, epsilon) || result.Concentration != "high" {
+		t.Errorf("HHI = %v (%s), want 1 high", result.HHI, result.Concentration)
+	}
+	if len(result.Partners) != 1 {
+		t.Errorf("got %d partners, want 1", len(result.Partners))
+	}
+}
+
+func TestPartnerCostRatioErrors(t *testing.T) {
+	streamCo, filmCo := "StreamCo", "FilmCo"
+	calc := New()
+
+	tests := []struct {
+		name        string
+		input       *domain.AppraisalInput
+		errContains string
+	}{
+		{name: "nil_financials", input: &domain.AppraisalInput{}, errContains: "financial data required"},
+		{
+			name:        "missing_customer_count",
+			input:       &domain.AppraisalInput{Financials: &domain.FinancialData{}},
+			errContains: "average_customer_count required",
+		},
+		{
+			name: "missing_revenues",
+			input: &domain.AppraisalInput{Financials: &domain.FinancialData{
+				AverageCustomerCount: ptr(10),
+			}},
+			errContains: "premium_revenue and base_revenue required",
+		},
+		{
+			name: "non_positive_delta",
+			input: &domain.AppraisalInput{Financials: &domain.FinancialData{
+				AverageCustomerCount: ptr(10), PremiumRevenue: ptr(100), BaseRevenue: ptr(100),
+			}},
+			errContains: "premium revenue delta must be positive",
+		},
+		{
+			name: "no_partner_costs",
+			input: &domain.AppraisalInput{Financials: &domain.FinancialData{
+				AverageCustomerCount: ptr(10), PremiumRevenue: ptr(200), BaseRevenue: ptr(100),
+			}},
+			errContains: "partner_licensing_cost or component partner attribution required",
+		},
+		{
+			name: "licensing_below_attributed",
+			input: &domain.AppraisalInput{
+				Financials: &domain.FinancialData{
+					AverageCustomerCount: ptr(10), PremiumRevenue: ptr(200), BaseRevenue: ptr(100), PartnerLicensingCost: ptr(3),
+				},
+				Components: []domain.ComponentData{
+					{Name: "Music", Partner: &streamCo, DirectCost: ptr(3)},
+					{Name: "Video", Partner: &filmCo, DirectCost: ptr(2)},
+				},
+			},
+			errContains: "partner_licensing_cost 3 is below the 5 attributed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.PartnerCostRatio(tt.input)
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.errContains)
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestNew verifies constructor
+// ---------------------------------------------------------------------------
+
+func TestNew(t *testing.T) {
+	calc := New()
+	if calc == nil {
+		t.Fatal("New() returned nil")
+	}
 }
@@ -0,0 +1,397 @@
+package bundle
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/dates"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// activationWindow is the window after a customer's start in which first use
+// counts as activation.
+const activationWindow = 30 * 24 * time.Hour
+
+// Engagement computes the Component Engagement Rate and related usage metrics
+// from raw usage events (usage_events).
+//
+// Denominator: usage_events.customers if listed, else total_customers, else
+// distinct customers seen in events.
+// Activation30d = customers whose first use is within 30 days of their start
+// date (default: period start) / denominator.
+// MonthlyActiveRate = mean over calendar months of active customers / denominator.
+// EngagementRate = events / (denominator * months).
+// EngagementDepth = events / active customer-months.
+//
+// The input is not modified; WithEngagement applies the derived rates to a
+// copy for the calculators that read them.
+func (c *Calculator) Engagement(input *domain.AppraisalInput) (*domain.EngagementResult, error) {
+	if input.UsageEvents == nil {
+		return nil, fmt.Errorf("usage_events required")
+	}
+	ue := input.UsageEvents
+
+	events := append([]domain.UsageEvent(nil), ue.Events...)
+	if ue.File != nil {
+		format := ""
+		if ue.Format != nil {
+			format = *ue.Format
+		}
+		loaded, err := LoadUsageEvents(*ue.File, format)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, loaded...)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("at least one usage event required")
+	}
+
+	type parsedEvent struct {
+		customer  string
+		component string
+		at        time.Time
+	}
+	parsed := make([]parsedEvent, 0, len(events))
+	var first, last time.Time
+	for i, ev := range events {
+		if ev.Customer == "" || ev.Component == "" {
+			return nil, fmt.Errorf("event %d: customer and component required", i+1)
+		}
+		at, err := dates.Parse(ev.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i+1, err)
+		}
+		if first.IsZero() || at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+		parsed = append(parsed, parsedEvent{ev.Customer, ev.Component, at})
+	}
+
+	// Events are kept through end; a date-only period_end covers that whole day.
+	start, end, until := first, last, last
+	if ue.PeriodStart != nil {
+		t, err := dates.Parse(*ue.PeriodStart)
+		if err != nil {
+			return nil, fmt.Errorf("period_start: %w", err)
+		}
+		start = t
+	}
+	if ue.PeriodEnd != nil {
+		t, err := dates.Parse(*ue.PeriodEnd)
+		if err != nil {
+			return nil, fmt.Errorf("period_end: %w", err)
+		}
+		end, until = t, t
+		if isDate(*ue.PeriodEnd) {
+			until = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("period_end must not be before period_start")
+	}
+
+	// Customer population and start dates
+	starts := make(map[string]time.Time)
+	for _, cust := range ue.Customers {
+		at := start
+		if cust.StartDate != nil {
+			t, err := dates.Parse(*cust.StartDate)
+			if err != nil {
+				return nil, fmt.Errorf("customer %q start_date: %w", cust.ID, err)
+			}
+			at = t
+		}
+		starts[cust.ID] = at
+	}
+	listed := len(ue.Customers) > 0
+
+	seen := make(map[string]bool)
+	for _, ev := range parsed {
+		seen[ev.customer] = true
+	}
+	denominator := float64(len(seen))
+	switch {
+	case listed:
+		denominator = float64(len(starts))
+	case ue.TotalCustomers != nil:
+		if *ue.TotalCustomers <= 0 {
+			return nil, fmt.Errorf("total_customers must be positive")
+		}
+		denominator = *ue.TotalCustomers
+	}
+
+	months := monthsBetween(start, end)
+
+	type usage struct {
+		events       int
+		firstUse     map[string]time.Time
+		monthly      map[string]map[int]int // customer -> month index -> events
+		activeMonths []map[string]bool
+	}
+	byComponent := make(map[string]*usage)
+	var order []string
+	counted := 0
+
+	for _, ev := range parsed {
+		if ev.at.Before(start) || ev.at.After(until) {
+			continue
+		}
+		if listed {
+			if _, ok := starts[ev.customer]; !ok {
+				continue
+			}
+		}
+		u, ok := byComponent[ev.component]
+		if !ok {
+			u = &usage{
+				firstUse:     make(map[string]time.Time),
+				monthly:      make(map[string]map[int]int),
+				activeMonths: make([]map[string]bool, months),
+			}
+			for m := range u.activeMonths {
+				u.activeMonths[m] = make(map[string]bool)
+			}
+			byComponent[ev.component] = u
+			order = append(order, ev.component)
+		}
+
+		counted++
+		u.events++
+		if prev, ok := u.firstUse[ev.customer]; !ok || ev.at.Before(prev) {
+			u.firstUse[ev.customer] = ev.at
+		}
+		m := monthIndex(start, ev.at)
+		u.activeMonths[m][ev.customer] = true
+		if u.monthly[ev.customer] == nil {
+			u.monthly[ev.customer] = make(map[int]int)
+		}
+		u.monthly[ev.customer][m]++
+	}
+	if counted == 0 {
+		return nil, fmt.Errorf("no usage events within the analysis period")
+	}
+
+	result := &domain.EngagementResult{
+		Customers:   int(denominator),
+		Events:      counted,
+		PeriodStart: start.Format("2006-01-02"),
+		PeriodEnd:   end.Format("2006-01-02"),
+		Months:      months,
+	}
+
+	customerMonths := denominator * float64(months)
+	for _, name := range order {
+		u := byComponent[name]
+
+		activated := 0
+		for cust, firstUse := range u.firstUse {
+			custStart, ok := starts[cust]
+			if !ok {
+				custStart = start
+			}
+			if !firstUse.Before(custStart) && firstUse.Sub(custStart) < activationWindow {
+				activated++
+			}
+		}
+
+		activeCustomerMonths := 0
+		for _, active := range u.activeMonths {
+			activeCustomerMonths += len(active)
+		}
+
+		// Frequency distribution over all customer-months.
+		counts := []float64{0, 0, 0, 0, 0}
+		for _, perMonth := range u.monthly {
+			for _, n := range perMonth {
+				counts[frequencyBucket(n)]++
+			}
+		}
+		counts[0] = customerMonths - float64(activeCustomerMonths)
+		labels := []string{"0", "1", "2-4", "5-9", "10+"}
+		var freq []domain.FrequencyBucket
+		for i, label := range labels {
+			freq = append(freq, domain.FrequencyBucket{Label: label, Share: counts[i] / customerMonths})
+		}
+
+		ce := domain.ComponentEngagement{
+			Name:                  name,
+			Events:                u.events,
+			ActiveCustomers:       len(u.firstUse),
+			Activation30d:         float64(activated) / denominator,
+			MonthlyActiveRate:     float64(activeCustomerMonths) / customerMonths,
+			EngagementRate:        float64(u.events) / customerMonths,
+			FrequencyDistribution: freq,
+		}
+		if activeCustomerMonths > 0 {
+			ce.EngagementDepth = float64(u.events) / float64(activeCustomerMonths)
+		}
+		result.Components = append(result.Components, ce)
+	}
+
+	return result, nil
+}
+
+// WithEngagement returns a copy of input whose components carry the measured
+// activation and monthly active rates, appending entries for components not
+// yet listed. input itself is left unchanged.
+func WithEngagement(input *domain.AppraisalInput, measured []domain.ComponentEngagement) *domain.AppraisalInput {
+	out := *input
+	out.Components = append([]domain.ComponentData(nil), input.Components...)
+	for _, ce := range measured {
+		activation := ce.Activation30d
+		monthly := ce.MonthlyActiveRate
+
+		found := false
+		for i := range out.Components {
+			if out.Components[i].Name == ce.Name {
+				out.Components[i].Activation30d = &activation
+				out.Components[i].MonthlyActiveRate = &monthly
+				found = true
+				break
+			}
+		}
+		if !found {
+			out.Components = append(out.Components, domain.ComponentData{
+				Name:              ce.Name,
+				Activation30d:     &activation,
+				MonthlyActiveRate: &monthly,
+			})
+		}
+	}
+	return &out
+}
+
+// LoadUsageEvents reads usage events from a CSV or JSON Lines file.
+// format is "csv" or "jsonl"; when empty it is inferred from the extension.
+// CSV files need a header row with customer, component, and timestamp columns.
+func LoadUsageEvents(path, format string) ([]domain.UsageEvent, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".jsonl", ".ndjson":
+			format = "jsonl"
+		default:
+			return nil, fmt.Errorf("cannot infer usage event format from %q: set format to csv or jsonl", path)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening usage events: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(format) {
+	case "csv":
+		return readUsageCSV(f)
+	case "jsonl":
+		return readUsageJSONL(f)
+	default:
+		return nil, fmt.Errorf("unknown usage event format %q: use csv or jsonl", format)
+	}
+}
+
+// readUsageCSV parses CSV usage events with a header row (columns in any order).
+func readUsageCSV(r io.Reader) ([]domain.UsageEvent, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	cols := map[string]int{"customer": -1, "component": -1, "timestamp": -1}
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := cols[key]; ok {
+			cols[key] = i
+		}
+	}
+	for name, idx := range cols {
+		if idx < 0 {
+			return nil, fmt.Errorf("CSV header missing %q column", name)
+		}
+	}
+
+	var events []domain.UsageEvent
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV line %d: %w", line, err)
+		}
+		events = append(events, domain.UsageEvent{
+			Customer:  record[cols["customer"]],
+			Component: record[cols["component"]],
+			Timestamp: record[cols["timestamp"]],
+		})
+	}
+	return events, nil
+}
+
+// readUsageJSONL parses one JSON usage event per line, skipping blank lines.
+func readUsageJSONL(r io.Reader) ([]domain.UsageEvent, error) {
+	var events []domain.UsageEvent
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var ev domain.UsageEvent
+		if err := json.Unmarshal([]byte(text), &ev); err != nil {
+			return nil, fmt.Errorf("parsing JSONL line %d: %w", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading JSONL: %w", err)
+	}
+	return events, nil
+}
+
+// isDate reports whether s is a plain YYYY-MM-DD date without a time.
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	return err == nil
+}
+
+// monthsBetween counts the calendar months touched by [start, end].
+func monthsBetween(start, end time.Time) int {
+	return monthIndex(start, end) + 1
+}
+
+// monthIndex is the calendar month offset of t from start (0 = start's month).
+func monthIndex(start, t time.Time) int {
+	return (t.Year()-start.Year())*12 + int(t.Month()) - int(start.Month())
+}
+
+// frequencyBucket maps a monthly event count to its bucket index.
+func frequencyBucket(n int) int {
+	switch {
+	case n <= 0:
+		return 0
+	case n == 1:
+		return 1
+	case n <= 4:
+		return 2
+	case n <= 9:
+		return 3
+	default:
+		return 4
+	}
+}
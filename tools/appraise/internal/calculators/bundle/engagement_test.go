@@ -0,0 +1,256 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func engagementInput() *domain.AppraisalInput {
+	startC, periodStart, periodEnd := "2025-02-01", "2025-01-01", "2025-03-31"
+	return &domain.AppraisalInput{
+		Components: []domain.ComponentData{
+			{Name: "Streaming", MonthlyActiveRate: ptr(0.9)},
+		},
+		UsageEvents: &domain.UsageEventData{
+			Customers: []domain.UsageCustomer{
+				{ID: "A"},
+				{ID: "B"},
+				{ID: "C", StartDate: &startC},
+				{ID: "D"},
+			},
+			PeriodStart: &periodStart,
+			PeriodEnd:   &periodEnd,
+			Events: []domain.UsageEvent{
+				{Customer: "A", Component: "Streaming", Timestamp: "2025-01-05T10:00:00Z"},
+				{Customer: "A", Component: "Streaming", Timestamp: "2025-01-06"},
+				{Customer: "A", Component: "Streaming", Timestamp: "2025-02-10"},
+				{Customer: "B", Component: "Streaming", Timestamp: "2025-02-20"},
+				{Customer: "C", Component: "Streaming", Timestamp: "2025-02-15"},
+				{Customer: "C", Component: "Streaming", Timestamp: "2025-02-15"},
+				{Customer: "C", Component: "Streaming", Timestamp: "2025-02-16"},
+				{Customer: "C", Component: "Streaming", Timestamp: "2025-02-17"},
+				{Customer: "C", Component: "Streaming", Timestamp: "2025-02-18"},
+				{Customer: "D", Component: "Gaming", Timestamp: "2025-03-01"},
+				{Customer: "E", Component: "Gaming", Timestamp: "2025-03-02"},           // not listed: ignored
+				{Customer: "D", Component: "Gaming", Timestamp: "2025-03-31T15:00:00Z"}, // within a date-only period_end
+			},
+		},
+	}
+}
+
+func TestEngagement(t *testing.T) {
+	calc := New()
+	input := engagementInput()
+
+	result, err := calc.Engagement(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Customers != 4 || result.Events != 11 || result.Months != 3 {
+		t.Errorf("customers/events/months = %d/%d/%d, want 4/11/3", result.Customers, result.Events, result.Months)
+	}
+	if result.PeriodStart != "2025-01-01" || result.PeriodEnd != "2025-03-31" {
+		t.Errorf("period = %s..%s, want 2025-01-01..2025-03-31", result.PeriodStart, result.PeriodEnd)
+	}
+	if len(result.Components) != 2 {
+		t.Fatalf("got %d components, want 2", len(result.Components))
+	}
+
+	s := result.Components[0]
+	if s.Name != "Streaming" || s.Events != 9 || s.ActiveCustomers != 3 {
+		t.Errorf("Streaming = %s events=%d active=%d, want Streaming 9 3", s.Name, s.Events, s.ActiveCustomers)
+	}
+	// A and C activate within 30 days of start; B first uses on day 50.
+	if !almostEqual(s.Activation30d, 0.5, epsilon) {
+		t.Errorf("Activation30d = %v, want 0.5", s.Activation30d)
+	}
+	// Active customer-months: Jan {A}, Feb {A, B, C} = 4 of 12
+	if !almostEqual(s.MonthlyActiveRate, 4.0/12, epsilon) {
+		t.Errorf("MonthlyActiveRate = %v, want %v", s.MonthlyActiveRate, 4.0/12)
+	}
+	if !almostEqual(s.EngagementRate, 9.0/12, epsilon) {
+		t.Errorf("EngagementRate = %v, want %v", s.EngagementRate, 9.0/12)
+	}
+	if !almostEqual(s.EngagementDepth, 2.25, epsilon) {
+		t.Errorf("EngagementDepth = %v, want 2.25", s.EngagementDepth)
+	}
+
+	wantFreq := []struct {
+		label string
+		share float64
+	}{
+		{"0", 8.0 / 12},
+		{"1", 2.0 / 12},   // A Feb, B Feb
+		{"2-4", 1.0 / 12}, // A Jan
+		{"5-9", 1.0 / 12}, // C Feb
+		{"10+", 0},
+	}
+	if len(s.FrequencyDistribution) != len(wantFreq) {
+		t.Fatalf("got %d frequency buckets, want %d", len(s.FrequencyDistribution), len(wantFreq))
+	}
+	for i, want := range wantFreq {
+		got := s.FrequencyDistribution[i]
+		if got.Label != want.label || !almostEqual(got.Share, want.share, epsilon) {
+			t.Errorf("bucket %d = %s %v, want %s %v", i, got.Label, got.Share, want.label, want.share)
+		}
+	}
+
+	// Measured values replace pre-aggregated ones on a copy; unseen components
+	// are appended.
+	measured := WithEngagement(input, result.Components)
+	if len(input.Components) != 1 || *input.Components[0].MonthlyActiveRate != 0.9 {
+		t.Errorf("input components modified: %+v", input.Components)
+	}
+	if len(measured.Components) != 2 {
+		t.Fatalf("copy has %d components, want 2", len(measured.Components))
+	}
+	checkOptional(t, "Streaming MonthlyActiveRate", measured.Components[0].MonthlyActiveRate, ptr(4.0/12))
+	checkOptional(t, "Streaming Activation30d", measured.Components[0].Activation30d, ptr(0.5))
+	if measured.Components[1].Name != "Gaming" {
+		t.Errorf("appended component = %q, want Gaming", measured.Components[1].Name)
+	}
+	// D's first use on day 59 of the period is outside the activation window.
+	checkOptional(t, "Gaming Activation30d", measured.Components[1].Activation30d, ptr(0))
+	checkOptional(t, "Gaming MonthlyActiveRate", measured.Components[1].MonthlyActiveRate, ptr(1.0/12))
+
+	dw, err := calc.DeadWeightRatio(measured)
+	if err != nil {
+		t.Fatalf("DeadWeightRatio: %v", err)
+	}
+	if !almostEqual(dw.DeadWeightRatio, 0.5, epsilon) {
+		t.Errorf("DeadWeightRatio from measured usage = %v, want 0.5", dw.DeadWeightRatio)
+	}
+}
+
+func TestEngagementDenominator(t *testing.T) {
+	events := []domain.UsageEvent{
+		{Customer: "A", Component: "Music", Timestamp: "2025-01-01"},
+		{Customer: "B", Component: "Music", Timestamp: "2025-01-15"},
+	}
+
+	tests := []struct {
+		name  string
+		total *float64
+		want  float64
+	}{
+		{"distinct customers in events", nil, 1},
+		{"total_customers", ptr(8), 0.25},
+	}
+
+	calc := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Engagement(&domain.AppraisalInput{
+				UsageEvents: &domain.UsageEventData{Events: events, TotalCustomers: tt.total},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Months != 1 {
+				t.Errorf("Months = %d, want 1", result.Months)
+			}
+			if !almostEqual(result.Components[0].MonthlyActiveRate, tt.want, epsilon) {
+				t.Errorf("MonthlyActiveRate = %v, want %v", result.Components[0].MonthlyActiveRate, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngagementErrors(t *testing.T) {
+	periodStart, periodEnd := "2025-01-01", "2025-03-31"
+	tests := []struct {
+		name    string
+		events  *domain.UsageEventData
+		wantErr string
+	}{
+		{"missing usage events", nil, "usage_events required"},
+		{"no events", &domain.UsageEventData{}, "at least one usage event"},
+		{
+			"bad timestamp",
+			&domain.UsageEventData{Events: []domain.UsageEvent{{Customer: "A", Component: "X", Timestamp: "yesterday"}}},
+			"invalid date",
+		},
+		{
+			"missing component",
+			&domain.UsageEventData{Events: []domain.UsageEvent{{Customer: "A", Timestamp: "2025-01-01"}}},
+			"customer and component required",
+		},
+		{
+			"events outside period",
+			&domain.UsageEventData{
+				Events:      []domain.UsageEvent{{Customer: "A", Component: "X", Timestamp: "2024-06-01"}},
+				PeriodStart: &periodStart,
+				PeriodEnd:   &periodEnd,
+			},
+			"no usage events within",
+		},
+	}
+
+	calc := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.Engagement(&domain.AppraisalInput{UsageEvents: tt.events})
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadUsageEvents(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"events.csv":   "Timestamp,Customer,Component\n2025-01-01,A,Music\n2025-01-02T08:00:00Z,B,Video\n",
+		"events.jsonl": "{\"customer\":\"A\",\"component\":\"Music\",\"timestamp\":\"2025-01-01\"}\n\n{\"customer\":\"B\",\"component\":\"Video\",\"timestamp\":\"2025-01-02T08:00:00Z\"}\n",
+		"events.txt":   "",
+		"missing.csv":  "customer,timestamp\nA,2025-01-01\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		format  string
+		wantErr string
+	}{
+		{"csv by extension", "events.csv", "", ""},
+		{"jsonl by extension", "events.jsonl", "", ""},
+		{"explicit format", "events.jsonl", "jsonl", ""},
+		{"unknown extension", "events.txt", "", "cannot infer"},
+		{"unknown format", "events.csv", "xml", "unknown usage event format"},
+		{"missing column", "missing.csv", "", "missing \"component\""},
+		{"missing file", "nope.csv", "", "opening usage events"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := LoadUsageEvents(filepath.Join(dir, tt.file), tt.format)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(events) != 2 {
+				t.Fatalf("got %d events, want 2", len(events))
+			}
+			if events[1].Customer != "B" || events[1].Component != "Video" || events[1].Timestamp != "2025-01-02T08:00:00Z" {
+				t.Errorf("event[1] = %+v", events[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package customer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/dates"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// cohortSums accumulates one cohort, or several pooled, by months since
+// acquisition.
+type cohortSums struct {
+	customers    []int // cohort customers observed at this month
+	active       []int
+	revenue      []float64
+	capped       []float64 // revenue, each customer capped at their month-0 revenue
+	startRevenue []float64 // month-0 revenue of the cohorts observed at this month
+	lost, base   int       // month-to-month churn transitions
+}
+
+// Cohorts builds the classic cohort matrix from customer-month activity
+// (customers.customer_activity): customers are grouped by the month of their
+// first record, and every later month of data is a column of months since
+// acquisition. Per cohort it gives active customers and retention, and with
+// revenue on the records, revenue, NRR, GRR and ARPU:
+//
+//	NRR(k)  = cohort revenue in month k / cohort revenue in month 0
+//	GRR(k)  = same, each customer's revenue capped at their month-0 revenue
+//	ARPU(k) = cohort revenue in month k / active customers in month k
+//
+// A customer absent in a month is inactive that month and counts again if
+// they come back. Monthly churn is the share of customers active in one month
+// and inactive the next. The average curve pools all cohorts observed at
+// each month, so later months rest on the older cohorts only; its monthly
+// churn can be used as churn_before / churn_after.
+func (c *Calculator) Cohorts(input *domain.AppraisalInput) (*domain.CohortsResult, error) {
+	if input.Customers == nil || len(input.Customers.CustomerActivity) == 0 {
+		return nil, fmt.Errorf("customers.customer_activity required")
+	}
+	records := input.Customers.CustomerActivity
+
+	hasRevenue := records[0].Revenue != nil
+	months := map[string]map[int]float64{} // customer -> month -> revenue
+	var customers []string
+	last := math.MinInt
+	for i, r := range records {
+		if r.Customer == "" {
+			return nil, fmt.Errorf("customer_activity[%d]: customer required", i)
+		}
+		month, err := parseMonth(r.Month)
+		if err != nil {
+			return nil, fmt.Errorf("customer_activity[%d]: %w", i, err)
+		}
+		if (r.Revenue != nil) != hasRevenue {
+			return nil, fmt.Errorf("customer_activity[%d]: revenue must be given on all records or none", i)
+		}
+		if _, ok := months[r.Customer]; !ok {
+			months[r.Customer] = map[int]float64{}
+			customers = append(customers, r.Customer)
+		}
+		// Several records in one month add up.
+		rev := 0.0
+		if r.Revenue != nil {
+			rev = *r.Revenue
+		}
+		months[r.Customer][month] += rev
+		if month > last {
+			last = month
+		}
+	}
+
+	byCohort := map[int][]string{}
+	for _, id := range customers {
+		first := math.MaxInt
+		for m := range months[id] {
+			if m < first {
+				first = m
+			}
+		}
+		byCohort[first] = append(byCohort[first], id)
+	}
+	cohortMonths := make([]int, 0, len(byCohort))
+	for m := range byCohort {
+		cohortMonths = append(cohortMonths, m)
+	}
+	sort.Ints(cohortMonths)
+
+	result := &domain.CohortsResult{Customers: len(customers)}
+	var pooled cohortSums
+	for _, cm := range cohortMonths {
+		ids := byCohort[cm]
+		width := last - cm + 1
+		sums := cohortSums{
+			customers:    make([]int, width),
+			active:       make([]int, width),
+			revenue:      make([]float64, width),
+			capped:       make([]float64, width),
+			startRevenue: make([]float64, width),
+		}
+		for _, id := range ids {
+			start := months[id][cm]
+			for k := 0; k < width; k++ {
+				rev, active := months[id][cm+k]
+				if active {
+					sums.active[k]++
+					sums.revenue[k] += rev
+					sums.capped[k] += math.Min(rev, start)
+				}
+				if k > 0 {
+					if _, was := months[id][cm+k-1]; was {
+						sums.base++
+						if !active {
+							sums.lost++
+						}
+					}
+				}
+			}
+		}
+		for k := range sums.customers {
+			sums.customers[k] = len(ids)
+			sums.startRevenue[k] = sums.revenue[0]
+		}
+
+		result.Cohorts = append(result.Cohorts, domain.CohortRow{
+			Cohort:      monthName(cm),
+			Customers:   len(ids),
+			CohortCurve: sums.curve(hasRevenue),
+		})
+		pooled.add(sums)
+	}
+	result.Average = pooled.curve(hasRevenue)
+
+	return result, nil
+}
+
+// add pools another cohort into s, month by month.
+func (s *cohortSums) add(o cohortSums) {
+	for len(s.customers) < len(o.customers) {
+		s.customers = append(s.customers, 0)
+		s.active = append(s.active, 0)
+		s.revenue = append(s.revenue, 0)
+		s.capped = append(s.capped, 0)
+		s.startRevenue = append(s.startRevenue, 0)
+	}
+	for k := range o.customers {
+		s.customers[k] += o.customers[k]
+		s.active[k] += o.active[k]
+		s.revenue[k] += o.revenue[k]
+		s.capped[k] += o.capped[k]
+		s.startRevenue[k] += o.startRevenue[k]
+	}
+	s.lost += o.lost
+	s.base += o.base
+}
+
+func (s cohortSums) curve(hasRevenue bool) domain.CohortCurve {
+	curve := domain.CohortCurve{
+		Active:    s.active,
+		Retention: make([]float64, len(s.active)),
+	}
+	for k, n := range s.active {
+		curve.Retention[k] = float64(n) / float64(s.customers[k])
+	}
+	if s.base > 0 {
+		curve.MonthlyChurn = float64(s.lost) / float64(s.base)
+	}
+	if !hasRevenue {
+		return curve
+	}
+
+	curve.Revenue = s.revenue
+	curve.NRR = make([]float64, len(s.revenue))
+	curve.GRR = make([]float64, len(s.revenue))
+	curve.ARPU = make([]float64, len(s.revenue))
+	for k, rev := range s.revenue {
+		if s.startRevenue[k] != 0 {
+			curve.NRR[k] = rev / s.startRevenue[k]
+			curve.GRR[k] = s.capped[k] / s.startRevenue[k]
+		}
+		if s.active[k] > 0 {
+			curve.ARPU[k] = rev / float64(s.active[k])
+		}
+	}
+	return curve
+}
+
+// parseMonth parses YYYY-MM, or a date, into a month number.
+func parseMonth(s string) (int, error) {
+	t, err := time.Parse("2006-01", strings.TrimSpace(s))
+	if err != nil {
+		if t, err = dates.Parse(s); err != nil {
+			return 0, fmt.Errorf("invalid month %q (use YYYY-MM)", s)
+		}
+	}
+	return t.Year()*12 + int(t.Month()) - 1, nil
+}
+
+func monthName(m int) string {
+	return fmt.Sprintf("%04d-%02d", m/12, m%12+1)
+}
@@ -0,0 +1,180 @@
+package customer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func floatsEqual(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !almostEqual(got[i], want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ---------------------------------------------------------------------------
+// Cohorts
+// ---------------------------------------------------------------------------
+
+func TestCohorts(t *testing.T) {
+	calc := New()
+
+	// activity returns the two-cohort history, with revenue when withRevenue
+	// is set, after applying mod.
+	activity := func(withRevenue bool, mod func([]domain.CustomerMonth)) []domain.CustomerMonth {
+		month := func(customer, month string, revenue float64) domain.CustomerMonth {
+			m := domain.CustomerMonth{Customer: customer, Month: month}
+			if withRevenue {
+				m.Revenue = ptr(revenue)
+			}
+			return m
+		}
+		ms := []domain.CustomerMonth{
+			// January cohort: a expands, b skips February, c churns
+			month("a", "2024-01", 10), month("a", "2024-02", 12), month("a", "2024-03", 12),
+			month("b", "2024-01", 10), month("b", "2024-03", 10),
+			month("c", "2024-01", 20),
+			// February cohort: d contracts, e (two records) churns
+			month("d", "2024-02", 10), month("d", "2024-03", 5),
+			month("e", "2024-02-15", 6), month("e", "2024-02", 4),
+		}
+		if mod != nil {
+			mod(ms)
+		}
+		return ms
+	}
+
+	type curve struct {
+		retention, revenue, nrr, grr, arpu []float64
+		churn                              float64
+	}
+	type cohort struct {
+		name      string
+		customers int
+		curve     curve
+	}
+
+	tests := []struct {
+		name          string
+		activity      []domain.CustomerMonth
+		wantErr       bool
+		errContains   string
+		wantCustomers int
+		wantCohorts   []cohort
+		wantAverage   curve
+	}{
+		{
+			name:          "with_revenue",
+			activity:      activity(true, nil),
+			wantCustomers: 5,
+			wantCohorts: []cohort{
+				{"2024-01", 3, curve{
+					retention: []float64{1, 1.0 / 3, 2.0 / 3},
+					revenue:   []float64{40, 12, 22},
+					nrr:       []float64{1, 0.3, 0.55},
+					grr:       []float64{1, 0.25, 0.5}, // a capped at 10
+					arpu:      []float64{40.0 / 3, 12, 11},
+					churn:     0.5, // b and c lost of 4 transitions
+				}},
+				{"2024-02", 2, curve{
+					retention: []float64{1, 0.5},
+					revenue:   []float64{20, 5},
+					nrr:       []float64{1, 0.25},
+					grr:       []float64{1, 0.25},
+					arpu:      []float64{10, 5},
+					churn:     0.5,
+				}},
+			},
+			wantAverage: curve{
+				retention: []float64{1, 0.4, 2.0 / 3},
+				revenue:   []float64{60, 17, 22},
+				nrr:       []float64{1, 17.0 / 60, 0.55},
+				grr:       []float64{1, 0.25, 0.5},
+				arpu:      []float64{12, 8.5, 11},
+				churn:     0.5,
+			},
+		},
+		{
+			name:          "activity_only",
+			activity:      activity(false, nil),
+			wantCustomers: 5,
+			wantCohorts: []cohort{
+				{"2024-01", 3, curve{retention: []float64{1, 1.0 / 3, 2.0 / 3}, churn: 0.5}},
+				{"2024-02", 2, curve{retention: []float64{1, 0.5}, churn: 0.5}},
+			},
+			wantAverage: curve{retention: []float64{1, 0.4, 2.0 / 3}, churn: 0.5},
+		},
+		{
+			name:        "no_activity",
+			wantErr:     true,
+			errContains: "customer_activity required",
+		},
+		{
+			name:        "no_customer",
+			activity:    activity(true, func(ms []domain.CustomerMonth) { ms[2].Customer = "" }),
+			wantErr:     true,
+			errContains: "customer_activity[2]: customer required",
+		},
+		{
+			name:        "bad_month",
+			activity:    activity(true, func(ms []domain.CustomerMonth) { ms[1].Month = "Feb 2024" }),
+			wantErr:     true,
+			errContains: `invalid month "Feb 2024"`,
+		},
+		{
+			name:        "mixed_revenue",
+			activity:    activity(true, func(ms []domain.CustomerMonth) { ms[3].Revenue = nil }),
+			wantErr:     true,
+			errContains: "all records or none",
+		},
+	}
+
+	checkCurve := func(t *testing.T, name string, g domain.CohortCurve, w curve) {
+		t.Helper()
+		if !floatsEqual(g.Retention, w.retention) || !floatsEqual(g.Revenue, w.revenue) {
+			t.Errorf("%s retention/revenue = %v/%v, want %v/%v", name, g.Retention, g.Revenue, w.retention, w.revenue)
+		}
+		if !floatsEqual(g.NRR, w.nrr) || !floatsEqual(g.GRR, w.grr) || !floatsEqual(g.ARPU, w.arpu) {
+			t.Errorf("%s nrr/grr/arpu = %v/%v/%v, want %v/%v/%v", name, g.NRR, g.GRR, g.ARPU, w.nrr, w.grr, w.arpu)
+		}
+		if !almostEqual(g.MonthlyChurn, w.churn) {
+			t.Errorf("%s MonthlyChurn = %v, want %v", name, g.MonthlyChurn, w.churn)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Cohorts(&domain.AppraisalInput{Customers: &domain.CustomerMetrics{CustomerActivity: tt.activity}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Customers != tt.wantCustomers || len(result.Cohorts) != len(tt.wantCohorts) {
+				t.Fatalf("customers/cohorts = %d/%d, want %d/%d", result.Customers, len(result.Cohorts), tt.wantCustomers, len(tt.wantCohorts))
+			}
+			for i, w := range tt.wantCohorts {
+				row := result.Cohorts[i]
+				if row.Cohort != w.name || row.Customers != w.customers {
+					t.Errorf("cohort %d = %s/%d, want %s/%d", i, row.Cohort, row.Customers, w.name, w.customers)
+				}
+				checkCurve(t, w.name, row.CohortCurve, w.curve)
+			}
+			checkCurve(t, "average", result.Average, tt.wantAverage)
+		})
+	}
+}
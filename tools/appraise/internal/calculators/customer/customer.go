@@ -3,17 +3,24 @@
 // Functions:
 //   ChurnRate              - Customers lost / total customers at start
 //   RetentionRate          - 1 - churn rate
+//   Survival               - Kaplan-Meier retention curve from customer records: median lifetime, monthly hazard, lifespan for CLV
+//   Cohorts                - Cohort matrix from customer-month activity: retention, NRR/GRR, ARPU by month since acquisition
 //   NPS                    - % promoters (9-10) - % detractors (0-6)
+//   NPSSurvey              - NPS from raw 0-10 responses: 95% CI, segment/tier/region breakdown, premium vs base z-test
 //   CSAT                   - Satisfied responses / total responses
-//   ChurnReductionImpact   - (churn_before - churn_after) / churn_before
+//   Reviews                - Review ratings across sources: weighted and Bayesian mean, top-2-box CSAT, vs competitors
+//   Topics                 - Review topic share and polarity from a keyword dictionary, top complaints, vs competitors
+//   ChurnReductionImpact   - (churn_before - churn_after) / churn_before; z-test and CI with sample sizes
 //   RevenueGrowthRate      - (revenue_t - revenue_t-1) / revenue_t-1
 //   ServiceRevenueShare    - Add-on revenue / total revenue
 package customer
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/stats"
 )
 
 // Calculator implements all customer module functions.
@@ -62,34 +69,44 @@ func (c *Calculator) RetentionRate(input *domain.AppraisalInput) (*domain.Single
 }
 
 // NPS calculates Net Promoter Score = % promoters (9-10) - % detractors (0-6).
+// Without promoters_pct and detractors_pct, it is computed from nps_responses.
 func (c *Calculator) NPS(input *domain.AppraisalInput) (*domain.SingleValueResult, error) {
 	if input.Customers == nil {
 		return nil, fmt.Errorf("customer metrics required")
 	}
 	m := input.Customers
 
-	if m.PromotersPct == nil || m.DetractorsPct == nil {
-		return nil, fmt.Errorf("promoters_pct and detractors_pct required")
+	var nps float64
+	switch {
+	case m.PromotersPct != nil && m.DetractorsPct != nil:
+		nps = *m.PromotersPct - *m.DetractorsPct
+	case len(m.NPSResponses) > 0:
+		s, err := npsStats(m.NPSResponses)
+		if err != nil {
+			return nil, err
+		}
+		nps = s.NPS
+	default:
+		return nil, fmt.Errorf("promoters_pct and detractors_pct required (or nps_responses)")
 	}
 
-	nps := *m.PromotersPct - *m.DetractorsPct
+	return &domain.SingleValueResult{
+		Value:          nps,
+		Interpretation: npsInterpretation(nps),
+	}, nil
+}
 
-	var interp string
+func npsInterpretation(nps float64) string {
 	switch {
 	case nps >= 50:
-		interp = "excellent_nps"
+		return "excellent_nps"
 	case nps >= 30:
-		interp = "good_nps"
+		return "good_nps"
 	case nps >= 0:
-		interp = "moderate_nps"
+		return "moderate_nps"
 	default:
-		interp = "negative_nps"
+		return "negative_nps"
 	}
-
-	return &domain.SingleValueResult{
-		Value:          nps,
-		Interpretation: interp,
-	}, nil
 }
 
 // CSAT calculates satisfied responses / total responses.
@@ -124,7 +141,10 @@ func (c *Calculator) CSAT(input *domain.AppraisalInput) (*domain.SingleValueResu
 }
 
 // ChurnReductionImpact calculates (churn_before - churn_after) / churn_before.
-// Measures the % improvement in churn after premium/bundle launch.
+// Measures the % improvement in churn after premium/bundle launch. With the
+// customers at risk in each period (customers_before, customers_after) it
+// adds a two-proportion z-test of the two churn rates and a 95% CI for the
+// reduction from the log relative risk.
 func (c *Calculator) ChurnReductionImpact(input *domain.AppraisalInput) (*domain.SingleValueResult, error) {
 	if input.Customers == nil {
 		return nil, fmt.Errorf("customer metrics required")
@@ -154,9 +174,51 @@ func (c *Calculator) ChurnReductionImpact(input *domain.AppraisalInput) (*domain
 		interp = "churn_increased"
 	}
 
-	return &domain.SingleValueResult{
+	result := &domain.SingleValueResult{
 		Value:          reduction,
 		Interpretation: interp,
+	}
+	if m.CustomersBefore != nil || m.CustomersAfter != nil {
+		if m.CustomersBefore == nil || m.CustomersAfter == nil {
+			return nil, fmt.Errorf("customers_before and customers_after required together")
+		}
+		sig, err := churnReductionSignificance(*m.ChurnBefore, *m.CustomersBefore, *m.ChurnAfter, *m.CustomersAfter)
+		if err != nil {
+			return nil, err
+		}
+		result.Significance = sig
+	}
+	return result, nil
+}
+
+// churnReductionSignificance tests churn before vs after and puts a CI on
+// 1 - relative risk. Half a customer is added to each count when either
+// period has no churn, so the log stays finite.
+func churnReductionSignificance(before, nBefore, after, nAfter float64) (*domain.Significance, error) {
+	churnedBefore, churnedAfter := before*nBefore, after*nAfter
+	z, p, err := stats.TwoProportionZTest(churnedBefore, nBefore, churnedAfter, nAfter)
+	if err != nil {
+		return nil, fmt.Errorf("churn significance: %w", err)
+	}
+
+	if churnedBefore == 0 || churnedAfter == 0 {
+		churnedBefore, nBefore = churnedBefore+0.5, nBefore+1
+		churnedAfter, nAfter = churnedAfter+0.5, nAfter+1
+	}
+	logRR := math.Log((churnedAfter / nAfter) / (churnedBefore / nBefore))
+	se := math.Sqrt(1/churnedAfter - 1/nAfter + 1/churnedBefore - 1/nBefore)
+	zc := stats.NormalQuantile(1 - (1-stats.DefaultConfidence)/2)
+
+	significant := p < 1-stats.DefaultConfidence
+	return &domain.Significance{
+		Test:        "two_proportion_z",
+		Statistic:   z,
+		PValue:      &p,
+		Significant: &significant,
+		Confidence:  stats.DefaultConfidence,
+		CILower:     1 - math.Exp(logRR+zc*se),
+		CIUpper:     1 - math.Exp(logRR-zc*se),
+		CIMethod:    "log_relative_risk",
 	}, nil
 }
 
@@ -337,6 +337,26 @@ func TestNPS(t *testing.T) {
 			input:   &domain.AppraisalInput{},
 			wantErr: true,
 		},
+		{
+			// 32 promoters, 21 passives and 17 detractors of 70
+			name: "from_nps_responses",
+			input: &domain.AppraisalInput{
+				Customers: &domain.CustomerMetrics{
+					NPSResponses: survey(responses(32, 10, "", ""), responses(21, 7, "", ""), responses(17, 0, "", "")),
+				},
+			},
+			wantValue:  100 * 15.0 / 70,
+			wantInterp: "moderate_nps",
+		},
+		{
+			name: "bad_nps_response",
+			input: &domain.AppraisalInput{
+				Customers: &domain.CustomerMetrics{
+					NPSResponses: []domain.NPSResponse{{Score: 11}},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing_promoters",
 			input: &domain.AppraisalInput{
@@ -707,6 +727,64 @@ func TestChurnReductionImpact(t *testing.T) {
 	}
 }
 
+func TestChurnReductionSignificance(t *testing.T) {
+	calc := New()
+	churn := func(before, nBefore, after, nAfter float64) *domain.AppraisalInput {
+		return &domain.AppraisalInput{Customers: &domain.CustomerMetrics{
+			ChurnBefore: ptr(before), CustomersBefore: ptr(nBefore),
+			ChurnAfter: ptr(after), CustomersAfter: ptr(nAfter),
+		}}
+	}
+
+	// 100 of 1000 churned before, 70 of 1000 after
+	result, err := calc.ChurnReductionImpact(churn(0.10, 1000, 0.07, 1000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sig := result.Significance
+	if sig == nil || sig.Test != "two_proportion_z" || sig.PValue == nil {
+		t.Fatalf("Significance = %+v", sig)
+	}
+	wantZ := 0.03 / math.Sqrt(0.085*0.915*0.002)
+	if !almostEqual(sig.Statistic, wantZ) || sig.Significant == nil || !*sig.Significant || *sig.PValue > 0.05 {
+		t.Errorf("z = %v (p %v), want %v", sig.Statistic, *sig.PValue, wantZ)
+	}
+	se := math.Sqrt(1.0/70 - 1.0/1000 + 1.0/100 - 1.0/1000)
+	if !almostEqual(sig.CILower, 1-0.7*math.Exp(1.959963984540054*se)) || !almostEqual(sig.CIUpper, 1-0.7*math.Exp(-1.959963984540054*se)) {
+		t.Errorf("CI = [%v, %v]", sig.CILower, sig.CIUpper)
+	}
+
+	// The same rates on 50 customers each are not evidence.
+	small, err := calc.ChurnReductionImpact(churn(0.10, 50, 0.06, 50))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if small.Significance.Significant == nil || *small.Significance.Significant || small.Significance.CILower >= 0 {
+		t.Errorf("small sample = %+v", small.Significance)
+	}
+
+	// No churn after: the interval stays finite.
+	none, err := calc.ChurnReductionImpact(churn(0.10, 200, 0, 200))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.IsInf(none.Significance.CIUpper, 0) || none.Significance.CIUpper > 1 || none.Significance.Significant == nil || !*none.Significance.Significant {
+		t.Errorf("no churn after = %+v", none.Significance)
+	}
+
+	// Without sample sizes there is no significance.
+	plain, _ := calc.ChurnReductionImpact(&domain.AppraisalInput{Customers: &domain.CustomerMetrics{ChurnBefore: ptr(0.1), ChurnAfter: ptr(0.07)}})
+	if plain.Significance != nil {
+		t.Errorf("Significance = %+v, want nil", plain.Significance)
+	}
+
+	half := churn(0.10, 1000, 0.07, 1000)
+	half.Customers.CustomersAfter = nil
+	if _, err := calc.ChurnReductionImpact(half); err == nil {
+		t.Error("expected error with only customers_before")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // RevenueGrowthRate
 // ---------------------------------------------------------------------------
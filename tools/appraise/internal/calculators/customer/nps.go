@@ -0,0 +1,144 @@
+package customer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/stats"
+)
+
+// npsZ is the two-sided 95% normal critical value.
+const npsZ = 1.959963984540054
+
+// NPSSurvey calculates NPS from raw 0-10 survey responses
+// (customers.nps_responses) with its sampling error. Each response scores
+// +1 (promoter), 0 (passive) or -1 (detractor), so with shares p and d:
+//
+//	NPS = 100 * (p - d)
+//	SE  = 100 * sqrt((p + d - (p - d)^2) / n)
+//
+// The margin of error and CI are at 95%. Responses are also broken down by
+// segment, tier and region where tagged, and when both the premium and base
+// tiers (nps_premium_tier, nps_base_tier) have responses their NPS is
+// compared with a two-sample z-test; significantly_above is the one-sided
+// test that premium NPS exceeds base.
+func (c *Calculator) NPSSurvey(input *domain.AppraisalInput) (*domain.NPSSurveyResult, error) {
+	if input.Customers == nil || len(input.Customers.NPSResponses) == 0 {
+		return nil, fmt.Errorf("customers.nps_responses required")
+	}
+	m := input.Customers
+
+	overall, err := npsStats(m.NPSResponses)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.NPSSurveyResult{
+		NPSStats:       overall,
+		Segments:       npsGroups(m.NPSResponses, func(r domain.NPSResponse) string { return r.Segment }),
+		Tiers:          npsGroups(m.NPSResponses, func(r domain.NPSResponse) string { return r.Tier }),
+		Regions:        npsGroups(m.NPSResponses, func(r domain.NPSResponse) string { return r.Region }),
+		Interpretation: npsInterpretation(overall.NPS),
+	}
+
+	premiumTier, baseTier := "premium", "base"
+	if m.NPSPremiumTier != nil {
+		premiumTier = *m.NPSPremiumTier
+	}
+	if m.NPSBaseTier != nil {
+		baseTier = *m.NPSBaseTier
+	}
+	var premium, base *domain.NPSStats
+	for i := range result.Tiers {
+		switch result.Tiers[i].Name {
+		case premiumTier:
+			premium = &result.Tiers[i].NPSStats
+		case baseTier:
+			base = &result.Tiers[i].NPSStats
+		}
+	}
+	if premium != nil && base != nil {
+		cmp := &domain.NPSComparison{
+			PremiumTier:   premiumTier,
+			BaseTier:      baseTier,
+			Difference:    premium.NPS - base.NPS,
+			StandardError: math.Hypot(premium.StandardError, base.StandardError),
+		}
+		switch {
+		case cmp.StandardError > 0:
+			cmp.Z = cmp.Difference / cmp.StandardError
+			cmp.PValue = 2 * (1 - stats.NormalCDF(math.Abs(cmp.Z)))
+			cmp.PValueAbove = 1 - stats.NormalCDF(cmp.Z)
+		// Both tiers answered unanimously but differently: no sampling
+		// error, z is undefined.
+		case cmp.Difference > 0:
+			cmp.PValue, cmp.PValueAbove = 0, 0
+		case cmp.Difference < 0:
+			cmp.PValueAbove = 1
+		default:
+			cmp.PValue, cmp.PValueAbove = 1, 1
+		}
+		cmp.SignificantlyDifferent = cmp.PValue < 0.05
+		cmp.SignificantlyAbove = cmp.PValueAbove < 0.05
+		result.TierComparison = cmp
+	}
+
+	return result, nil
+}
+
+// npsStats counts promoters, passives and detractors and derives NPS with
+// its standard error and 95% CI.
+func npsStats(responses []domain.NPSResponse) (domain.NPSStats, error) {
+	var s domain.NPSStats
+	for _, r := range responses {
+		switch {
+		case r.Score < 0 || r.Score > 10:
+			return s, fmt.Errorf("nps score %d out of range 0-10", r.Score)
+		case r.Score >= 9:
+			s.Promoters++
+		case r.Score >= 7:
+			s.Passives++
+		default:
+			s.Detractors++
+		}
+	}
+	s.Responses = len(responses)
+
+	n := float64(s.Responses)
+	p, d := float64(s.Promoters)/n, float64(s.Detractors)/n
+	s.PromotersPct = 100 * p
+	s.PassivesPct = 100 * float64(s.Passives) / n
+	s.DetractorsPct = 100 * d
+	s.NPS = 100 * (p - d)
+	s.StandardError = 100 * math.Sqrt(math.Max(0, p+d-(p-d)*(p-d))/n)
+	s.MarginOfError = npsZ * s.StandardError
+	s.CILower = math.Max(-100, s.NPS-s.MarginOfError)
+	s.CIUpper = math.Min(100, s.NPS+s.MarginOfError)
+	return s, nil
+}
+
+// npsGroups breaks responses down by the tag returned by key, in order of
+// first appearance. Untagged responses are left out; nil when none is
+// tagged.
+func npsGroups(responses []domain.NPSResponse, key func(domain.NPSResponse) string) []domain.NPSGroup {
+	var names []string
+	byName := map[string][]domain.NPSResponse{}
+	for _, r := range responses {
+		name := key(r)
+		if name == "" {
+			continue
+		}
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], r)
+	}
+
+	var groups []domain.NPSGroup
+	for _, name := range names {
+		// Scores were validated on the full set.
+		s, _ := npsStats(byName[name])
+		groups = append(groups, domain.NPSGroup{Name: name, NPSStats: s})
+	}
+	return groups
+}
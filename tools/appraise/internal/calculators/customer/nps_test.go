@@ -0,0 +1,231 @@
+package customer
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// responses returns count copies of score tagged with tier and segment.
+func responses(count, score int, tier, segment string) []domain.NPSResponse {
+	out := make([]domain.NPSResponse, count)
+	for i := range out {
+		out[i] = domain.NPSResponse{Score: score, Tier: tier, Segment: segment}
+	}
+	return out
+}
+
+// survey concatenates response groups.
+func survey(groups ...[]domain.NPSResponse) []domain.NPSResponse {
+	var out []domain.NPSResponse
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
+
+func strPtr(s string) *string { return &s }
+
+// ---------------------------------------------------------------------------
+// NPSSurvey
+// ---------------------------------------------------------------------------
+
+func TestNPSSurvey(t *testing.T) {
+	calc := New()
+
+	type group struct {
+		name      string
+		responses int
+		nps       float64
+	}
+
+	tests := []struct {
+		name           string
+		responses      []domain.NPSResponse
+		premiumTier    *string
+		baseTier       *string
+		wantErr        bool
+		errContains    string
+		wantCounts     [4]int // responses, promoters, passives, detractors
+		wantInterp     string
+		wantTiers      []group
+		wantSegments   []group
+		wantComparison bool
+		wantDiff       float64
+		wantPValue     float64 // negative skips the exact check
+		wantPAbove     float64 // negative skips the exact check
+		wantDifferent  bool
+		wantAbove      bool
+	}{
+		{
+			// premium 20/5/5 -> NPS 50; base 12/16/12 -> NPS 0
+			name: "premium_vs_base",
+			responses: survey(
+				responses(20, 10, "premium", "smb"),
+				responses(5, 8, "premium", "smb"),
+				responses(5, 3, "premium", "enterprise"),
+				responses(12, 9, "base", "enterprise"),
+				responses(16, 7, "base", ""),
+				responses(12, 0, "base", ""),
+			),
+			wantCounts:     [4]int{70, 32, 21, 17},
+			wantInterp:     "moderate_nps",
+			wantTiers:      []group{{"premium", 30, 50}, {"base", 40, 0}},
+			wantSegments:   []group{{"smb", 25, 80}, {"enterprise", 17, 100 * (12.0 - 5) / 17}},
+			wantComparison: true,
+			wantDiff:       50,
+			wantPValue:     -1,
+			wantPAbove:     -1,
+			wantDifferent:  true,
+			wantAbove:      true,
+		},
+		{
+			// gold 3/5 promoters vs standard 2/5
+			name:           "small_samples_not_significant",
+			responses:      survey(responses(3, 10, "gold", ""), responses(2, 5, "gold", ""), responses(2, 10, "standard", ""), responses(3, 5, "standard", "")),
+			premiumTier:    strPtr("gold"),
+			baseTier:       strPtr("standard"),
+			wantCounts:     [4]int{10, 5, 0, 5},
+			wantTiers:      []group{{"gold", 5, 20}, {"standard", 5, -20}},
+			wantComparison: true,
+			wantDiff:       40,
+			wantPValue:     -1,
+			wantPAbove:     -1,
+		},
+		{
+			name:           "unanimous_above",
+			responses:      survey(responses(10, 10, "gold", ""), responses(10, 0, "standard", "")),
+			premiumTier:    strPtr("gold"),
+			baseTier:       strPtr("standard"),
+			wantCounts:     [4]int{20, 10, 0, 10},
+			wantTiers:      []group{{"gold", 10, 100}, {"standard", 10, -100}},
+			wantComparison: true,
+			wantDiff:       200,
+			wantDifferent:  true,
+			wantAbove:      true,
+		},
+		{
+			name:           "unanimous_below",
+			responses:      survey(responses(10, 0, "gold", ""), responses(10, 10, "standard", "")),
+			premiumTier:    strPtr("gold"),
+			baseTier:       strPtr("standard"),
+			wantCounts:     [4]int{20, 10, 0, 10},
+			wantTiers:      []group{{"gold", 10, -100}, {"standard", 10, 100}},
+			wantComparison: true,
+			wantDiff:       -200,
+			wantPAbove:     1,
+			wantDifferent:  true,
+		},
+		{
+			name:           "unanimous_and_equal",
+			responses:      survey(responses(4, 9, "gold", ""), responses(6, 10, "standard", "")),
+			premiumTier:    strPtr("gold"),
+			baseTier:       strPtr("standard"),
+			wantCounts:     [4]int{10, 10, 0, 0},
+			wantInterp:     "excellent_nps",
+			wantTiers:      []group{{"gold", 4, 100}, {"standard", 6, 100}},
+			wantComparison: true,
+			wantPValue:     1,
+			wantPAbove:     1,
+		},
+		{
+			name:       "untagged",
+			responses:  survey(responses(3, 10, "", ""), responses(1, 0, "", "")),
+			wantCounts: [4]int{4, 3, 0, 1},
+		},
+		{
+			name:        "no_responses",
+			wantErr:     true,
+			errContains: "nps_responses required",
+		},
+		{
+			name:        "score_out_of_range",
+			responses:   []domain.NPSResponse{{Score: 9}, {Score: 11}},
+			wantErr:     true,
+			errContains: "out of range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.NPSSurvey(&domain.AppraisalInput{Customers: &domain.CustomerMetrics{
+				NPSResponses:   tt.responses,
+				NPSPremiumTier: tt.premiumTier,
+				NPSBaseTier:    tt.baseTier,
+			}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			n, promoters, detractors := tt.wantCounts[0], tt.wantCounts[1], tt.wantCounts[3]
+			if got := [4]int{result.Responses, result.Promoters, result.Passives, result.Detractors}; got != tt.wantCounts {
+				t.Errorf("counts = %v, want %v", got, tt.wantCounts)
+			}
+			p, d := float64(promoters)/float64(n), float64(detractors)/float64(n)
+			wantSE := 100 * math.Sqrt((p+d-(p-d)*(p-d))/float64(n))
+			if !almostEqual(result.NPS, 100*(p-d)) || !almostEqual(result.StandardError, wantSE) {
+				t.Errorf("NPS = %v ± %v, want %v ± %v", result.NPS, result.StandardError, 100*(p-d), wantSE)
+			}
+			// the CI is clamped to the NPS scale
+			if !almostEqual(result.MarginOfError, 1.959963984540054*wantSE) ||
+				!almostEqual(result.CILower, math.Max(-100, result.NPS-result.MarginOfError)) ||
+				!almostEqual(result.CIUpper, math.Min(100, result.NPS+result.MarginOfError)) {
+				t.Errorf("margin/CI = %v/[%v, %v]", result.MarginOfError, result.CILower, result.CIUpper)
+			}
+			if tt.wantInterp != "" && result.Interpretation != tt.wantInterp {
+				t.Errorf("Interpretation = %q, want %q", result.Interpretation, tt.wantInterp)
+			}
+			if result.Regions != nil {
+				t.Errorf("Regions = %+v, want nil when untagged", result.Regions)
+			}
+
+			checkGroups := func(field string, got []domain.NPSGroup, want []group) {
+				if len(got) != len(want) {
+					t.Fatalf("%s = %+v, want %+v", field, got, want)
+				}
+				for i, w := range want {
+					if got[i].Name != w.name || got[i].Responses != w.responses || !almostEqual(got[i].NPS, w.nps) {
+						t.Errorf("%s[%d] = %s/%d/%v, want %+v", field, i, got[i].Name, got[i].Responses, got[i].NPS, w)
+					}
+				}
+			}
+			checkGroups("Tiers", result.Tiers, tt.wantTiers)
+			checkGroups("Segments", result.Segments, tt.wantSegments)
+
+			cmp := result.TierComparison
+			if (cmp != nil) != tt.wantComparison {
+				t.Fatalf("TierComparison = %+v, want present %v", cmp, tt.wantComparison)
+			}
+			if cmp == nil {
+				return
+			}
+			if cmp.PremiumTier != tt.wantTiers[0].name || cmp.BaseTier != tt.wantTiers[1].name || !almostEqual(cmp.Difference, tt.wantDiff) {
+				t.Errorf("comparison = %s-%s %v, want difference %v", cmp.PremiumTier, cmp.BaseTier, cmp.Difference, tt.wantDiff)
+			}
+			if wantSE := math.Hypot(result.Tiers[0].StandardError, result.Tiers[1].StandardError); !almostEqual(cmp.StandardError, wantSE) {
+				t.Errorf("comparison StandardError = %v, want %v", cmp.StandardError, wantSE)
+			}
+			if cmp.StandardError > 0 && (!almostEqual(cmp.Z, cmp.Difference/cmp.StandardError) || (cmp.Z > 0 && !almostEqual(cmp.PValue, 2*cmp.PValueAbove))) {
+				t.Errorf("z/p-values = %v/%v/%v", cmp.Z, cmp.PValue, cmp.PValueAbove)
+			}
+			if (tt.wantPValue >= 0 && cmp.PValue != tt.wantPValue) || (tt.wantPAbove >= 0 && cmp.PValueAbove != tt.wantPAbove) {
+				t.Errorf("p-values = %v/%v, want %v/%v", cmp.PValue, cmp.PValueAbove, tt.wantPValue, tt.wantPAbove)
+			}
+			if cmp.SignificantlyDifferent != tt.wantDifferent || cmp.SignificantlyAbove != tt.wantAbove {
+				t.Errorf("significantly different/above = %v/%v, want %v/%v",
+					cmp.SignificantlyDifferent, cmp.SignificantlyAbove, tt.wantDifferent, tt.wantAbove)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package customer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// defaultReviewPriorWeight is how many reviews at the prior mean the
+// Bayesian rating adds to every product.
+const defaultReviewPriorWeight = 10
+
+// reviewPool accumulates 1-5 star ratings across sources.
+type reviewPool struct {
+	count, sum float64
+	stars      [5]float64 // counts per star, from sources with per-star data
+}
+
+// Reviews summarizes product.reviews across sources and compares them with
+// each competitor's reviews. The mean rating is weighted by review count;
+// the Bayesian rating shrinks it toward a prior mean, so a product with a
+// handful of reviews does not outrank one with thousands:
+//
+//	bayesian = (C * prior_mean + sum of ratings) / (C + review count)
+//
+// The prior mean defaults to the pooled mean of the product and all
+// competitors, C (review_prior_weight) to 10 reviews. Top-2-box (4-5 stars)
+// over the sources with per-star data is scored with CSAT.
+func (c *Calculator) Reviews(input *domain.AppraisalInput) (*domain.ReviewsResult, error) {
+	if input.Product == nil || len(input.Product.Reviews) == 0 {
+		return nil, fmt.Errorf("product.reviews required")
+	}
+
+	product, productSources, err := poolReviews(input.Product.Reviews)
+	if err != nil {
+		return nil, fmt.Errorf("product reviews: %w", err)
+	}
+	total := product
+	type competitorPool struct {
+		name    string
+		pool    reviewPool
+		sources []domain.ReviewSourceSummary
+	}
+	var competitors []competitorPool
+	for _, comp := range input.Competitors {
+		if len(comp.Reviews) == 0 {
+			continue
+		}
+		pool, sources, err := poolReviews(comp.Reviews)
+		if err != nil {
+			return nil, fmt.Errorf("competitor %q reviews: %w", comp.Name, err)
+		}
+		competitors = append(competitors, competitorPool{comp.Name, pool, sources})
+		total.count += pool.count
+		total.sum += pool.sum
+	}
+
+	priorMean := total.sum / total.count
+	priorWeight := float64(defaultReviewPriorWeight)
+	if m := input.Customers; m != nil {
+		if m.ReviewPriorMean != nil {
+			priorMean = *m.ReviewPriorMean
+		}
+		if m.ReviewPriorWeight != nil {
+			if *m.ReviewPriorWeight < 0 {
+				return nil, fmt.Errorf("review_prior_weight must be non-negative")
+			}
+			priorWeight = *m.ReviewPriorWeight
+		}
+	}
+
+	result := &domain.ReviewsResult{
+		ReviewSummary: product.summary(input.Product.Name, productSources, priorMean, priorWeight),
+		PriorMean:     priorMean,
+		PriorWeight:   priorWeight,
+	}
+	if dist := product.distributionCount(); dist > 0 {
+		satisfied := product.stars[3] + product.stars[4]
+		csat, err := c.CSAT(&domain.AppraisalInput{Customers: &domain.CustomerMetrics{
+			SatisfiedResponses: &satisfied,
+			TotalResponses:     &dist,
+		}})
+		if err != nil {
+			return nil, err
+		}
+		result.CSAT = &csat.Value
+		result.CSATInterpretation = csat.Interpretation
+	}
+
+	for _, cp := range competitors {
+		cmp := domain.ReviewComparison{
+			ReviewSummary: cp.pool.summary(cp.name, cp.sources, priorMean, priorWeight),
+		}
+		cmp.MeanDelta = result.MeanRating - cmp.MeanRating
+		cmp.BayesianDelta = result.BayesianRating - cmp.BayesianRating
+		if result.TopTwoBox != nil && cmp.TopTwoBox != nil {
+			delta := *result.TopTwoBox - *cmp.TopTwoBox
+			cmp.TopTwoBoxDelta = &delta
+		}
+		if result.Distribution != nil && cmp.Distribution != nil {
+			distance := 0.0
+			for i := range result.Distribution {
+				distance += math.Abs(result.Distribution[i] - cmp.Distribution[i])
+			}
+			distance /= 2
+			cmp.DistributionDistance = &distance
+		}
+		result.Competitors = append(result.Competitors, cmp)
+	}
+
+	return result, nil
+}
+
+// poolReviews validates and pools review sources.
+func poolReviews(sources []domain.ReviewSource) (reviewPool, []domain.ReviewSourceSummary, error) {
+	var pool reviewPool
+	var summaries []domain.ReviewSourceSummary
+	for _, src := range sources {
+		var sp reviewPool
+		hasStars := len(src.Histogram) > 0
+		if len(src.Histogram) > 0 {
+			if len(src.Histogram) != 5 {
+				return pool, nil, fmt.Errorf("source %q: histogram needs 5 counts (1-5 stars)", src.Source)
+			}
+			for i, n := range src.Histogram {
+				if n < 0 {
+					return pool, nil, fmt.Errorf("source %q: histogram counts must be non-negative", src.Source)
+				}
+				sp.stars[i] += n
+				sp.count += n
+				sp.sum += float64(i+1) * n
+			}
+		}
+		for _, r := range src.Reviews {
+			if r.Rating == 0 {
+				continue // text-only review
+			}
+			hasStars = true
+			if r.Rating < 1 || r.Rating > 5 {
+				return pool, nil, fmt.Errorf("source %q: rating %v out of range 1-5", src.Source, r.Rating)
+			}
+			sp.stars[int(math.Round(r.Rating))-1]++
+			sp.count++
+			sp.sum += r.Rating
+		}
+		if !hasStars {
+			if src.AverageRating == nil || src.ReviewCount == nil {
+				return pool, nil, fmt.Errorf("source %q: histogram, reviews, or average_rating and review_count required", src.Source)
+			}
+			if *src.AverageRating < 1 || *src.AverageRating > 5 || *src.ReviewCount < 0 {
+				return pool, nil, fmt.Errorf("source %q: average_rating must be 1-5 and review_count non-negative", src.Source)
+			}
+			sp.count = *src.ReviewCount
+			sp.sum = *src.AverageRating * *src.ReviewCount
+		}
+		if sp.count == 0 {
+			return pool, nil, fmt.Errorf("source %q: no reviews", src.Source)
+		}
+
+		summary := domain.ReviewSourceSummary{
+			Source:      src.Source,
+			ReviewCount: sp.count,
+			MeanRating:  sp.sum / sp.count,
+		}
+		if hasStars {
+			summary.Distribution = sp.distribution()
+		}
+		summaries = append(summaries, summary)
+
+		pool.count += sp.count
+		pool.sum += sp.sum
+		for i, n := range sp.stars {
+			pool.stars[i] += n
+		}
+	}
+	return pool, summaries, nil
+}
+
+func (p reviewPool) summary(name string, sources []domain.ReviewSourceSummary, priorMean, priorWeight float64) domain.ReviewSummary {
+	s := domain.ReviewSummary{
+		Name:           name,
+		Sources:        sources,
+		ReviewCount:    p.count,
+		MeanRating:     p.sum / p.count,
+		BayesianRating: (priorWeight*priorMean + p.sum) / (priorWeight + p.count),
+		Distribution:   p.distribution(),
+	}
+	if n := p.distributionCount(); n > 0 {
+		top := (p.stars[3] + p.stars[4]) / n
+		bottom := (p.stars[0] + p.stars[1]) / n
+		s.TopTwoBox = &top
+		s.BottomTwoBox = &bottom
+	}
+	return s
+}
+
+func (p reviewPool) distributionCount() float64 {
+	n := 0.0
+	for _, v := range p.stars {
+		n += v
+	}
+	return n
+}
+
+// distribution returns star shares, or nil without per-star data.
+func (p reviewPool) distribution() []float64 {
+	n := p.distributionCount()
+	if n == 0 {
+		return nil
+	}
+	shares := make([]float64, 5)
+	for i, v := range p.stars {
+		shares[i] = v / n
+	}
+	return shares
+}
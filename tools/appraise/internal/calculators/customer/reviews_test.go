@@ -0,0 +1,229 @@
+package customer
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// Reviews
+// ---------------------------------------------------------------------------
+
+func TestReviews(t *testing.T) {
+	calc := New()
+
+	// 200 + 3 + 100 reviews summing 830 + 11 + 400; per-star data from the
+	// first two sources: 10/11/20/61/101 of 203
+	ours := func() []domain.ReviewSource {
+		return []domain.ReviewSource{
+			{Source: "marketplace", Histogram: []float64{10, 10, 20, 60, 100}},
+			{Source: "app_store", Reviews: []domain.Review{{Rating: 5}, {Rating: 4}, {Rating: 2, Text: "battery dies"}, {Text: "text only, unrated"}}},
+			{Source: "retailer", AverageRating: ptr(4.0), ReviewCount: ptr(100)},
+		}
+	}
+	// 50 reviews summing 200
+	rivals := func() []domain.CompetitorData {
+		return []domain.CompetitorData{
+			{Name: "rival", Reviews: []domain.ReviewSource{{Source: "marketplace", Histogram: []float64{5, 5, 5, 5, 30}}}},
+			{Name: "unreviewed"},
+		}
+	}
+	priorMean := (1241.0 + 200) / 353
+	bayesian := (10*priorMean + 1241) / 313
+	distance := 0.0
+	for i, theirs := range []float64{0.1, 0.1, 0.1, 0.1, 0.6} {
+		distance += math.Abs([]float64{10, 11, 20, 61, 101}[i]/203-theirs) / 2
+	}
+
+	type rival struct {
+		name          string
+		mean          float64
+		bayesianDelta float64
+		topTwoDelta   *float64
+		distance      *float64
+	}
+
+	tests := []struct {
+		name            string
+		reviews         []domain.ReviewSource
+		competitors     []domain.CompetitorData
+		customers       *domain.CustomerMetrics
+		wantErr         bool
+		errContains     string
+		wantCount       float64
+		wantMean        float64
+		wantPriorMean   float64
+		wantPriorWeight float64
+		wantBayesian    float64
+		wantSourceMeans []float64
+		wantPerStar     []bool // sources with a star distribution
+		wantTopTwo      *float64
+		wantBottomTwo   *float64
+		wantCSATInterp  string
+		wantRivals      []rival
+	}{
+		{
+			name:            "mixed_sources_against_rival",
+			reviews:         ours(),
+			competitors:     rivals(),
+			wantCount:       303,
+			wantMean:        1241.0 / 303,
+			wantPriorMean:   priorMean,
+			wantPriorWeight: 10,
+			wantBayesian:    bayesian,
+			wantSourceMeans: []float64{4.15, 11.0 / 3, 4},
+			wantPerStar:     []bool{true, true, false},
+			wantTopTwo:      ptr(162.0 / 203),
+			wantBottomTwo:   ptr(21.0 / 203),
+			wantCSATInterp:  "acceptable_satisfaction",
+			wantRivals: []rival{{
+				name:          "rival",
+				mean:          4,
+				bayesianDelta: bayesian - (10*priorMean+200)/60,
+				topTwoDelta:   ptr(162.0/203 - 0.7),
+				distance:      ptr(distance),
+			}},
+		},
+		{
+			// two 5-star reviews shrink to (8*3 + 10) / 10
+			name:            "prior_override",
+			reviews:         []domain.ReviewSource{{Source: "site", Reviews: []domain.Review{{Rating: 5}, {Rating: 5}}}},
+			customers:       &domain.CustomerMetrics{ReviewPriorMean: ptr(3), ReviewPriorWeight: ptr(8)},
+			wantCount:       2,
+			wantMean:        5,
+			wantPriorMean:   3,
+			wantPriorWeight: 8,
+			wantBayesian:    3.4,
+			wantSourceMeans: []float64{5},
+			wantPerStar:     []bool{true},
+			wantTopTwo:      ptr(1),
+			wantBottomTwo:   ptr(0),
+			wantCSATInterp:  "premium_level_satisfaction",
+		},
+		{
+			name:        "no_reviews",
+			wantErr:     true,
+			errContains: "product.reviews required",
+		},
+		{
+			name:        "short_histogram",
+			reviews:     []domain.ReviewSource{{Source: "marketplace", Histogram: []float64{1, 2}}},
+			wantErr:     true,
+			errContains: "5 counts",
+		},
+		{
+			name:        "negative_histogram_count",
+			reviews:     []domain.ReviewSource{{Source: "marketplace", Histogram: []float64{1, 2, -3, 4, 5}}},
+			wantErr:     true,
+			errContains: "non-negative",
+		},
+		{
+			name:        "bad_rating",
+			reviews:     []domain.ReviewSource{{Source: "app_store", Reviews: []domain.Review{{Rating: 6}}}},
+			wantErr:     true,
+			errContains: "out of range",
+		},
+		{
+			name:        "empty_source",
+			reviews:     []domain.ReviewSource{{Source: "retailer", AverageRating: ptr(4.0)}},
+			wantErr:     true,
+			errContains: `source "retailer"`,
+		},
+		{
+			name:        "bad_average_rating",
+			reviews:     []domain.ReviewSource{{Source: "retailer", AverageRating: ptr(6.0), ReviewCount: ptr(10)}},
+			wantErr:     true,
+			errContains: "average_rating must be 1-5",
+		},
+		{
+			name:    "bad_competitor",
+			reviews: ours(),
+			competitors: []domain.CompetitorData{
+				{Name: "rival", Reviews: []domain.ReviewSource{{Source: "marketplace", Histogram: []float64{0, 0, 0, 0, 0}}}},
+			},
+			wantErr:     true,
+			errContains: `competitor "rival" reviews: source "marketplace": no reviews`,
+		},
+		{
+			name:        "bad_prior_weight",
+			reviews:     ours(),
+			customers:   &domain.CustomerMetrics{ReviewPriorWeight: ptr(-1)},
+			wantErr:     true,
+			errContains: "review_prior_weight",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Reviews(&domain.AppraisalInput{
+				Product:     &domain.ProductDefinition{Name: "ours", Reviews: tt.reviews},
+				Competitors: tt.competitors,
+				Customers:   tt.customers,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !almostEqual(result.ReviewCount, tt.wantCount) || !almostEqual(result.MeanRating, tt.wantMean) {
+				t.Errorf("count/mean = %v/%v, want %v/%v", result.ReviewCount, result.MeanRating, tt.wantCount, tt.wantMean)
+			}
+			if !almostEqual(result.PriorMean, tt.wantPriorMean) || result.PriorWeight != tt.wantPriorWeight {
+				t.Errorf("prior = %v x %v, want %v x %v", result.PriorMean, result.PriorWeight, tt.wantPriorMean, tt.wantPriorWeight)
+			}
+			if !almostEqual(result.BayesianRating, tt.wantBayesian) {
+				t.Errorf("BayesianRating = %v, want %v", result.BayesianRating, tt.wantBayesian)
+			}
+
+			if len(result.Sources) != len(tt.wantSourceMeans) {
+				t.Fatalf("got %d sources, want %d", len(result.Sources), len(tt.wantSourceMeans))
+			}
+			for i, src := range result.Sources {
+				if !almostEqual(src.MeanRating, tt.wantSourceMeans[i]) || (src.Distribution != nil) != tt.wantPerStar[i] {
+					t.Errorf("source %s = %v (distribution %v), want %v (distribution %v)",
+						src.Source, src.MeanRating, src.Distribution, tt.wantSourceMeans[i], tt.wantPerStar[i])
+				}
+			}
+
+			if (result.TopTwoBox == nil) != (tt.wantTopTwo == nil) || result.TopTwoBox != nil && !almostEqual(*result.TopTwoBox, *tt.wantTopTwo) {
+				t.Errorf("TopTwoBox = %v, want %v", result.TopTwoBox, tt.wantTopTwo)
+			}
+			if (result.BottomTwoBox == nil) != (tt.wantBottomTwo == nil) || result.BottomTwoBox != nil && !almostEqual(*result.BottomTwoBox, *tt.wantBottomTwo) {
+				t.Errorf("BottomTwoBox = %v, want %v", result.BottomTwoBox, tt.wantBottomTwo)
+			}
+			if (result.CSAT == nil) != (tt.wantTopTwo == nil) || result.CSAT != nil && !almostEqual(*result.CSAT, *tt.wantTopTwo) {
+				t.Errorf("CSAT = %v, want %v", result.CSAT, tt.wantTopTwo)
+			}
+			if result.CSATInterpretation != tt.wantCSATInterp {
+				t.Errorf("CSATInterpretation = %q, want %q", result.CSATInterpretation, tt.wantCSATInterp)
+			}
+
+			if len(result.Competitors) != len(tt.wantRivals) {
+				t.Fatalf("got %d competitors, want %d (unreviewed skipped)", len(result.Competitors), len(tt.wantRivals))
+			}
+			for i, w := range tt.wantRivals {
+				got := result.Competitors[i]
+				if got.Name != w.name || !almostEqual(got.MeanRating, w.mean) || !almostEqual(got.MeanDelta, tt.wantMean-w.mean) ||
+					!almostEqual(got.BayesianDelta, w.bayesianDelta) {
+					t.Errorf("competitor %d = %+v, want %+v", i, got, w)
+				}
+				if (got.TopTwoBoxDelta == nil) != (w.topTwoDelta == nil) || got.TopTwoBoxDelta != nil && !almostEqual(*got.TopTwoBoxDelta, *w.topTwoDelta) {
+					t.Errorf("%s TopTwoBoxDelta = %v, want %v", got.Name, got.TopTwoBoxDelta, w.topTwoDelta)
+				}
+				if (got.DistributionDistance == nil) != (w.distance == nil) || got.DistributionDistance != nil && !almostEqual(*got.DistributionDistance, *w.distance) {
+					t.Errorf("%s DistributionDistance = %v, want %v", got.Name, got.DistributionDistance, w.distance)
+				}
+			}
+		})
+	}
+}
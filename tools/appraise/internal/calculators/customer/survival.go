@@ -0,0 +1,216 @@
+package customer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/dates"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// daysPerMonth is the average calendar month, used to express lifetimes in
+// months.
+const daysPerMonth = 365.25 / 12
+
+// lifetime is one customer's observed time in months.
+type lifetime struct {
+	months  float64
+	churned bool
+}
+
+// Survival estimates retention from customer records (customers.customer_records)
+// with the Kaplan-Meier estimator, so customers who are still active count
+// for the time they were observed instead of being dropped:
+//
+//	S(t) = product over churn times t_i <= t of (1 - churned_i / at_risk_i)
+//
+// Active customers are censored at survival_as_of, which defaults to the
+// latest start or end date in the records. The curve gives retention and the
+// monthly hazard at the end of each month since start; the median lifetime
+// is where retention first falls to 50%. average_lifespan_months (observed
+// customer-months / churned customers) is what financial.clv uses when
+// financials.average_lifespan_months is not given.
+//
+// The same estimate is broken down by start-month cohort, tier and bundle vs
+// standalone where records are tagged.
+func (c *Calculator) Survival(input *domain.AppraisalInput) (*domain.SurvivalResult, error) {
+	if input.Customers == nil || len(input.Customers.CustomerRecords) == 0 {
+		return nil, fmt.Errorf("customers.customer_records required")
+	}
+	m := input.Customers
+
+	starts := make([]time.Time, len(m.CustomerRecords))
+	ends := make([]*time.Time, len(m.CustomerRecords))
+	var asOf time.Time
+	for i, r := range m.CustomerRecords {
+		start, err := dates.Parse(r.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("customer record %s: start_date: %w", recordName(r, i), err)
+		}
+		starts[i] = start
+		if start.After(asOf) {
+			asOf = start
+		}
+		if r.EndDate != nil {
+			end, err := dates.Parse(*r.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("customer record %s: end_date: %w", recordName(r, i), err)
+			}
+			if end.Before(start) {
+				return nil, fmt.Errorf("customer record %s: end_date before start_date", recordName(r, i))
+			}
+			ends[i] = &end
+			if end.After(asOf) {
+				asOf = end
+			}
+		}
+	}
+	if m.SurvivalAsOf != nil {
+		t, err := dates.Parse(*m.SurvivalAsOf)
+		if err != nil {
+			return nil, fmt.Errorf("survival_as_of: %w", err)
+		}
+		asOf = t
+	}
+
+	lifetimes := make([]lifetime, len(m.CustomerRecords))
+	for i, r := range m.CustomerRecords {
+		if starts[i].After(asOf) {
+			return nil, fmt.Errorf("customer record %s: starts after survival_as_of", recordName(r, i))
+		}
+		// A churn date after the as-of date was not yet observed.
+		end, churned := asOf, false
+		if ends[i] != nil && !ends[i].After(asOf) {
+			end, churned = *ends[i], true
+		}
+		lifetimes[i] = lifetime{months: end.Sub(starts[i]).Hours() / 24 / daysPerMonth, churned: churned}
+	}
+
+	result := &domain.SurvivalResult{
+		SurvivalCurve: kaplanMeier("", lifetimes),
+		AsOf:          asOf.Format("2006-01-02"),
+	}
+
+	cohorts := survivalGroups(m.CustomerRecords, lifetimes, func(i int, _ domain.CustomerRecord) string {
+		return starts[i].Format("2006-01")
+	})
+	sort.SliceStable(cohorts, func(i, j int) bool { return cohorts[i].Name < cohorts[j].Name })
+	result.Cohorts = cohorts
+	result.Tiers = survivalGroups(m.CustomerRecords, lifetimes, func(_ int, r domain.CustomerRecord) string { return r.Tier })
+	bundling := survivalGroups(m.CustomerRecords, lifetimes, func(_ int, r domain.CustomerRecord) string {
+		switch {
+		case r.Bundled == nil:
+			return ""
+		case *r.Bundled:
+			return "bundle"
+		default:
+			return "standalone"
+		}
+	})
+	sort.SliceStable(bundling, func(i, j int) bool { return bundling[i].Name < bundling[j].Name })
+	result.Bundling = bundling
+
+	return result, nil
+}
+
+// survivalGroups estimates a curve per tag returned by key, in order of first
+// appearance. Untagged records are left out; nil when none is tagged.
+func survivalGroups(records []domain.CustomerRecord, lifetimes []lifetime, key func(int, domain.CustomerRecord) string) []domain.SurvivalCurve {
+	var names []string
+	byName := map[string][]lifetime{}
+	for i, r := range records {
+		name := key(i, r)
+		if name == "" {
+			continue
+		}
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], lifetimes[i])
+	}
+
+	var curves []domain.SurvivalCurve
+	for _, name := range names {
+		curves = append(curves, kaplanMeier(name, byName[name]))
+	}
+	return curves
+}
+
+// kaplanMeier estimates the survival curve of one group, reading it off at
+// each whole month up to the longest observed lifetime.
+func kaplanMeier(name string, lifetimes []lifetime) domain.SurvivalCurve {
+	sorted := append([]lifetime(nil), lifetimes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].months < sorted[j].months })
+
+	curve := domain.SurvivalCurve{Name: name, Customers: len(sorted)}
+	for _, l := range sorted {
+		curve.ExposureMonths += l.months
+		if l.churned {
+			curve.Churned++
+		} else {
+			curve.Censored++
+		}
+	}
+	if curve.ExposureMonths > 0 {
+		curve.MonthlyChurn = float64(curve.Churned) / curve.ExposureMonths
+	}
+	if curve.Churned > 0 && curve.ExposureMonths > 0 {
+		lifespan := curve.ExposureMonths / float64(curve.Churned)
+		curve.AverageLifespanMonths = &lifespan
+	}
+
+	// Step through churn times; at each, everyone with a lifetime at least
+	// that long is at risk.
+	type step struct{ at, survival float64 }
+	var steps []step
+	survival := 1.0
+	for i := 0; i < len(sorted); {
+		t, churned, j := sorted[i].months, 0, i
+		for ; j < len(sorted) && sorted[j].months == t; j++ {
+			if sorted[j].churned {
+				churned++
+			}
+		}
+		if churned > 0 {
+			survival *= 1 - float64(churned)/float64(len(sorted)-i)
+			steps = append(steps, step{t, survival})
+			if survival <= 0.5 && curve.MedianMonths == nil {
+				median := t
+				curve.MedianMonths = &median
+			}
+		}
+		i = j
+	}
+
+	maxMonth := 0
+	if len(sorted) > 0 {
+		maxMonth = int(math.Floor(sorted[len(sorted)-1].months))
+	}
+	prev, k := 1.0, 0
+	for month := 1; month <= maxMonth; month++ {
+		for k < len(steps) && steps[k].at <= float64(month) {
+			k++
+		}
+		retention := 1.0
+		if k > 0 {
+			retention = steps[k-1].survival
+		}
+		atRisk := len(sorted) - sort.Search(len(sorted), func(i int) bool { return sorted[i].months > float64(month-1) })
+		point := domain.SurvivalPoint{Month: month, Retention: retention, AtRisk: atRisk}
+		if prev > 0 {
+			point.Hazard = 1 - retention/prev
+		}
+		curve.Curve = append(curve.Curve, point)
+		prev = retention
+	}
+	return curve
+}
+
+func recordName(r domain.CustomerRecord, i int) string {
+	if r.ID != "" {
+		return fmt.Sprintf("%q", r.ID)
+	}
+	return fmt.Sprintf("%d", i)
+}
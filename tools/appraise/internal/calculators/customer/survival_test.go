@@ -0,0 +1,210 @@
+package customer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// Survival
+// ---------------------------------------------------------------------------
+
+func TestSurvival(t *testing.T) {
+	calc := New()
+
+	records := func() []domain.CustomerRecord {
+		bundled, standalone := true, false
+		return []domain.CustomerRecord{
+			{ID: "a", StartDate: "2023-01-01", EndDate: strPtr("2023-03-02"), Tier: "premium", Bundled: &bundled}, // churned day 60
+			{ID: "b", StartDate: "2023-01-01", EndDate: strPtr("2023-04-11"), Tier: "base", Bundled: &standalone}, // churned day 100
+			{ID: "c", StartDate: "2023-01-01", Tier: "premium", Bundled: &bundled},                                // active
+			{ID: "d", StartDate: "2023-02-01", EndDate: strPtr("2023-03-03"), Tier: "base", Bundled: &standalone}, // churned day 30
+			{ID: "e", StartDate: "2023-02-01", EndDate: strPtr("2024-06-01")},                                     // churned day 486
+		}
+	}
+	withEnd := func(i int, end string) []domain.CustomerRecord {
+		rs := records()
+		rs[i].EndDate = &end
+		return rs
+	}
+
+	type curve struct {
+		name                         string
+		customers, churned, censored int
+		medianDays                   *float64
+	}
+	// Tier and bundling curves leave out e, which has neither.
+	tiers := []curve{{"premium", 2, 1, 1, ptr(60)}, {"base", 2, 2, 0, ptr(30)}}
+	bundling := []curve{{"bundle", 2, 1, 1, ptr(60)}, {"standalone", 2, 2, 0, ptr(30)}}
+
+	tests := []struct {
+		name          string
+		records       []domain.CustomerRecord
+		asOf          *string
+		wantErr       bool
+		errContains   string
+		wantAsOf      string
+		wantOverall   curve
+		wantExposure  float64 // days
+		wantCurveLen  int
+		wantCurve     []domain.SurvivalPoint // leading points
+		wantLastPoint domain.SurvivalPoint
+		wantCohorts   []curve
+		wantTiers     []curve
+		wantBundling  []curve
+	}{
+		{
+			// c is observed 365 days and e 334 days to the as-of date.
+			// S = 0.8 at day 30, 0.6 at day 60, 0.4 at day 100.
+			name:         "as_of_before_last_churn",
+			records:      records(),
+			asOf:         strPtr("2024-01-01"),
+			wantAsOf:     "2024-01-01",
+			wantOverall:  curve{"", 5, 3, 2, ptr(100)},
+			wantExposure: 60 + 100 + 365 + 30 + 334,
+			wantCurveLen: 11,
+			wantCurve: []domain.SurvivalPoint{
+				{Month: 1, Retention: 0.8, Hazard: 0.2, AtRisk: 5},
+				{Month: 2, Retention: 0.6, Hazard: 0.25, AtRisk: 4},
+				{Month: 3, Retention: 0.6, Hazard: 0, AtRisk: 3},
+				{Month: 4, Retention: 0.4, Hazard: 1.0 / 3, AtRisk: 3},
+			},
+			wantLastPoint: domain.SurvivalPoint{Month: 11, Retention: 0.4, AtRisk: 2},
+			wantCohorts:   []curve{{"2023-01", 3, 2, 1, ptr(100)}, {"2023-02", 2, 1, 1, ptr(30)}},
+			wantTiers:     tiers,
+			wantBundling:  bundling,
+		},
+		{
+			// The latest date is e's churn, so e now counts as churned and c
+			// is observed 517 days.
+			name:         "default_as_of",
+			records:      records(),
+			wantAsOf:     "2024-06-01",
+			wantOverall:  curve{"", 5, 4, 1, ptr(100)},
+			wantExposure: 60 + 100 + 517 + 30 + 486,
+			wantCurveLen: 16,
+			wantCurve: []domain.SurvivalPoint{
+				{Month: 1, Retention: 0.8, Hazard: 0.2, AtRisk: 5},
+				{Month: 2, Retention: 0.6, Hazard: 0.25, AtRisk: 4},
+			},
+			wantLastPoint: domain.SurvivalPoint{Month: 16, Retention: 0.2, Hazard: 0.5, AtRisk: 2},
+			wantCohorts:   []curve{{"2023-01", 3, 2, 1, ptr(100)}, {"2023-02", 2, 2, 0, ptr(30)}},
+			wantTiers:     tiers,
+			wantBundling:  bundling,
+		},
+		{
+			name:        "no_records",
+			wantErr:     true,
+			errContains: "customer_records required",
+		},
+		{
+			name: "bad_start",
+			records: func() []domain.CustomerRecord {
+				rs := records()
+				rs[0].StartDate = "01/02/2023"
+				return rs
+			}(),
+			wantErr:     true,
+			errContains: `customer record "a": start_date: invalid date`,
+		},
+		{
+			name:        "bad_end",
+			records:     withEnd(1, "later"),
+			wantErr:     true,
+			errContains: `customer record "b": end_date: invalid date`,
+		},
+		{
+			name:        "end_before_start",
+			records:     withEnd(1, "2022-12-31"),
+			wantErr:     true,
+			errContains: "end_date before start_date",
+		},
+		{
+			name:        "starts_after_as_of",
+			records:     records(),
+			asOf:        strPtr("2023-01-15"),
+			wantErr:     true,
+			errContains: `customer record "d": starts after survival_as_of`,
+		},
+		{
+			name:        "bad_as_of",
+			records:     records(),
+			asOf:        strPtr("soon"),
+			wantErr:     true,
+			errContains: "survival_as_of: invalid date",
+		},
+	}
+
+	checkCurve := func(t *testing.T, got domain.SurvivalCurve, w curve) {
+		t.Helper()
+		if got.Name != w.name || got.Customers != w.customers || got.Churned != w.churned || got.Censored != w.censored {
+			t.Errorf("curve %q = %d/%d/%d, want %+v", got.Name, got.Customers, got.Churned, got.Censored, w)
+		}
+		var wantMedian *float64
+		if w.medianDays != nil {
+			wantMedian = ptr(*w.medianDays / daysPerMonth)
+		}
+		if (got.MedianMonths == nil) != (wantMedian == nil) || got.MedianMonths != nil && !almostEqual(*got.MedianMonths, *wantMedian) {
+			t.Errorf("curve %q MedianMonths = %v, want %v", got.Name, got.MedianMonths, wantMedian)
+		}
+	}
+	checkGroups := func(t *testing.T, field string, got []domain.SurvivalCurve, want []curve) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s = %+v, want %+v", field, got, want)
+		}
+		for i, w := range want {
+			checkCurve(t, got[i], w)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Survival(&domain.AppraisalInput{Customers: &domain.CustomerMetrics{
+				CustomerRecords: tt.records,
+				SurvivalAsOf:    tt.asOf,
+			}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.AsOf != tt.wantAsOf {
+				t.Errorf("AsOf = %s, want %s", result.AsOf, tt.wantAsOf)
+			}
+			checkCurve(t, result.SurvivalCurve, tt.wantOverall)
+
+			exposure := tt.wantExposure / daysPerMonth
+			churned := float64(tt.wantOverall.churned)
+			if !almostEqual(result.ExposureMonths, exposure) || !almostEqual(result.MonthlyChurn, churned/exposure) {
+				t.Errorf("exposure/churn = %v/%v, want %v/%v", result.ExposureMonths, result.MonthlyChurn, exposure, churned/exposure)
+			}
+			if result.AverageLifespanMonths == nil || !almostEqual(*result.AverageLifespanMonths, exposure/churned) {
+				t.Errorf("AverageLifespanMonths = %v, want %v", result.AverageLifespanMonths, exposure/churned)
+			}
+
+			if len(result.Curve) != tt.wantCurveLen {
+				t.Fatalf("got %d curve points, want %d", len(result.Curve), tt.wantCurveLen)
+			}
+			for i, w := range append(tt.wantCurve, tt.wantLastPoint) {
+				got := result.Curve[w.Month-1]
+				if got.Month != w.Month || got.AtRisk != w.AtRisk || !almostEqual(got.Retention, w.Retention) || !almostEqual(got.Hazard, w.Hazard) {
+					t.Errorf("curve point %d = %+v, want %+v", i, got, w)
+				}
+			}
+
+			checkGroups(t, "Cohorts", result.Cohorts, tt.wantCohorts)
+			checkGroups(t, "Tiers", result.Tiers, tt.wantTiers)
+			checkGroups(t, "Bundling", result.Bundling, tt.wantBundling)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package customer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// maxTopTopics caps the top complaints and praise lists.
+const maxTopTopics = 5
+
+// Built-in English sentiment lexicon, used when customers.sentiment_lexicon
+// does not replace a list.
+var (
+	defaultPositive = []string{
+		"good", "great", "excellent", "love", "loved", "loves", "amazing", "awesome", "perfect", "fast",
+		"easy", "reliable", "comfortable", "best", "happy", "recommend", "nice", "solid", "worth", "smooth",
+	}
+	defaultNegative = []string{
+		"bad", "poor", "terrible", "awful", "broke", "broken", "slow", "hate", "worst", "disappointed",
+		"disappointing", "expensive", "overpriced", "problem", "problems", "issue", "issues", "fail", "fails",
+		"failed", "dies", "died", "flimsy", "noisy", "useless", "defective", "annoying", "weak",
+	}
+	defaultNegators = []string{"not", "no", "never", "hardly", "without"}
+)
+
+// keyword is a dictionary entry split into words; prefix makes the last
+// word match by prefix.
+type keyword struct {
+	words  []string
+	prefix bool
+}
+
+// lexicon scores words +1 or -1.
+type lexicon struct {
+	score    map[string]int
+	negators map[string]bool
+}
+
+// Topics counts how many reviews mention each topic of the
+// customers.topics dictionary, for the product and each competitor with
+// reviews, and scores each mention with a word lexicon. A mention's polarity
+// is the lexicon score of the sentences that mention the topic (a negator
+// flips the next two words); when that is zero, the review's star rating
+// decides (4-5 positive, 1-2 negative), else the mention is neutral.
+//
+// The analysis is deterministic and offline: no stemming beyond keyword
+// prefixes ("batter*"), no models.
+func (c *Calculator) Topics(input *domain.AppraisalInput) (*domain.TopicsResult, error) {
+	if input.Customers == nil || len(input.Customers.Topics) == 0 {
+		return nil, fmt.Errorf("customers.topics required")
+	}
+	if input.Product == nil || len(input.Product.Reviews) == 0 {
+		return nil, fmt.Errorf("product.reviews required")
+	}
+
+	dictionary := make([][]keyword, len(input.Customers.Topics))
+	seen := map[string]bool{}
+	for i, topic := range input.Customers.Topics {
+		if seen[topic.Name] {
+			return nil, fmt.Errorf("duplicate topic %q", topic.Name)
+		}
+		seen[topic.Name] = true
+		for _, kw := range topic.Keywords {
+			prefix := strings.HasSuffix(kw, "*")
+			words := tokenize(strings.TrimSuffix(kw, "*"))
+			if len(words) == 0 {
+				return nil, fmt.Errorf("topic %q: empty keyword", topic.Name)
+			}
+			dictionary[i] = append(dictionary[i], keyword{words: words, prefix: prefix})
+		}
+		if len(dictionary[i]) == 0 {
+			return nil, fmt.Errorf("topic %q: keywords required", topic.Name)
+		}
+	}
+	lex := newLexicon(input.Customers.SentimentLexicon)
+
+	result := &domain.TopicsResult{
+		ProductTopics: productTopics(input.Product.Name, input.Product.Reviews, input.Customers.Topics, dictionary, lex),
+	}
+	for _, comp := range input.Competitors {
+		if len(comp.Reviews) > 0 {
+			result.Competitors = append(result.Competitors, productTopics(comp.Name, comp.Reviews, input.Customers.Topics, dictionary, lex))
+		}
+	}
+	return result, nil
+}
+
+func productTopics(name string, sources []domain.ReviewSource, topics []domain.TopicDefinition, dictionary [][]keyword, lex lexicon) domain.ProductTopics {
+	pt := domain.ProductTopics{Name: name, Topics: make([]domain.TopicStat, len(topics))}
+	for i, topic := range topics {
+		pt.Topics[i].Topic = topic.Name
+	}
+
+	negativeReviews := 0
+	for _, src := range sources {
+		for _, review := range src.Reviews {
+			if strings.TrimSpace(review.Text) == "" {
+				continue
+			}
+			pt.Reviews++
+			sentences := splitSentences(review.Text)
+			anyNegative := false
+			for i, keywords := range dictionary {
+				mentioned, score := false, 0
+				for _, sentence := range sentences {
+					if matchesAny(sentence, keywords) {
+						mentioned = true
+						score += lex.sentenceScore(sentence)
+					}
+				}
+				if !mentioned {
+					continue
+				}
+				if score == 0 && review.Rating > 0 {
+					switch {
+					case review.Rating >= 4:
+						score = 1
+					case review.Rating <= 2:
+						score = -1
+					}
+				}
+				stat := &pt.Topics[i]
+				stat.Mentions++
+				switch {
+				case score > 0:
+					stat.Positive++
+				case score < 0:
+					stat.Negative++
+					anyNegative = true
+				default:
+					stat.Neutral++
+				}
+			}
+			if anyNegative {
+				negativeReviews++
+			}
+		}
+	}
+
+	if pt.Reviews > 0 {
+		pt.NegativeShare = float64(negativeReviews) / float64(pt.Reviews)
+	}
+	for i := range pt.Topics {
+		stat := &pt.Topics[i]
+		if pt.Reviews > 0 {
+			stat.Share = float64(stat.Mentions) / float64(pt.Reviews)
+		}
+		if stat.Mentions > 0 {
+			stat.NetSentiment = float64(stat.Positive-stat.Negative) / float64(stat.Mentions)
+		}
+	}
+	pt.TopComplaints = rankTopics(pt.Topics, func(s domain.TopicStat) int { return s.Negative })
+	pt.TopPraise = rankTopics(pt.Topics, func(s domain.TopicStat) int { return s.Positive })
+	return pt
+}
+
+// rankTopics returns up to maxTopTopics topic names with a positive count,
+// highest first; ties keep dictionary order.
+func rankTopics(stats []domain.TopicStat, count func(domain.TopicStat) int) []string {
+	ranked := make([]domain.TopicStat, 0, len(stats))
+	for _, s := range stats {
+		if count(s) > 0 {
+			ranked = append(ranked, s)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return count(ranked[i]) > count(ranked[j]) })
+
+	var names []string
+	for i := 0; i < len(ranked) && i < maxTopTopics; i++ {
+		names = append(names, ranked[i].Topic)
+	}
+	return names
+}
+
+func newLexicon(custom *domain.SentimentLexicon) lexicon {
+	positive, negative, negators := defaultPositive, defaultNegative, defaultNegators
+	if custom != nil {
+		if custom.Positive != nil {
+			positive = custom.Positive
+		}
+		if custom.Negative != nil {
+			negative = custom.Negative
+		}
+		if custom.Negators != nil {
+			negators = custom.Negators
+		}
+	}
+
+	lex := lexicon{score: map[string]int{}, negators: map[string]bool{}}
+	for _, w := range positive {
+		lex.score[strings.ToLower(w)] = 1
+	}
+	for _, w := range negative {
+		lex.score[strings.ToLower(w)] = -1
+	}
+	for _, w := range negators {
+		lex.negators[strings.ToLower(w)] = true
+	}
+	return lex
+}
+
+// sentenceScore sums word scores, flipping the two words after a negator
+// (including contractions ending in n't).
+func (l lexicon) sentenceScore(words []string) int {
+	score, flip := 0, 0
+	for _, w := range words {
+		if l.negators[w] || strings.HasSuffix(w, "n't") {
+			flip = 2
+			continue
+		}
+		s := l.score[w]
+		if flip > 0 {
+			s = -s
+			flip--
+		}
+		score += s
+	}
+	return score
+}
+
+// splitSentences splits text into tokenized sentences.
+func splitSentences(text string) [][]string {
+	var sentences [][]string
+	for _, s := range strings.FieldsFunc(text, func(r rune) bool { return strings.ContainsRune(".!?;\n", r) }) {
+		if words := tokenize(s); len(words) > 0 {
+			sentences = append(sentences, words)
+		}
+	}
+	return sentences
+}
+
+// tokenize lower-cases text and splits it into words, keeping apostrophes
+// so contractions stay whole.
+func tokenize(text string) []string {
+	// Typographic apostrophes become ASCII so "don’t" matches "don't".
+	text = strings.ReplaceAll(strings.ToLower(text), "’", "'")
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+func matchesAny(words []string, keywords []keyword) bool {
+	for _, kw := range keywords {
+		for start := 0; start+len(kw.words) <= len(words); start++ {
+			if kw.matchesAt(words, start) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (kw keyword) matchesAt(words []string, start int) bool {
+	last := len(kw.words) - 1
+	for i, w := range kw.words {
+		got := words[start+i]
+		if i == last && kw.prefix {
+			if !strings.HasPrefix(got, w) {
+				return false
+			}
+		} else if got != w {
+			return false
+		}
+	}
+	return true
+}
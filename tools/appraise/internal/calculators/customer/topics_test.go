@@ -0,0 +1,233 @@
+package customer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// Topics
+// ---------------------------------------------------------------------------
+
+func TestTopics(t *testing.T) {
+	calc := New()
+
+	dictionary := func() []domain.TopicDefinition {
+		return []domain.TopicDefinition{
+			{Name: "battery", Keywords: []string{"batter*", "charge"}},
+			{Name: "screen", Keywords: []string{"screen", "display"}},
+			{Name: "support", Keywords: []string{"customer support", "support"}},
+			{Name: "price", Keywords: []string{"price"}},
+		}
+	}
+	battery := []domain.TopicDefinition{{Name: "battery", Keywords: []string{"batter*"}}}
+	site := func(texts ...string) []domain.ReviewSource {
+		src := domain.ReviewSource{Source: "site"}
+		for _, text := range texts {
+			src.Reviews = append(src.Reviews, domain.Review{Text: text})
+		}
+		return []domain.ReviewSource{src}
+	}
+
+	type want struct {
+		name       string
+		reviews    int
+		negShare   float64
+		topics     []domain.TopicStat
+		complaints string
+		praise     string
+	}
+
+	tests := []struct {
+		name        string
+		reviews     []domain.ReviewSource
+		competitors []domain.CompetitorData
+		topics      []domain.TopicDefinition
+		lexicon     *domain.SentimentLexicon
+		wantErr     bool
+		errContains string
+		wantProduct want
+		wantRivals  []want
+	}{
+		{
+			name: "rated_reviews_against_rival",
+			reviews: []domain.ReviewSource{{Source: "app_store", Reviews: []domain.Review{
+				{Rating: 2, Text: "The battery dies by noon. Screen is great though!"},
+				{Rating: 5, Text: "Love the screen, and the batteries last all day."},
+				{Rating: 4, Text: "Customer support was helpful"},
+				{Rating: 1, Text: "Support is not good. Battery life is ok"},
+				{Rating: 3, Text: "It works"},
+				{Rating: 5},
+			}}},
+			competitors: []domain.CompetitorData{
+				{Name: "rival", Reviews: site("Battery isn't bad at all", "Terrible screen")},
+				{Name: "unreviewed"},
+			},
+			topics: dictionary(),
+			// 5 reviews with text; the first and fourth have a negative mention.
+			wantProduct: want{
+				name:     "ours",
+				reviews:  5,
+				negShare: 0.4,
+				topics: []domain.TopicStat{
+					// "dies" (-1); "batteries" by prefix, no lexicon words, rated 5 (+1);
+					// "ok" has no score, rated 1 (-1)
+					{Topic: "battery", Mentions: 3, Share: 0.6, Positive: 1, Negative: 2, NetSentiment: -1.0 / 3},
+					{Topic: "screen", Mentions: 2, Share: 0.4, Positive: 2, NetSentiment: 1},
+					// "helpful" is not in the lexicon, rated 4; "not good" flips to -1
+					{Topic: "support", Mentions: 2, Share: 0.4, Positive: 1, Negative: 1},
+					{Topic: "price"},
+				},
+				complaints: "battery,support",
+				praise:     "screen,battery,support",
+			},
+			// "isn't bad" is negated to +1; unrated "Terrible screen" stays -1.
+			wantRivals: []want{{
+				name:     "rival",
+				reviews:  2,
+				negShare: 0.5,
+				topics: []domain.TopicStat{
+					{Topic: "battery", Mentions: 1, Share: 0.5, Positive: 1, NetSentiment: 1},
+					{Topic: "screen", Mentions: 1, Share: 0.5, Negative: 1, NetSentiment: -1},
+					{Topic: "support"},
+					{Topic: "price"},
+				},
+				complaints: "screen",
+				praise:     "battery",
+			}},
+		},
+		{
+			// Negative list replaced, positive list kept.
+			name:    "custom_lexicon",
+			reviews: site("Battery is meh", "Battery is great"),
+			topics:  battery,
+			lexicon: &domain.SentimentLexicon{Negative: []string{"meh"}},
+			wantProduct: want{
+				name: "ours", reviews: 2, negShare: 0.5,
+				topics:     []domain.TopicStat{{Topic: "battery", Mentions: 2, Share: 1, Positive: 1, Negative: 1}},
+				complaints: "battery",
+				praise:     "battery",
+			},
+		},
+		{
+			name:    "ascii_contraction",
+			reviews: site("The battery, I don't love it"),
+			topics:  battery,
+			wantProduct: want{
+				name: "ours", reviews: 1, negShare: 1,
+				topics:     []domain.TopicStat{{Topic: "battery", Mentions: 1, Share: 1, Negative: 1, NetSentiment: -1}},
+				complaints: "battery",
+			},
+		},
+		{
+			name:    "typographic_contraction",
+			reviews: site("The battery, I don’t love it"),
+			topics:  battery,
+			wantProduct: want{
+				name: "ours", reviews: 1, negShare: 1,
+				topics:     []domain.TopicStat{{Topic: "battery", Mentions: 1, Share: 1, Negative: 1, NetSentiment: -1}},
+				complaints: "battery",
+			},
+		},
+		{
+			name:    "negation_wears_off",
+			reviews: site("Battery is not bad but not great"),
+			topics:  battery,
+			wantProduct: want{
+				name: "ours", reviews: 1,
+				topics: []domain.TopicStat{{Topic: "battery", Mentions: 1, Share: 1, Neutral: 1}},
+			},
+		},
+		{
+			name:        "no_topics",
+			reviews:     site("Battery is great"),
+			wantErr:     true,
+			errContains: "customers.topics required",
+		},
+		{
+			name:        "no_reviews",
+			topics:      dictionary(),
+			wantErr:     true,
+			errContains: "product.reviews required",
+		},
+		{
+			name:    "duplicate_topic",
+			reviews: site("Battery is great"),
+			topics: []domain.TopicDefinition{
+				{Name: "battery", Keywords: []string{"battery"}},
+				{Name: "battery", Keywords: []string{"charge"}},
+			},
+			wantErr:     true,
+			errContains: `duplicate topic "battery"`,
+		},
+		{
+			name:        "no_keywords",
+			reviews:     site("Battery is great"),
+			topics:      []domain.TopicDefinition{{Name: "price"}},
+			wantErr:     true,
+			errContains: `topic "price": keywords required`,
+		},
+		{
+			name:        "empty_keyword",
+			reviews:     site("Battery is great"),
+			topics:      []domain.TopicDefinition{{Name: "price", Keywords: []string{" *"}}},
+			wantErr:     true,
+			errContains: `topic "price": empty keyword`,
+		},
+	}
+
+	check := func(t *testing.T, got domain.ProductTopics, w want) {
+		t.Helper()
+		if got.Name != w.name || got.Reviews != w.reviews || !almostEqual(got.NegativeShare, w.negShare) {
+			t.Errorf("name/reviews/negative share = %s/%d/%v, want %s/%d/%v", got.Name, got.Reviews, got.NegativeShare, w.name, w.reviews, w.negShare)
+		}
+		if len(got.Topics) != len(w.topics) {
+			t.Fatalf("%s: got %d topics, want %d", got.Name, len(got.Topics), len(w.topics))
+		}
+		for i, wt := range w.topics {
+			gt := got.Topics[i]
+			if gt.Topic != wt.Topic || gt.Mentions != wt.Mentions || gt.Positive != wt.Positive ||
+				gt.Negative != wt.Negative || gt.Neutral != wt.Neutral ||
+				!almostEqual(gt.Share, wt.Share) || !almostEqual(gt.NetSentiment, wt.NetSentiment) {
+				t.Errorf("%s topic %d = %+v, want %+v", got.Name, i, gt, wt)
+			}
+		}
+		if strings.Join(got.TopComplaints, ",") != w.complaints || strings.Join(got.TopPraise, ",") != w.praise {
+			t.Errorf("%s top complaints/praise = %v/%v, want %s/%s", got.Name, got.TopComplaints, got.TopPraise, w.complaints, w.praise)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &domain.AppraisalInput{
+				Product:     &domain.ProductDefinition{Name: "ours", Reviews: tt.reviews},
+				Competitors: tt.competitors,
+			}
+			if tt.topics != nil {
+				input.Customers = &domain.CustomerMetrics{Topics: tt.topics, SentimentLexicon: tt.lexicon}
+			}
+			result, err := calc.Topics(input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			check(t, result.ProductTopics, tt.wantProduct)
+			if len(result.Competitors) != len(tt.wantRivals) {
+				t.Fatalf("got %d competitors, want %d (unreviewed skipped)", len(result.Competitors), len(tt.wantRivals))
+			}
+			for i, w := range tt.wantRivals {
+				check(t, result.Competitors[i], w)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package financial
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
+)
+
+// maxScheduleBreakpoints bounds the number of step-cost breakpoints, so a
+// tiny step size over a large volume range fails fast instead of hanging.
+const maxScheduleBreakpoints = 100000
+
+// costSchedule evaluates fixed and variable cost at a volume.
+type costSchedule struct {
+	fixed    float64
+	baseUnit float64
+	steps    []domain.StepCost
+	tiers    []domain.UnitCostTier
+	graduate bool
+}
+
+// BreakEvenSchedule finds every volume between 0 and
+// financials.cost_schedule.max_volume where profit changes sign, with fixed
+// costs that step up with volume and variable costs that fall in volume
+// tiers:
+//
+//	profit(q) = price*q - variable(q) - fixed_costs - step costs(q)
+//
+// A step cost with every=N adds cost for each started block of N units; a
+// bracketed step cost charges the highest bracket reached. Unit cost tiers
+// apply to all units once reached ("all_units") or only to the units within
+// each tier ("graduated"). Profit is linear between breakpoints, so
+// crossings are exact. The best profit is taken over the breakpoints and
+// the ends of the range.
+func (c *Calculator) BreakEvenSchedule(input *domain.AppraisalInput) (*domain.BreakEvenScheduleResult, error) {
+	if input.Financials == nil || input.Financials.CostSchedule == nil {
+		return nil, fmt.Errorf("financials.cost_schedule required")
+	}
+	if input.Product == nil {
+		return nil, fmt.Errorf("product definition required")
+	}
+	f := input.Financials
+	cs := f.CostSchedule
+	price := input.Product.Price
+	if cs.MaxVolume <= 0 {
+		return nil, fmt.Errorf("cost_schedule.max_volume must be positive")
+	}
+	if f.VariableCostPerUnit == nil && (len(cs.UnitCostTiers) == 0 || cs.UnitCostTiers[0].From > 0) {
+		return nil, fmt.Errorf("variable_cost_per_unit or unit_cost_tiers from 0 required")
+	}
+
+	sched := costSchedule{
+		fixed:    valuation.ValueOr(f.FixedCosts, 0),
+		baseUnit: valuation.ValueOr(f.VariableCostPerUnit, 0),
+		steps:    cs.StepCosts,
+		tiers:    cs.UnitCostTiers,
+	}
+	switch cs.UnitCostMode {
+	case "", "all_units":
+	case "graduated":
+		sched.graduate = true
+	default:
+		return nil, fmt.Errorf("unit_cost_mode must be all_units or graduated")
+	}
+
+	// Breakpoints: where a step, bracket or tier changes, plus both ends.
+	causes := map[float64][]string{0: nil, cs.MaxVolume: nil}
+	addBreak := func(q float64, cause string) {
+		if q > 0 && q < cs.MaxVolume {
+			causes[q] = append(causes[q], cause)
+		}
+	}
+	for _, s := range cs.StepCosts {
+		switch {
+		case len(s.Brackets) > 0:
+			for i, b := range s.Brackets {
+				if i > 0 && b.From <= s.Brackets[i-1].From {
+					return nil, fmt.Errorf("step cost %q: brackets must be ascending", s.Name)
+				}
+				addBreak(b.From, s.Name)
+			}
+		case s.Cost != nil && s.Every != nil:
+			every := *s.Every
+			if every <= 0 {
+				return nil, fmt.Errorf("step cost %q: every must be positive", s.Name)
+			}
+			if cs.MaxVolume/every > maxScheduleBreakpoints {
+				return nil, fmt.Errorf("step cost %q: more than %d steps within max_volume", s.Name, maxScheduleBreakpoints)
+			}
+			for k := 1.0; k*every < cs.MaxVolume; k++ {
+				addBreak(k*every, s.Name)
+			}
+		default:
+			return nil, fmt.Errorf("step cost %q: cost and every, or brackets, required", s.Name)
+		}
+	}
+	for i, t := range cs.UnitCostTiers {
+		if i > 0 && t.From <= cs.UnitCostTiers[i-1].From {
+			return nil, fmt.Errorf("unit_cost_tiers must be ascending")
+		}
+		addBreak(t.From, fmt.Sprintf("unit cost tier from %g", t.From))
+	}
+	breaks := make([]float64, 0, len(causes))
+	for q := range causes {
+		breaks = append(breaks, q)
+	}
+	sort.Float64s(breaks)
+
+	result := &domain.BreakEvenScheduleResult{
+		Price:     price,
+		MaxVolume: cs.MaxVolume,
+		Crossings: []domain.BreakEvenCrossing{},
+		MaxProfit: math.Inf(-1),
+	}
+
+	// Walk exact values at breakpoints and one-sided limits inside each
+	// segment, recording every change between loss (< 0) and profit (>= 0).
+	// The sign at zero volume only sets the starting state.
+	var prev bool
+	cross := func(q, profit float64, cause string) {
+		now := profit >= 0
+		if q == 0 {
+			prev = now
+			return
+		}
+		if now != prev {
+			direction := "to_loss"
+			if now {
+				direction = "to_profit"
+			}
+			result.Crossings = append(result.Crossings, domain.BreakEvenCrossing{Volume: q, Direction: direction, Cause: cause})
+			prev = now
+		}
+	}
+	for i, q := range breaks {
+		at := sched.profit(price, q, q)
+		cross(q, at, strings.Join(causes[q], ", "))
+		if at > result.MaxProfit {
+			result.MaxProfit, result.MaxProfitVolume = at, q
+		}
+		if i == len(breaks)-1 {
+			break
+		}
+
+		next := breaks[i+1]
+		mid := (q + next) / 2
+		left, right := sched.profit(price, q, mid), sched.profit(price, next, mid)
+		cross(q, left, strings.Join(causes[q], ", "))
+		if (left >= 0) != (right >= 0) {
+			cross(q+(next-q)*left/(left-right), right, "")
+		}
+	}
+	result.ProfitableAtMax = sched.profit(price, cs.MaxVolume, cs.MaxVolume) >= 0
+	for _, cr := range result.Crossings {
+		if cr.Direction == "to_profit" {
+			v := cr.Volume
+			result.FirstBreakEven = &v
+			break
+		}
+	}
+
+	points := 20
+	if cs.CurvePoints != nil {
+		if *cs.CurvePoints <= 0 {
+			return nil, fmt.Errorf("curve_points must be positive")
+		}
+		points = *cs.CurvePoints
+	}
+	for j := 0; j <= points; j++ {
+		q := cs.MaxVolume * float64(j) / float64(points)
+		mp := domain.MarginPoint{
+			Volume:       q,
+			Revenue:      price * q,
+			VariableCost: sched.variable(q, q),
+			FixedCost:    sched.fixedAt(q),
+		}
+		mp.Profit = mp.Revenue - mp.VariableCost - mp.FixedCost
+		if mp.Revenue > 0 {
+			mp.MarginPct = mp.Profit / mp.Revenue
+		}
+		result.Curve = append(result.Curve, mp)
+	}
+
+	return result, nil
+}
+
+// profit evaluates profit at volume q with steps, brackets and the
+// all-units tier taken at volume ref, so a segment's one-sided limits can
+// be evaluated at its ends.
+func (s costSchedule) profit(price, q, ref float64) float64 {
+	return price*q - s.variable(q, ref) - s.fixedAt(ref)
+}
+
+func (s costSchedule) fixedAt(q float64) float64 {
+	total := s.fixed
+	for _, step := range s.steps {
+		if len(step.Brackets) > 0 {
+			for i := len(step.Brackets) - 1; i >= 0; i-- {
+				if q >= step.Brackets[i].From {
+					total += step.Brackets[i].Cost
+					break
+				}
+			}
+			continue
+		}
+		if q > 0 {
+			total += math.Ceil(q / *step.Every) * *step.Cost
+		}
+	}
+	return total
+}
+
+func (s costSchedule) variable(q, ref float64) float64 {
+	if !s.graduate {
+		rate := s.baseUnit
+		for _, t := range s.tiers {
+			if ref >= t.From {
+				rate = t.UnitCost
+			}
+		}
+		return rate * q
+	}
+
+	total, from, rate := 0.0, 0.0, s.baseUnit
+	for _, t := range s.tiers {
+		if q <= t.From {
+			break
+		}
+		total += rate * (t.From - from)
+		from, rate = t.From, t.UnitCost
+	}
+	return total + rate*(q-from)
+}
@@ -0,0 +1,249 @@
+package financial
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// BreakEvenSchedule
+// ---------------------------------------------------------------------------
+
+func TestBreakEvenSchedule(t *testing.T) {
+	calc := New()
+
+	schedule := func(cs *domain.CostSchedule) *domain.FinancialData {
+		return &domain.FinancialData{VariableCostPerUnit: ptr(5), CostSchedule: cs}
+	}
+
+	type crossing struct {
+		volume    float64
+		direction string
+		cause     string
+	}
+	tests := []struct {
+		name          string
+		price         float64
+		financials    *domain.FinancialData
+		wantErr       bool
+		errContains   string
+		wantCrossings []crossing
+		wantMaxProfit float64
+		wantMaxVolume float64
+		wantAtMax     bool
+		wantPoints    int      // curve points; 0 means the default 21
+		wantMidProfit *float64 // profit at the middle curve point
+	}{
+		{
+			name:  "plain fixed and variable cost",
+			price: 40,
+			financials: &domain.FinancialData{
+				FixedCosts:          ptr(1000),
+				VariableCostPerUnit: ptr(10),
+				CostSchedule:        &domain.CostSchedule{MaxVolume: 100},
+			},
+			wantCrossings: []crossing{{1000.0 / 30, "to_profit", ""}},
+			wantMaxProfit: 2000,
+			wantMaxVolume: 100,
+			wantAtMax:     true,
+		},
+		{
+			// 30/unit margin, 10000 per started block of 500 customers
+			name:  "support team every 500 customers",
+			price: 40,
+			financials: &domain.FinancialData{
+				VariableCostPerUnit: ptr(10),
+				CostSchedule: &domain.CostSchedule{
+					MaxVolume: 1200,
+					StepCosts: []domain.StepCost{{Name: "support", Cost: ptr(10000), Every: ptr(500)}},
+				},
+			},
+			wantCrossings: []crossing{
+				{1000.0 / 3, "to_profit", ""},
+				{500, "to_loss", "support"},
+				{2000.0 / 3, "to_profit", ""},
+			},
+			wantMaxProfit: 10000,
+			wantMaxVolume: 1000,
+			wantAtMax:     true,
+		},
+		{
+			name:  "volume discount on all units",
+			price: 20,
+			financials: &domain.FinancialData{
+				FixedCosts:          ptr(6000),
+				VariableCostPerUnit: ptr(15),
+				CostSchedule: &domain.CostSchedule{
+					MaxVolume:     1500,
+					UnitCostTiers: []domain.UnitCostTier{{From: 1000, UnitCost: 12}},
+				},
+			},
+			wantCrossings: []crossing{{1000, "to_profit", "unit cost tier from 1000"}},
+			wantMaxProfit: 6000,
+			wantMaxVolume: 1500,
+			wantAtMax:     true,
+		},
+		{
+			name:  "graduated volume discount",
+			price: 20,
+			financials: &domain.FinancialData{
+				FixedCosts:          ptr(6000),
+				VariableCostPerUnit: ptr(15),
+				CostSchedule: &domain.CostSchedule{
+					MaxVolume:     1500,
+					UnitCostTiers: []domain.UnitCostTier{{From: 1000, UnitCost: 12}},
+					UnitCostMode:  "graduated",
+				},
+			},
+			wantCrossings: []crossing{{1125, "to_profit", ""}},
+			wantMaxProfit: 3000,
+			wantMaxVolume: 1500,
+			wantAtMax:     true,
+		},
+		{
+			name:  "tiered licence fee",
+			price: 10,
+			financials: &domain.FinancialData{
+				VariableCostPerUnit: ptr(5),
+				CostSchedule: &domain.CostSchedule{
+					MaxVolume: 1000,
+					StepCosts: []domain.StepCost{{Name: "licence", Brackets: []domain.CostBracket{
+						{From: 0, Cost: 1000}, {From: 500, Cost: 4000},
+					}}},
+				},
+			},
+			wantCrossings: []crossing{
+				{200, "to_profit", ""},
+				{500, "to_loss", "licence"},
+				{800, "to_profit", ""},
+			},
+			wantMaxProfit: 1000,
+			wantMaxVolume: 1000,
+			wantAtMax:     true,
+		},
+		{
+			// 50 units: revenue 2000, variable 500, fixed 10000
+			name:  "never profitable",
+			price: 40,
+			financials: &domain.FinancialData{
+				FixedCosts:          ptr(10000),
+				VariableCostPerUnit: ptr(10),
+				CostSchedule:        &domain.CostSchedule{MaxVolume: 100, CurvePoints: intPtr(4)},
+			},
+			wantMaxProfit: -7000,
+			wantMaxVolume: 100,
+			wantPoints:    5,
+			wantMidProfit: ptr(-8500),
+		},
+		{
+			name:        "no schedule",
+			financials:  &domain.FinancialData{},
+			wantErr:     true,
+			errContains: "cost_schedule required",
+		},
+		{
+			name:        "bad max volume",
+			price:       10,
+			financials:  schedule(&domain.CostSchedule{}),
+			wantErr:     true,
+			errContains: "max_volume must be positive",
+		},
+		{
+			name:        "no variable cost",
+			price:       10,
+			financials:  &domain.FinancialData{CostSchedule: &domain.CostSchedule{MaxVolume: 10}},
+			wantErr:     true,
+			errContains: "variable_cost_per_unit",
+		},
+		{
+			name:        "bad unit cost mode",
+			price:       10,
+			financials:  schedule(&domain.CostSchedule{MaxVolume: 10, UnitCostMode: "tiered"}),
+			wantErr:     true,
+			errContains: "unit_cost_mode",
+		},
+		{
+			name:        "incomplete step cost",
+			price:       10,
+			financials:  schedule(&domain.CostSchedule{MaxVolume: 10, StepCosts: []domain.StepCost{{Name: "ops", Cost: ptr(1)}}}),
+			wantErr:     true,
+			errContains: `step cost "ops"`,
+		},
+		{
+			name:        "too many steps",
+			price:       10,
+			financials:  schedule(&domain.CostSchedule{MaxVolume: 1e9, StepCosts: []domain.StepCost{{Name: "ops", Cost: ptr(1), Every: ptr(1)}}}),
+			wantErr:     true,
+			errContains: "more than",
+		},
+		{
+			name:        "unsorted brackets",
+			price:       10,
+			financials:  schedule(&domain.CostSchedule{MaxVolume: 10, StepCosts: []domain.StepCost{{Name: "fee", Brackets: []domain.CostBracket{{From: 5}, {From: 1}}}}}),
+			wantErr:     true,
+			errContains: "ascending",
+		},
+		{
+			name:        "unsorted unit cost tiers",
+			price:       10,
+			financials:  schedule(&domain.CostSchedule{MaxVolume: 10, UnitCostTiers: []domain.UnitCostTier{{From: 5}, {From: 5}}}),
+			wantErr:     true,
+			errContains: "ascending",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.BreakEvenSchedule(&domain.AppraisalInput{
+				Product:    &domain.ProductDefinition{Price: tt.price},
+				Financials: tt.financials,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Crossings) != len(tt.wantCrossings) {
+				t.Fatalf("crossings = %+v, want %+v", result.Crossings, tt.wantCrossings)
+			}
+			for i, w := range tt.wantCrossings {
+				got := result.Crossings[i]
+				if !almostEqual(got.Volume, w.volume) || got.Direction != w.direction || got.Cause != w.cause {
+					t.Errorf("crossing %d = %+v, want %+v", i, got, w)
+				}
+			}
+			var wantFirst *float64
+			if len(tt.wantCrossings) > 0 {
+				wantFirst = &tt.wantCrossings[0].volume
+			}
+			if (result.FirstBreakEven == nil) != (wantFirst == nil) || result.FirstBreakEven != nil && !almostEqual(*result.FirstBreakEven, *wantFirst) {
+				t.Errorf("FirstBreakEven = %v, want %v", result.FirstBreakEven, wantFirst)
+			}
+			if !almostEqual(result.MaxProfit, tt.wantMaxProfit) || result.MaxProfitVolume != tt.wantMaxVolume {
+				t.Errorf("max profit = %v at %v, want %v at %v", result.MaxProfit, result.MaxProfitVolume, tt.wantMaxProfit, tt.wantMaxVolume)
+			}
+			if result.ProfitableAtMax != tt.wantAtMax {
+				t.Errorf("ProfitableAtMax = %v, want %v", result.ProfitableAtMax, tt.wantAtMax)
+			}
+			points := tt.wantPoints
+			if points == 0 {
+				points = 21
+			}
+			if len(result.Curve) != points || result.Curve[points-1].Volume != tt.financials.CostSchedule.MaxVolume {
+				t.Errorf("curve has %d points ending at %v", len(result.Curve), result.Curve[len(result.Curve)-1].Volume)
+			}
+			if mid := result.Curve[len(result.Curve)/2]; tt.wantMidProfit != nil && !almostEqual(mid.Profit, *tt.wantMidProfit) {
+				t.Errorf("profit at %v = %v, want %v", mid.Volume, mid.Profit, *tt.wantMidProfit)
+			}
+		})
+	}
+}
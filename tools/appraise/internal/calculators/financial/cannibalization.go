@@ -0,0 +1,156 @@
+package financial
+
+import (
+	"fmt"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+const defaultCannibalizationMonths = 24
+
+// CannibalizationTimeline models cannibalization month by month
+// (bundle-valuation.md §7.3–7.4).
+//
+// Each month the standalone base churns at the standalone rate, then
+// migration_rates[m] of what remains moves to the bundle. Migrants and new
+// bundle customers churn at the bundle rate. The baseline is the same
+// standalone base with no bundle. Net effect = revenue with the bundle -
+// baseline revenue, split into the migration effect (discount loss vs churn
+// reduction) and the acquisition effect (new premium customers).
+func (c *Calculator) CannibalizationTimeline(input *domain.AppraisalInput) (*domain.CannibalizationTimelineResult, error) {
+	if input.Financials == nil || input.Financials.CannibalizationTimeline == nil {
+		return nil, fmt.Errorf("financials.cannibalization_timeline required")
+	}
+	f := input.Financials
+	t := f.CannibalizationTimeline
+
+	months := defaultCannibalizationMonths
+	if t.Months != nil {
+		months = *t.Months
+	}
+	if months <= 0 {
+		return nil, fmt.Errorf("months must be positive")
+	}
+
+	if t.StandaloneCustomers == nil || *t.StandaloneCustomers < 0 {
+		return nil, fmt.Errorf("standalone_customers required (non-negative)")
+	}
+	standaloneRev := t.StandaloneRevenue
+	if standaloneRev == nil {
+		standaloneRev = f.MigratedCustomerOldRev
+	}
+	bundleRev := t.BundleRevenue
+	if bundleRev == nil {
+		bundleRev = f.MigratedCustomerNewRev
+	}
+	if standaloneRev == nil || bundleRev == nil {
+		return nil, fmt.Errorf("standalone_revenue and bundle_revenue required (or migrated_customer_old_revenue/new_revenue)")
+	}
+	for _, r := range t.MigrationRates {
+		if r < 0 || r > 1 {
+			return nil, fmt.Errorf("migration_rates must be between 0 and 1")
+		}
+	}
+	for _, n := range t.NewBundleCustomers {
+		if n < 0 {
+			return nil, fmt.Errorf("new_bundle_customers must be non-negative")
+		}
+	}
+
+	churn, bundleChurn, err := timelineChurn(t, input.Customers)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.CannibalizationTimelineResult{
+		StandaloneChurn: churn,
+		BundleChurn:     bundleChurn,
+	}
+
+	standalone := *t.StandaloneCustomers
+	baseline := standalone
+	var migrated, acquired, cumulative float64
+	lastNonPositive := 0
+
+	for m := 1; m <= months; m++ {
+		standalone *= 1 - churn
+		baseline *= 1 - churn
+		migrations := standalone * rampValue(t.MigrationRates, m)
+		standalone -= migrations
+		migrated = migrated*(1-bundleChurn) + migrations
+		acquired = acquired*(1-bundleChurn) + rampValue(t.NewBundleCustomers, m)
+
+		row := domain.CannibalizationMonth{
+			Month:                    m,
+			Migrations:               migrations,
+			StandaloneCustomers:      standalone,
+			MigratedCustomers:        migrated,
+			NewBundleCustomers:       acquired,
+			BaselineRevenue:          baseline * *standaloneRev,
+			MigratedRevenue:          migrated * *bundleRev,
+			ForgoneStandaloneRevenue: (baseline - standalone) * *standaloneRev,
+			AcquisitionEffect:        acquired * *bundleRev,
+		}
+		row.Revenue = standalone**standaloneRev + row.MigratedRevenue + row.AcquisitionEffect
+		row.MigrationEffect = row.MigratedRevenue - row.ForgoneStandaloneRevenue
+		row.NetEffect = row.MigrationEffect + row.AcquisitionEffect
+		cumulative += row.NetEffect
+		row.CumulativeNet = cumulative
+
+		if result.MigrationOffsetMonth == nil && migrated > 0 && row.MigrationEffect >= 0 {
+			result.MigrationOffsetMonth = intPtr(m)
+		}
+		if result.MonthlyPositiveMonth == nil && row.NetEffect > 0 {
+			result.MonthlyPositiveMonth = intPtr(m)
+		}
+		if cumulative <= 0 {
+			lastNonPositive = m
+		}
+
+		result.TotalMigrationEffect += row.MigrationEffect
+		result.TotalAcquisitionEffect += row.AcquisitionEffect
+		result.Months = append(result.Months, row)
+	}
+
+	result.CumulativeNetRevenue = cumulative
+	result.NetPositive = cumulative > 0
+	if lastNonPositive < months {
+		result.CumulativePositiveMonth = intPtr(lastNonPositive + 1)
+	}
+
+	return result, nil
+}
+
+// timelineChurn resolves monthly standalone and bundle churn. Standalone
+// churn is standalone_churn, else customers.base_churn_rate. Bundle churn is
+// bundle_churn, else standalone churn reduced by churn_reduction, else
+// customers.premium_churn_rate, else standalone churn.
+func timelineChurn(t *domain.CannibalizationTimeline, cm *domain.CustomerMetrics) (float64, float64, error) {
+	churn := t.StandaloneChurn
+	if churn == nil && cm != nil {
+		churn = cm.BaseChurnRate
+	}
+	if churn == nil {
+		return 0, 0, fmt.Errorf("standalone_churn or customers.base_churn_rate required")
+	}
+
+	bundle := t.BundleChurn
+	if bundle == nil && t.ChurnReduction != nil {
+		if *t.ChurnReduction < 0 || *t.ChurnReduction > 1 {
+			return 0, 0, fmt.Errorf("churn_reduction must be between 0 and 1")
+		}
+		v := *churn * (1 - *t.ChurnReduction)
+		bundle = &v
+	}
+	if bundle == nil && cm != nil {
+		bundle = cm.PremiumChurnRate
+	}
+	if bundle == nil {
+		bundle = churn
+	}
+
+	if *churn < 0 || *churn > 1 || *bundle < 0 || *bundle > 1 {
+		return 0, 0, fmt.Errorf("churn rates must be between 0 and 1")
+	}
+	return *churn, *bundle, nil
+}
@@ -0,0 +1,150 @@
+package financial
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func TestCannibalizationTimeline(t *testing.T) {
+	calc := New()
+
+	// 1000 standalone at 100/month churning 10%; half of month-1 survivors
+	// migrate to an 80/month bundle churning 5%. Migration effect in month k
+	// = 450 * (80*0.95^(k-1) - 100*0.9^(k-1)).
+	result, err := calc.CannibalizationTimeline(&domain.AppraisalInput{
+		Financials: &domain.FinancialData{
+			MigratedCustomerOldRev: ptr(100),
+			MigratedCustomerNewRev: ptr(80),
+			CannibalizationTimeline: &domain.CannibalizationTimeline{
+				Months:              intPtr(24),
+				StandaloneCustomers: ptr(1000),
+				MigrationRates:      []float64{0.5, 0},
+				StandaloneChurn:     ptr(0.10),
+				ChurnReduction:      ptr(0.5),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !almostEqual(result.BundleChurn, 0.05) || len(result.Months) != 24 {
+		t.Fatalf("bundle churn/months = %v/%d, want 0.05/24", result.BundleChurn, len(result.Months))
+	}
+
+	m1 := result.Months[0]
+	if !almostEqual(m1.Migrations, 450) || !almostEqual(m1.StandaloneCustomers, 450) ||
+		!almostEqual(m1.BaselineRevenue, 90000) || !almostEqual(m1.Revenue, 81000) ||
+		!almostEqual(m1.ForgoneStandaloneRevenue, 45000) || !almostEqual(m1.NetEffect, -9000) {
+		t.Errorf("month 1 = %+v", m1)
+	}
+
+	cumulative := 0.0
+	for k, row := range result.Months {
+		want := 450 * (80*math.Pow(0.95, float64(k)) - 100*math.Pow(0.9, float64(k)))
+		cumulative += want
+		if math.Abs(row.MigrationEffect-want) > 1e-6 || math.Abs(row.CumulativeNet-cumulative) > 1e-6 {
+			t.Fatalf("month %d effect/cumulative = %v/%v, want %v/%v", row.Month, row.MigrationEffect, row.CumulativeNet, want, cumulative)
+		}
+		if row.AcquisitionEffect != 0 || row.NetEffect != row.MigrationEffect {
+			t.Fatalf("month %d: unexpected acquisition effect %v", row.Month, row.AcquisitionEffect)
+		}
+	}
+
+	// (0.95/0.9)^(k-1) > 1.25 first at k = 6; cumulative turns positive at 11.
+	if result.MigrationOffsetMonth == nil || *result.MigrationOffsetMonth != 6 {
+		t.Errorf("MigrationOffsetMonth = %v, want 6", result.MigrationOffsetMonth)
+	}
+	if result.MonthlyPositiveMonth == nil || *result.MonthlyPositiveMonth != 6 {
+		t.Errorf("MonthlyPositiveMonth = %v, want 6", result.MonthlyPositiveMonth)
+	}
+	if result.CumulativePositiveMonth == nil || *result.CumulativePositiveMonth != 11 {
+		t.Errorf("CumulativePositiveMonth = %v, want 11", result.CumulativePositiveMonth)
+	}
+	if !result.NetPositive || !almostEqual(result.CumulativeNetRevenue, cumulative) ||
+		!almostEqual(result.TotalMigrationEffect, cumulative) {
+		t.Errorf("totals = %v/%v, want %v", result.CumulativeNetRevenue, result.TotalMigrationEffect, cumulative)
+	}
+}
+
+func TestCannibalizationTimelineNeverPositive(t *testing.T) {
+	calc := New()
+
+	// Same churn with and without the bundle: migration is a pure discount
+	// loss, and 1 new customer a month at 80 cannot cover 10% migration.
+	result, err := calc.CannibalizationTimeline(&domain.AppraisalInput{
+		Customers: &domain.CustomerMetrics{BaseChurnRate: ptr(0.02)},
+		Financials: &domain.FinancialData{
+			CannibalizationTimeline: &domain.CannibalizationTimeline{
+				Months:              intPtr(12),
+				StandaloneCustomers: ptr(1000),
+				StandaloneRevenue:   ptr(100),
+				BundleRevenue:       ptr(80),
+				MigrationRates:      []float64{0.1},
+				NewBundleCustomers:  []float64{1},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.StandaloneChurn != 0.02 || result.BundleChurn != 0.02 {
+		t.Errorf("churn = %v/%v, want 0.02/0.02", result.StandaloneChurn, result.BundleChurn)
+	}
+	if result.MigrationOffsetMonth != nil || result.MonthlyPositiveMonth != nil || result.CumulativePositiveMonth != nil {
+		t.Errorf("months = %v/%v/%v, want all nil", result.MigrationOffsetMonth, result.MonthlyPositiveMonth, result.CumulativePositiveMonth)
+	}
+	if result.NetPositive || result.TotalAcquisitionEffect <= 0 || result.TotalMigrationEffect >= 0 {
+		t.Errorf("totals = %+v", result)
+	}
+	// Month 1: 1000*0.98 = 980, 98 migrate: -98*20 + 1*80
+	if !almostEqual(result.Months[0].NetEffect, -1880) {
+		t.Errorf("month 1 net = %v, want -1880", result.Months[0].NetEffect)
+	}
+}
+
+func TestCannibalizationTimelineErrors(t *testing.T) {
+	calc := New()
+
+	timeline := func(mod func(*domain.CannibalizationTimeline)) *domain.AppraisalInput {
+		tl := &domain.CannibalizationTimeline{
+			StandaloneCustomers: ptr(100),
+			StandaloneRevenue:   ptr(100),
+			BundleRevenue:       ptr(80),
+			StandaloneChurn:     ptr(0.05),
+		}
+		mod(tl)
+		return &domain.AppraisalInput{Financials: &domain.FinancialData{CannibalizationTimeline: tl}}
+	}
+
+	tests := []struct {
+		name    string
+		input   *domain.AppraisalInput
+		wantErr string
+	}{
+		{"no timeline", &domain.AppraisalInput{Financials: &domain.FinancialData{}}, "cannibalization_timeline required"},
+		{"no customers", timeline(func(t *domain.CannibalizationTimeline) { t.StandaloneCustomers = nil }), "standalone_customers required"},
+		{"no revenue", timeline(func(t *domain.CannibalizationTimeline) { t.BundleRevenue = nil }), "bundle_revenue required"},
+		{"no churn", timeline(func(t *domain.CannibalizationTimeline) { t.StandaloneChurn = nil }), "standalone_churn"},
+		{"bad months", timeline(func(t *domain.CannibalizationTimeline) { t.Months = intPtr(0) }), "months must be positive"},
+		{"bad migration", timeline(func(t *domain.CannibalizationTimeline) { t.MigrationRates = []float64{1.5} }), "migration_rates"},
+		{"bad reduction", timeline(func(t *domain.CannibalizationTimeline) { t.ChurnReduction = ptr(2) }), "churn_reduction"},
+		{"bad churn", timeline(func(t *domain.CannibalizationTimeline) { t.BundleChurn = ptr(-0.1) }), "churn rates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.CannibalizationTimeline(tt.input)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package financial
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
+)
+
+// ChurnValue converts a churn reduction into money (pricing-methods.md §6,
+// "Churn Reduction as Financial Lever").
+//
+// With monthly churn c0 before and c1 after launch, a base of N customers
+// and monthly ARPU r:
+//
+//	retained customers (12m) = N * ((1-c1)^12 - (1-c0)^12)
+//	retained annual revenue  = N * r * sum over t=1..12 of ((1-c1)^(t-1) - (1-c0)^(t-1))
+//	incremental CLV          = r * m * (1/c1 - 1/c0) per customer
+//
+// where m is gross_margin_pct (1 when unset, i.e. revenue CLV). The bundle
+// discount (standalone sum - bundle price) paid by the base over the same
+// 12 months is compared with the retained gross profit to show how much of
+// the discount retention pays back on its own.
+func (c *Calculator) ChurnValue(input *domain.AppraisalInput) (*domain.ChurnValueResult, error) {
+	if input.Customers == nil {
+		return nil, fmt.Errorf("customer metrics required")
+	}
+	cm := input.Customers
+	if cm.ChurnBefore == nil || cm.ChurnAfter == nil {
+		return nil, fmt.Errorf("churn_before and churn_after required")
+	}
+	before, after := *cm.ChurnBefore, *cm.ChurnAfter
+	if before <= 0 || before > 1 || after <= 0 || after > 1 {
+		return nil, fmt.Errorf("churn_before and churn_after must be monthly rates in (0, 1]")
+	}
+
+	var customers float64
+	switch {
+	case cm.TotalCustomers != nil:
+		customers = *cm.TotalCustomers
+	case input.Financials != nil && input.Financials.AverageCustomerCount != nil:
+		customers = *input.Financials.AverageCustomerCount
+	default:
+		return nil, fmt.Errorf("customers.total_customers or financials.average_customer_count required")
+	}
+	if customers <= 0 {
+		return nil, fmt.Errorf("customer base must be positive")
+	}
+
+	var arpu float64
+	switch {
+	case input.Financials != nil && input.Financials.RevenuePerCustomer != nil:
+		arpu = *input.Financials.RevenuePerCustomer
+	case input.Product != nil && input.Product.Price > 0:
+		arpu = input.Product.Price
+	default:
+		return nil, fmt.Errorf("financials.revenue_per_customer or product price required")
+	}
+
+	margin := 1.0
+	result := &domain.ChurnValueResult{
+		ChurnBefore:        before,
+		ChurnAfter:         after,
+		ChurnReduction:     (before - after) / before,
+		Customers:          customers,
+		RevenuePerCustomer: arpu,
+	}
+	if input.Financials != nil && input.Financials.GrossMarginPct != nil {
+		margin = *input.Financials.GrossMarginPct
+		result.GrossMarginPct = &margin
+	}
+
+	// Customer-months over the next 12 months per starting customer.
+	activeBefore, activeAfter := 0.0, 0.0
+	for t := 1; t <= 12; t++ {
+		activeBefore += math.Pow(1-before, float64(t-1))
+		activeAfter += math.Pow(1-after, float64(t-1))
+	}
+
+	result.CustomersSavedPerMonth = customers * (before - after)
+	result.RetainedCustomers12M = customers * (math.Pow(1-after, 12) - math.Pow(1-before, 12))
+	result.RetainedAnnualRevenue = customers * arpu * (activeAfter - activeBefore)
+	result.RetainedAnnualGrossProfit = result.RetainedAnnualRevenue * margin
+
+	result.LifespanBeforeMonths = 1 / before
+	result.LifespanAfterMonths = 1 / after
+	result.CLVBefore = arpu * margin * result.LifespanBeforeMonths
+	result.CLVAfter = arpu * margin * result.LifespanAfterMonths
+	result.IncrementalCLV = result.CLVAfter - result.CLVBefore
+	result.TotalIncrementalCLV = result.IncrementalCLV * customers
+
+	if input.Product != nil && len(input.Product.Components) > 0 {
+		if pct, err := valuation.BundleDiscount(input.Product); err == nil && pct > 0 {
+			discount := pct * valuation.StandaloneSum(input.Product)
+			annualCost := customers * discount * activeAfter
+			ratio := result.RetainedAnnualGrossProfit / annualCost
+			result.DiscountPerCustomer = &discount
+			result.AnnualDiscountCost = &annualCost
+			result.DiscountPaybackRatio = &ratio
+		}
+	}
+
+	switch {
+	case result.ChurnReduction <= 0:
+		result.Interpretation = "no_churn_reduction"
+	case result.DiscountPaybackRatio == nil:
+		result.Interpretation = "retention_value_without_discount_comparison"
+	case *result.DiscountPaybackRatio >= 1:
+		result.Interpretation = "retention_pays_for_discount"
+	case *result.DiscountPaybackRatio >= 0.5:
+		result.Interpretation = "retention_covers_part_of_discount"
+	default:
+		result.Interpretation = "discount_not_justified_by_retention"
+	}
+
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package financial
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func TestChurnValue(t *testing.T) {
+	calc := New()
+
+	activeBefore := (1 - math.Pow(0.95, 12)) / 0.05
+	activeAfter := (1 - math.Pow(0.96, 12)) / 0.04
+	retainedRevenue := 1000 * 50 * (activeAfter - activeBefore)
+
+	tests := []struct {
+		name        string
+		input       *domain.AppraisalInput
+		wantRevenue float64
+		wantIncCLV  float64
+		wantPayback *float64
+		wantInterp  string
+	}{
+		{
+			name: "with margin and bundle discount",
+			input: &domain.AppraisalInput{
+				Product: &domain.ProductDefinition{Price: 50, Components: []domain.Component{
+					{Name: "a", StandalonePrice: 30}, {Name: "b", StandalonePrice: 30},
+				}},
+				Customers:  &domain.CustomerMetrics{TotalCustomers: ptr(1000), ChurnBefore: ptr(0.05), ChurnAfter: ptr(0.04)},
+				Financials: &domain.FinancialData{RevenuePerCustomer: ptr(50), GrossMarginPct: ptr(0.6)},
+			},
+			wantRevenue: retainedRevenue,
+			wantIncCLV:  150, // 50 * 0.6 * (25 - 20)
+			wantPayback: ptr(retainedRevenue * 0.6 / (1000 * 10 * activeAfter)),
+			wantInterp:  "discount_not_justified_by_retention",
+		},
+		{
+			name: "revenue basis from product price and average count",
+			input: &domain.AppraisalInput{
+				Product:    &domain.ProductDefinition{Price: 50},
+				Customers:  &domain.CustomerMetrics{ChurnBefore: ptr(0.05), ChurnAfter: ptr(0.04)},
+				Financials: &domain.FinancialData{AverageCustomerCount: ptr(1000)},
+			},
+			wantRevenue: retainedRevenue,
+			wantIncCLV:  250,
+			wantInterp:  "retention_value_without_discount_comparison",
+		},
+		{
+			name: "retention pays for a small discount",
+			input: &domain.AppraisalInput{
+				Product: &domain.ProductDefinition{Price: 50, Components: []domain.Component{
+					{Name: "a", StandalonePrice: 51},
+				}},
+				Customers:  &domain.CustomerMetrics{TotalCustomers: ptr(1000), ChurnBefore: ptr(0.05), ChurnAfter: ptr(0.04)},
+				Financials: &domain.FinancialData{RevenuePerCustomer: ptr(50)},
+			},
+			wantRevenue: retainedRevenue,
+			wantIncCLV:  250,
+			wantPayback: ptr(retainedRevenue / (1000 * 1 * activeAfter)),
+			wantInterp:  "retention_pays_for_discount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.ChurnValue(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !almostEqual(result.ChurnReduction, 0.2) || !almostEqual(result.CustomersSavedPerMonth, 10) {
+				t.Errorf("reduction/saved = %v/%v, want 0.2/10", result.ChurnReduction, result.CustomersSavedPerMonth)
+			}
+			wantRetained := 1000 * (math.Pow(0.96, 12) - math.Pow(0.95, 12))
+			if math.Abs(result.RetainedCustomers12M-wantRetained) > 1e-9 {
+				t.Errorf("RetainedCustomers12M = %v, want %v", result.RetainedCustomers12M, wantRetained)
+			}
+			if math.Abs(result.RetainedAnnualRevenue-tt.wantRevenue) > 1e-6 {
+				t.Errorf("RetainedAnnualRevenue = %v, want %v", result.RetainedAnnualRevenue, tt.wantRevenue)
+			}
+			if math.Abs(result.IncrementalCLV-tt.wantIncCLV) > 1e-9 || math.Abs(result.TotalIncrementalCLV-tt.wantIncCLV*1000) > 1e-6 {
+				t.Errorf("IncrementalCLV = %v (total %v), want %v", result.IncrementalCLV, result.TotalIncrementalCLV, tt.wantIncCLV)
+			}
+			if !checkPayback(result.DiscountPaybackRatio, tt.wantPayback) {
+				t.Errorf("DiscountPaybackRatio = %v, want %v", result.DiscountPaybackRatio, tt.wantPayback)
+			}
+			if result.Interpretation != tt.wantInterp {
+				t.Errorf("Interpretation = %q, want %q", result.Interpretation, tt.wantInterp)
+			}
+		})
+	}
+}
+
+func checkPayback(got, want *float64) bool {
+	if got == nil || want == nil {
+		return got == nil && want == nil
+	}
+	return math.Abs(*got-*want) < 1e-9
+}
+
+func TestChurnValueErrors(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name    string
+		input   *domain.AppraisalInput
+		wantErr string
+	}{
+		{"no customers", &domain.AppraisalInput{}, "customer metrics required"},
+		{"no churn", &domain.AppraisalInput{Customers: &domain.CustomerMetrics{}}, "churn_before and churn_after required"},
+		{
+			"zero churn after",
+			&domain.AppraisalInput{Customers: &domain.CustomerMetrics{ChurnBefore: ptr(0.05), ChurnAfter: ptr(0)}},
+			"monthly rates",
+		},
+		{
+			"no base",
+			&domain.AppraisalInput{Customers: &domain.CustomerMetrics{ChurnBefore: ptr(0.05), ChurnAfter: ptr(0.04)}},
+			"total_customers",
+		},
+		{
+			"no ARPU",
+			&domain.AppraisalInput{Customers: &domain.CustomerMetrics{TotalCustomers: ptr(10), ChurnBefore: ptr(0.05), ChurnAfter: ptr(0.04)}},
+			"revenue_per_customer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.ChurnValue(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
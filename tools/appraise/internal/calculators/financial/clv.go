@@ -0,0 +1,214 @@
+package financial
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
+)
+
+const defaultCLVHorizonMonths = 60
+
+// CLVAdvanced calculates a discounted, churn-aware CLV.
+//
+// CLV = sum over months t=1..horizon of
+//   S(t-1) * (RPC * (1+arpu_growth)^((t-1)/12) + expansion) * margin% / (1+r)^t
+// where S is the share of the cohort still active (S(0) = 1) and r is the
+// monthly equivalent of the annual discount rate. S comes from monthly_churn,
+// a retention_curve (extrapolated at its last month-over-month ratio), or
+// 1/average_lifespan_months. LTV:CAC uses CAC = total acquisition spend /
+// new customers acquired, as in CACPayback.
+func (c *Calculator) CLVAdvanced(input *domain.AppraisalInput) (*domain.CLVAdvancedResult, error) {
+	if input.Financials == nil || input.Financials.CLVModel == nil {
+		return nil, fmt.Errorf("financials.clv_model required")
+	}
+	f := input.Financials
+	m := f.CLVModel
+
+	if f.RevenuePerCustomer == nil {
+		return nil, fmt.Errorf("revenue_per_customer required")
+	}
+	if f.GrossMarginPct == nil {
+		return nil, fmt.Errorf("gross_margin_pct required")
+	}
+
+	horizon := defaultCLVHorizonMonths
+	if m.HorizonMonths != nil {
+		horizon = *m.HorizonMonths
+	}
+	if horizon <= 0 {
+		return nil, fmt.Errorf("horizon_months must be positive")
+	}
+	annualRate := valuation.ValueOr(m.DiscountRate, defaultDiscountRate)
+	if annualRate <= -1 {
+		return nil, fmt.Errorf("discount_rate must be greater than -1")
+	}
+
+	churn := m.MonthlyChurn
+	if churn == nil && len(m.RetentionCurve) == 0 && f.AverageLifespanMonths != nil && *f.AverageLifespanMonths > 0 {
+		derived := 1 / *f.AverageLifespanMonths
+		churn = &derived
+	}
+	survival, err := survivalCurve(churn, m.RetentionCurve)
+	if err != nil {
+		return nil, err
+	}
+
+	model := clvParams{
+		rpc:         *f.RevenuePerCustomer,
+		margin:      *f.GrossMarginPct,
+		growth:      valuation.ValueOr(m.ARPUGrowth, 0),
+		expansion:   valuation.ValueOr(m.ExpansionRevenue, 0),
+		monthlyRate: math.Pow(1+annualRate, 1.0/12) - 1,
+		horizon:     horizon,
+	}
+	clv, undiscounted, lifetime := model.value(survival)
+
+	result := &domain.CLVAdvancedResult{
+		CLV:                    clv,
+		UndiscountedCLV:        undiscounted,
+		ExpectedLifetimeMonths: lifetime,
+		HorizonMonths:          horizon,
+		DiscountRate:           annualRate,
+	}
+
+	var cac *float64
+	if f.TotalAcquisitionSpend != nil && f.NewCustomersAcquired != nil && *f.NewCustomersAcquired > 0 {
+		v := *f.TotalAcquisitionSpend / *f.NewCustomersAcquired
+		cac = &v
+		result.CAC = cac
+		result.LTVToCAC = ltvToCAC(clv, cac)
+		if result.LTVToCAC != nil {
+			result.Interpretation = interpretLTVToCAC(*result.LTVToCAC)
+		}
+	}
+
+	if len(m.Cohorts) > 0 {
+		var weighted, customers float64
+		for _, cohort := range m.Cohorts {
+			cohortChurn, cohortCurve := churn, m.RetentionCurve
+			if cohort.MonthlyChurn != nil || len(cohort.RetentionCurve) > 0 {
+				cohortChurn, cohortCurve = cohort.MonthlyChurn, cohort.RetentionCurve
+			}
+			cohortSurvival, err := survivalCurve(cohortChurn, cohortCurve)
+			if err != nil {
+				return nil, fmt.Errorf("cohort %q: %w", cohort.Name, err)
+			}
+
+			params := model
+			if cohort.RevenuePerCustomer != nil {
+				params.rpc = *cohort.RevenuePerCustomer
+			}
+			cohortCLV, _, cohortLifetime := params.value(cohortSurvival)
+
+			cc := domain.CohortCLV{
+				Name:                   cohort.Name,
+				CLV:                    cohortCLV,
+				ExpectedLifetimeMonths: cohortLifetime,
+			}
+			if cohort.Customers != nil {
+				cc.Customers = *cohort.Customers
+				cc.CohortValue = cohortCLV * *cohort.Customers
+				weighted += cc.CohortValue
+				customers += *cohort.Customers
+			}
+			cohortCAC := cac
+			if cohort.AcquisitionCost != nil {
+				cohortCAC = cohort.AcquisitionCost
+			}
+			cc.LTVToCAC = ltvToCAC(cohortCLV, cohortCAC)
+			result.Cohorts = append(result.Cohorts, cc)
+		}
+		if customers > 0 {
+			blended := weighted / customers
+			result.BlendedCLV = &blended
+		}
+	}
+
+	return result, nil
+}
+
+// clvParams are the per-customer revenue assumptions for one CLV valuation.
+type clvParams struct {
+	rpc         float64
+	margin      float64
+	growth      float64
+	expansion   float64
+	monthlyRate float64
+	horizon     int
+}
+
+// value returns discounted CLV, undiscounted CLV, and expected active months
+// within the horizon for the given survival function.
+func (p clvParams) value(survival func(int) float64) (discounted, undiscounted, lifetime float64) {
+	for t := 1; t <= p.horizon; t++ {
+		active := survival(t - 1)
+		revenue := p.rpc*math.Pow(1+p.growth, float64(t-1)/12) + p.expansion
+		contribution := active * revenue * p.margin
+		undiscounted += contribution
+		discounted += contribution / math.Pow(1+p.monthlyRate, float64(t))
+		lifetime += active
+	}
+	return discounted, undiscounted, lifetime
+}
+
+// survivalCurve returns S(t), the share of a cohort still active after t months.
+func survivalCurve(churn *float64, curve []float64) (func(int) float64, error) {
+	if len(curve) > 0 {
+		prev := 1.0
+		for i, s := range curve {
+			if s < 0 || s > prev {
+				return nil, fmt.Errorf("retention_curve must be non-increasing values between 0 and 1 (month %d)", i+1)
+			}
+			prev = s
+		}
+		n := len(curve)
+		ratio := curve[0]
+		if n >= 2 && curve[n-2] > 0 {
+			ratio = curve[n-1] / curve[n-2]
+		}
+		return func(t int) float64 {
+			switch {
+			case t <= 0:
+				return 1
+			case t <= n:
+				return curve[t-1]
+			default:
+				return curve[n-1] * math.Pow(ratio, float64(t-n))
+			}
+		}, nil
+	}
+
+	if churn == nil {
+		return nil, fmt.Errorf("monthly_churn, retention_curve, or average_lifespan_months required")
+	}
+	if *churn < 0 || *churn > 1 {
+		return nil, fmt.Errorf("monthly_churn must be between 0 and 1")
+	}
+	rate := *churn
+	return func(t int) float64 {
+		return math.Pow(1-rate, float64(t))
+	}, nil
+}
+
+func ltvToCAC(clv float64, cac *float64) *float64 {
+	if cac == nil || *cac <= 0 {
+		return nil
+	}
+	ratio := clv / *cac
+	return &ratio
+}
+
+func interpretLTVToCAC(ratio float64) string {
+	switch {
+	case ratio >= 5:
+		return "possibly_underinvesting_in_growth"
+	case ratio >= 3:
+		return "healthy"
+	case ratio >= 1:
+		return "marginal"
+	default:
+		return "unprofitable_acquisition"
+	}
+}
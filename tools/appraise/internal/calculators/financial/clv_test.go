@@ -0,0 +1,191 @@
+package financial
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func TestCLVAdvanced(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name         string
+		rpc          float64
+		margin       float64
+		lifespan     *float64
+		model        domain.CLVModel
+		wantCLV      float64
+		wantLifetime float64
+	}{
+		{
+			name:   "constant churn, undiscounted",
+			rpc:    100,
+			margin: 0.5,
+			model: domain.CLVModel{
+				MonthlyChurn: ptr(0.1), DiscountRate: ptr(0), HorizonMonths: intPtr(12),
+			},
+			wantCLV:      358.78523175950005, // 50 * sum(0.9^t, t=0..11)
+			wantLifetime: 7.175704635190001,
+		},
+		{
+			name:   "retention curve extrapolated",
+			rpc:    100,
+			margin: 0.5,
+			model: domain.CLVModel{
+				RetentionCurve: []float64{0.8, 0.6}, DiscountRate: ptr(0), HorizonMonths: intPtr(4),
+			},
+			wantCLV:      142.5, // S = 1, 0.8, 0.6, 0.45
+			wantLifetime: 2.85,
+		},
+		{
+			name:   "ARPU growth",
+			rpc:    100,
+			margin: 1,
+			model: domain.CLVModel{
+				MonthlyChurn: ptr(0), DiscountRate: ptr(0), ARPUGrowth: ptr(0.12), HorizonMonths: intPtr(13),
+			},
+			wantCLV:      1376.6497908353176,
+			wantLifetime: 13,
+		},
+		{
+			name:   "discounted with expansion revenue",
+			rpc:    90,
+			margin: 1,
+			model: domain.CLVModel{
+				MonthlyChurn: ptr(0), DiscountRate: ptr(0.10), ExpansionRevenue: ptr(10), HorizonMonths: intPtr(12),
+			},
+			wantCLV:      1140.0487829330687,
+			wantLifetime: 12,
+		},
+		{
+			name:         "churn from lifespan",
+			rpc:          100,
+			margin:       0.5,
+			lifespan:     ptr(10),
+			model:        domain.CLVModel{DiscountRate: ptr(0), HorizonMonths: intPtr(12)},
+			wantCLV:      358.78523175950005,
+			wantLifetime: 7.175704635190001,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tt.model
+			result, err := calc.CLVAdvanced(&domain.AppraisalInput{
+				Financials: &domain.FinancialData{
+					RevenuePerCustomer:    ptr(tt.rpc),
+					GrossMarginPct:        ptr(tt.margin),
+					AverageLifespanMonths: tt.lifespan,
+					CLVModel:              &model,
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(result.CLV-tt.wantCLV) > 1e-6 {
+				t.Errorf("CLV = %v, want %v", result.CLV, tt.wantCLV)
+			}
+			if math.Abs(result.ExpectedLifetimeMonths-tt.wantLifetime) > 1e-9 {
+				t.Errorf("ExpectedLifetimeMonths = %v, want %v", result.ExpectedLifetimeMonths, tt.wantLifetime)
+			}
+			if result.CAC != nil || result.LTVToCAC != nil {
+				t.Errorf("CAC/LTVToCAC should be nil without acquisition data")
+			}
+		})
+	}
+}
+
+func TestCLVAdvancedCohorts(t *testing.T) {
+	calc := New()
+
+	result, err := calc.CLVAdvanced(&domain.AppraisalInput{
+		Financials: &domain.FinancialData{
+			RevenuePerCustomer:    ptr(100),
+			GrossMarginPct:        ptr(0.5),
+			TotalAcquisitionSpend: ptr(1000),
+			NewCustomersAcquired:  ptr(10),
+			CLVModel: &domain.CLVModel{
+				MonthlyChurn:  ptr(0.1),
+				DiscountRate:  ptr(0),
+				HorizonMonths: intPtr(12),
+				Cohorts: []domain.CLVCohort{
+					{Name: "early", Customers: ptr(100), MonthlyChurn: ptr(0)},
+					{Name: "late", Customers: ptr(300), RevenuePerCustomer: ptr(50), AcquisitionCost: ptr(200)},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.CAC == nil || !almostEqual(*result.CAC, 100) {
+		t.Fatalf("CAC = %v, want 100", result.CAC)
+	}
+	if result.LTVToCAC == nil || math.Abs(*result.LTVToCAC-3.5878523175950005) > 1e-9 {
+		t.Errorf("LTVToCAC = %v, want 3.5879", result.LTVToCAC)
+	}
+	if result.Interpretation != "healthy" {
+		t.Errorf("Interpretation = %q, want healthy", result.Interpretation)
+	}
+
+	if len(result.Cohorts) != 2 {
+		t.Fatalf("got %d cohorts, want 2", len(result.Cohorts))
+	}
+	early, late := result.Cohorts[0], result.Cohorts[1]
+	if !almostEqual(early.CLV, 600) || early.LTVToCAC == nil || !almostEqual(*early.LTVToCAC, 6) {
+		t.Errorf("early CLV/LTVToCAC = %v/%v, want 600/6", early.CLV, early.LTVToCAC)
+	}
+	if math.Abs(late.CLV-179.39261587975) > 1e-6 || late.LTVToCAC == nil || math.Abs(*late.LTVToCAC-0.89696307939875) > 1e-9 {
+		t.Errorf("late CLV/LTVToCAC = %v/%v, want 179.39/0.897", late.CLV, late.LTVToCAC)
+	}
+	if result.BlendedCLV == nil || math.Abs(*result.BlendedCLV-284.5444619099) > 1e-6 {
+		t.Errorf("BlendedCLV = %v, want 284.544", result.BlendedCLV)
+	}
+}
+
+func TestCLVAdvancedErrors(t *testing.T) {
+	calc := New()
+
+	base := func(m domain.CLVModel) *domain.AppraisalInput {
+		return &domain.AppraisalInput{Financials: &domain.FinancialData{
+			RevenuePerCustomer: ptr(100), GrossMarginPct: ptr(0.5), CLVModel: &m,
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		input   *domain.AppraisalInput
+		wantErr string
+	}{
+		{"no model", &domain.AppraisalInput{Financials: &domain.FinancialData{}}, "clv_model required"},
+		{
+			"no revenue",
+			&domain.AppraisalInput{Financials: &domain.FinancialData{CLVModel: &domain.CLVModel{}}},
+			"revenue_per_customer required",
+		},
+		{"no retention", base(domain.CLVModel{}), "monthly_churn, retention_curve"},
+		{"bad churn", base(domain.CLVModel{MonthlyChurn: ptr(-0.1)}), "between 0 and 1"},
+		{"increasing curve", base(domain.CLVModel{RetentionCurve: []float64{0.5, 0.7}}), "non-increasing"},
+		{
+			"bad cohort curve",
+			base(domain.CLVModel{MonthlyChurn: ptr(0.1), Cohorts: []domain.CLVCohort{{Name: "x", RetentionCurve: []float64{1.2}}}}),
+			"cohort \"x\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.CLVAdvanced(tt.input)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,20 +4,35 @@
 //   UnitEconomics          - Revenue, cost, margin per customer
 //   GrossMarginPerCustomer - (Revenue - COGS) / customer count
 //   CLV                    - Customer Lifetime Value = RPC * margin% * lifespan
+//   CLVAdvanced            - Discounted CLV from churn/retention curve, by cohort, LTV:CAC
 //   CACPayback             - Months to recover customer acquisition cost
 //   BreakEven              - Units needed: fixed costs / contribution margin
+//   BreakEvenSchedule      - Every break-even crossing with step fixed costs and tiered unit costs; margin curve
 //   CannibalizationNet     - Net revenue after migration losses
+//   CannibalizationTimeline - Monthly migration, acquisition and churn offset vs no-bundle baseline
+//   ChurnValue             - Retained customers, revenue and CLV from a churn reduction; discount payback
+//   TierEconomics          - Per-tier margin, share-weighted blended ARPU/margin, tier mix shifts
+//   LandedCost             - Physical goods: BOM + freight + duty per unit, inventory days/value
+//   ChannelMargin          - Retail-to-net price waterfall, returns, warranty, cash conversion cycle
+//   PriceWaterfall         - List-to-pocket price and pocket margin per sales channel, volume-blended
 //   StressTest             - Margin under costs+20%, growth-30%
+//   MonteCarlo             - Seeded simulation over input distributions: percentiles, P(margin < 0)
 //   IncrementalRevenue     - Bundle revenue - lost standalone revenue per customer
-//   RevenueUplift          - (premium RPC - base RPC) / base RPC
+//   RevenueUplift          - (premium RPC - base RPC) / base RPC; Welch t-test and bootstrap CI from revenue samples
+//   Forecast               - Holt-Winters trend + seasonality forecast of a monthly series with prediction intervals
+//   Projection             - Monthly P&L/cash flow, NPV, IRR, break-even and discounted payback
 package financial
 
 import (
 	"fmt"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/stats"
 )
 
+// bootstrapResamples is the number of resamples behind a bootstrap CI.
+const bootstrapResamples = 2000
+
 // Calculator implements all financial module functions.
 type Calculator struct{}
 
@@ -336,12 +351,33 @@ func (c *Calculator) IncrementalRevenue(input *domain.AppraisalInput) (*domain.S
 }
 
 // RevenueUplift calculates (premium RPC - base RPC) / base RPC.
+// Per-customer revenue samples for both tiers (premium_revenue_samples,
+// base_revenue_samples) take the place of premium_revenue and base_revenue:
+// the uplift comes from the sample means, with Welch's t-test of the two
+// means and a 95% bootstrap CI for the uplift.
 func (c *Calculator) RevenueUplift(input *domain.AppraisalInput) (*domain.SingleValueResult, error) {
 	if input.Financials == nil {
 		return nil, fmt.Errorf("financial data required")
 	}
 	f := input.Financials
 
+	if len(f.PremiumRevenueSamples) > 0 || len(f.BaseRevenueSamples) > 0 {
+		// One source for the value and its interval.
+		if f.PremiumRevenue != nil || f.BaseRevenue != nil {
+			return nil, fmt.Errorf("give premium_revenue/base_revenue or the revenue samples, not both")
+		}
+		sig, err := upliftSignificance(f.PremiumRevenueSamples, f.BaseRevenueSamples)
+		if err != nil {
+			return nil, err
+		}
+		uplift := stats.Mean(f.PremiumRevenueSamples)/stats.Mean(f.BaseRevenueSamples) - 1
+		return &domain.SingleValueResult{
+			Value:          uplift,
+			Interpretation: fmt.Sprintf("revenue_uplift=%.1f%%", uplift*100),
+			Significance:   sig,
+		}, nil
+	}
+
 	if f.PremiumRevenue == nil || f.BaseRevenue == nil {
 		return nil, fmt.Errorf("premium_revenue and base_revenue required")
 	}
@@ -363,3 +399,45 @@ func (c *Calculator) RevenueUplift(input *domain.AppraisalInput) (*domain.Single
 		Interpretation: fmt.Sprintf("revenue_uplift=%.1f%%", uplift*100),
 	}, nil
 }
+
+// upliftSignificance runs Welch's t-test on per-customer revenue and
+// bootstraps the uplift of the means. Flat pricing leaves both samples
+// without variance; the test is then degenerate, with p = 0 when the means
+// differ and 1 when they are equal.
+func upliftSignificance(premium, base []float64) (*domain.Significance, error) {
+	if len(premium) < 2 || len(base) < 2 {
+		return nil, fmt.Errorf("revenue samples: each sample needs at least two values")
+	}
+	if stats.Mean(base) <= 0 {
+		return nil, fmt.Errorf("revenue samples: base mean must be positive")
+	}
+	var t float64
+	var df *float64
+	p := 1.0
+	if stats.StdDev(premium) == 0 && stats.StdDev(base) == 0 {
+		if stats.Mean(premium) != stats.Mean(base) {
+			p = 0
+		}
+	} else {
+		var welchDF float64
+		var err error
+		if t, welchDF, p, err = stats.WelchTTest(premium, base); err != nil {
+			return nil, fmt.Errorf("revenue samples: %w", err)
+		}
+		df = &welchDF
+	}
+	uplift := func(s [][]float64) float64 { return stats.Mean(s[0])/stats.Mean(s[1]) - 1 }
+	lower, upper := stats.BootstrapCI([][]float64{premium, base}, uplift, bootstrapResamples, stats.DefaultConfidence, 1)
+	significant := p < 1-stats.DefaultConfidence
+	return &domain.Significance{
+		Test:        "welch_t",
+		Statistic:   t,
+		DF:          df,
+		PValue:      &p,
+		Significant: &significant,
+		Confidence:  stats.DefaultConfidence,
+		CILower:     lower,
+		CIUpper:     upper,
+		CIMethod:    "bootstrap",
+	}, nil
+}
@@ -2,6 +2,7 @@ package financial
 
 import (
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
@@ -1314,3 +1315,59 @@ func TestRevenueUplift(t *testing.T) {
 		})
 	}
 }
+
+func TestRevenueUpliftSignificance(t *testing.T) {
+	calc := New()
+	premium := []float64{60, 65, 70, 75, 80}
+	base := []float64{50, 52, 54, 56, 58}
+
+	// Uplift from the sample means when totals are not given.
+	result, err := calc.RevenueUplift(&domain.AppraisalInput{Financials: &domain.FinancialData{
+		PremiumRevenueSamples: premium, BaseRevenueSamples: base,
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(result.Value, 16.0/54) {
+		t.Errorf("Value = %v, want %v", result.Value, 16.0/54)
+	}
+	sig := result.Significance
+	if sig == nil || sig.Test != "welch_t" || sig.CIMethod != "bootstrap" || sig.DF == nil || sig.PValue == nil {
+		t.Fatalf("Significance = %+v", sig)
+	}
+	if !almostEqual(sig.Statistic, 16/math.Sqrt(14.5)) || sig.Significant == nil || !*sig.Significant {
+		t.Errorf("t = %v (p %v), want %v", sig.Statistic, *sig.PValue, 16/math.Sqrt(14.5))
+	}
+	if !(sig.CILower < result.Value && result.Value < sig.CIUpper) || sig.CILower <= 0 {
+		t.Errorf("CI = [%v, %v]", sig.CILower, sig.CIUpper)
+	}
+
+	// Totals and samples would give the value and its interval different sources.
+	if _, err := calc.RevenueUplift(&domain.AppraisalInput{Financials: &domain.FinancialData{
+		PremiumRevenue: ptr(150000), BaseRevenue: ptr(100000), AverageCustomerCount: ptr(1000),
+		PremiumRevenueSamples: premium, BaseRevenueSamples: base,
+	}}); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("expected error mixing totals and samples, got %v", err)
+	}
+
+	// Flat pricing: no variance, so the test is degenerate but the uplift stands.
+	for _, flat := range []struct {
+		premium float64
+		wantP   float64
+	}{{20, 0}, {10, 1}} {
+		result, err := calc.RevenueUplift(&domain.AppraisalInput{Financials: &domain.FinancialData{
+			PremiumRevenueSamples: []float64{flat.premium, flat.premium, flat.premium}, BaseRevenueSamples: []float64{10, 10, 10},
+		}})
+		if err != nil {
+			t.Fatalf("flat %v: unexpected error: %v", flat.premium, err)
+		}
+		sig := result.Significance
+		if !almostEqual(result.Value, flat.premium/10-1) || sig.PValue == nil || *sig.PValue != flat.wantP || sig.DF != nil {
+			t.Errorf("flat %v = %v, %+v, want p %v", flat.premium, result.Value, sig, flat.wantP)
+		}
+	}
+
+	if _, err := calc.RevenueUplift(&domain.AppraisalInput{Financials: &domain.FinancialData{PremiumRevenueSamples: premium}}); err == nil {
+		t.Error("expected error with premium samples only")
+	}
+}
@@ -0,0 +1,253 @@
+package financial
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/stats"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
+)
+
+// The projection covers one to three years: a shorter horizon cannot show a
+// year-over-year growth, and intervals beyond three years of monthly data
+// say little.
+const (
+	minForecastHorizon = 12
+	maxForecastHorizon = 36
+)
+
+// holtWinters holds smoothing parameters and the running state of an
+// additive Holt-Winters model. With season 0 it is Holt's linear trend.
+type holtWinters struct {
+	alpha, beta, gamma float64
+	season             int
+	level, trend       float64
+	seasonal           []float64
+}
+
+// Forecast extends a monthly revenue or customer series (financials.forecast)
+// with additive Holt-Winters: level, trend and a seasonal pattern of
+// season_length months (12 by default). Series shorter than two seasons fall
+// back to Holt's linear trend unless season_length is set explicitly.
+//
+// Unset smoothing parameters are fitted on a 0.05 grid by minimizing the
+// in-sample one-step-ahead squared error. Prediction intervals use the
+// additive error model:
+//
+//	var(h) = sigma^2 * (1 + sum over j=1..h-1 of (alpha*(1 + j*beta) + gamma*[j mod m = 0])^2)
+//
+// Lower bounds are floored at 0 when the history has no negative values.
+func (c *Calculator) Forecast(input *domain.AppraisalInput) (*domain.ForecastResult, error) {
+	if input.Financials == nil || input.Financials.Forecast == nil {
+		return nil, fmt.Errorf("financials.forecast required")
+	}
+	fd := input.Financials.Forecast
+	y := fd.Series
+	n := len(y)
+	if n < 4 {
+		return nil, fmt.Errorf("forecast series needs at least 4 observations")
+	}
+
+	horizon := 12
+	if fd.Horizon != nil {
+		horizon = *fd.Horizon
+	}
+	if horizon < minForecastHorizon || horizon > maxForecastHorizon {
+		return nil, fmt.Errorf("horizon must be between %d and %d months", minForecastHorizon, maxForecastHorizon)
+	}
+	confidence := valuation.ValueOr(fd.Confidence, 0.95)
+	if confidence <= 0 || confidence >= 1 {
+		return nil, fmt.Errorf("confidence must be between 0 and 1")
+	}
+	season := 12
+	if fd.SeasonLength != nil {
+		season = *fd.SeasonLength
+		if season < 0 {
+			return nil, fmt.Errorf("season_length must be non-negative")
+		}
+		if season > 1 && n < 2*season {
+			return nil, fmt.Errorf("seasonal forecast needs at least %d observations (two seasons)", 2*season)
+		}
+	}
+	if season <= 1 || n < 2*season {
+		season = 0
+	}
+	for _, p := range []*float64{fd.Alpha, fd.Beta, fd.Gamma} {
+		if p != nil && (*p < 0 || *p > 1) {
+			return nil, fmt.Errorf("alpha, beta and gamma must be between 0 and 1")
+		}
+	}
+
+	model, sse := fitHoltWinters(y, season, fd.Alpha, fd.Beta, fd.Gamma)
+
+	result := &domain.ForecastResult{
+		Model:        "holt_linear",
+		Metric:       fd.Metric,
+		Observations: n,
+		Alpha:        model.alpha,
+		Beta:         model.beta,
+		Gamma:        model.gamma,
+		Level:        model.level,
+		Trend:        model.trend,
+		RMSE:         math.Sqrt(sse / float64(n)),
+		Confidence:   confidence,
+	}
+	params := 2
+	if season > 0 {
+		result.Model = "holt_winters_additive"
+		result.SeasonLength = season
+		params = 3
+		for i := 0; i < season; i++ {
+			result.Seasonal = append(result.Seasonal, model.seasonal[(n+i)%season])
+		}
+	}
+
+	nonNegative, positive := true, true
+	ape := 0.0
+	fit := model.start(y)
+	for t, v := range y {
+		if v < 0 {
+			nonNegative = false
+		}
+		if v <= 0 {
+			positive = false
+		} else {
+			ape += math.Abs(v-fit.predict(t, 1)) / v
+		}
+		fit.update(t, v)
+	}
+	if positive {
+		mape := ape / float64(n)
+		result.MAPE = &mape
+	}
+
+	sigma := math.Sqrt(sse / float64(max(n-params, 1)))
+	z := stats.NormalQuantile(0.5 + confidence/2)
+	cumulative := 1.0
+	for h := 1; h <= horizon; h++ {
+		if h > 1 {
+			j := float64(h - 1)
+			cj := model.alpha * (1 + j*model.beta)
+			if season > 0 && (h-1)%season == 0 {
+				cj += model.gamma
+			}
+			cumulative += cj * cj
+		}
+		value := model.predict(n, h)
+		half := z * sigma * math.Sqrt(cumulative)
+		point := domain.ForecastPoint{Month: n + h, Value: value, Lower: value - half, Upper: value + half}
+		if nonNegative && point.Lower < 0 {
+			point.Lower = 0
+		}
+		result.Forecast = append(result.Forecast, point)
+		result.TotalForecast += value
+	}
+
+	if n >= 12 {
+		last, next := 0.0, 0.0
+		for i := 0; i < 12; i++ {
+			last += y[n-12+i]
+			next += result.Forecast[i].Value
+		}
+		if last > 0 {
+			growth := next/last - 1
+			result.AnnualGrowth = &growth
+		}
+	}
+
+	return result, nil
+}
+
+// fitHoltWinters runs the model over y for every grid combination of the
+// parameters not fixed by the caller and returns the final state of the
+// combination with the lowest one-step-ahead SSE.
+func fitHoltWinters(y []float64, season int, alpha, beta, gamma *float64) (*holtWinters, float64) {
+	grid := func(fixed *float64, from int, upper float64) []float64 {
+		if fixed != nil {
+			return []float64{*fixed}
+		}
+		var values []float64
+		for i := from; float64(i)/20 <= upper+1e-9; i++ {
+			values = append(values, float64(i)/20)
+		}
+		return values
+	}
+
+	var best *holtWinters
+	bestSSE := math.Inf(1)
+	for _, a := range grid(alpha, 1, 1) {
+		for _, b := range grid(beta, 0, 1) {
+			gammas := []float64{0}
+			if season > 0 {
+				// gamma above 1 - alpha makes the seasonal update unstable.
+				gammas = grid(gamma, 0, 1-a)
+				if gamma == nil && len(gammas) == 0 {
+					gammas = []float64{0}
+				}
+			}
+			for _, g := range gammas {
+				m := &holtWinters{alpha: a, beta: b, gamma: g, season: season}
+				m = m.start(y)
+				sse := 0.0
+				for t, v := range y {
+					e := v - m.predict(t, 1)
+					sse += e * e
+					m.update(t, v)
+				}
+				if sse < bestSSE {
+					best, bestSSE = m, sse
+				}
+			}
+		}
+	}
+	return best, bestSSE
+}
+
+// start returns a copy of the model with its state initialized from the
+// first one (Holt) or two (Holt-Winters) seasons of y, positioned just
+// before the first observation.
+func (m *holtWinters) start(y []float64) *holtWinters {
+	s := &holtWinters{alpha: m.alpha, beta: m.beta, gamma: m.gamma, season: m.season}
+	if s.season == 0 {
+		s.trend = y[1] - y[0]
+		s.level = y[0] - s.trend
+		return s
+	}
+
+	first := stats.Mean(y[:s.season])
+	second := stats.Mean(y[s.season : 2*s.season])
+	s.trend = (second - first) / float64(s.season)
+	// first is the level at the middle of the first season.
+	mid := float64(s.season-1) / 2
+	s.level = first - s.trend*(mid+1)
+	s.seasonal = make([]float64, s.season)
+	for i := range s.seasonal {
+		s.seasonal[i] = y[i] - (first + s.trend*(float64(i)-mid))
+	}
+	return s
+}
+
+// predict forecasts observation t+h-1 from the state after observation t-1
+// (with h = 1, the one-step-ahead forecast of observation t).
+func (m *holtWinters) predict(t, h int) float64 {
+	value := m.level + float64(h)*m.trend
+	if m.season > 0 {
+		value += m.seasonal[(t+h-1)%m.season]
+	}
+	return value
+}
+
+// update folds observation t into the state.
+func (m *holtWinters) update(t int, v float64) {
+	seasonal := 0.0
+	if m.season > 0 {
+		seasonal = m.seasonal[t%m.season]
+	}
+	level := m.alpha*(v-seasonal) + (1-m.alpha)*(m.level+m.trend)
+	if m.season > 0 {
+		m.seasonal[t%m.season] = m.gamma*(v-m.level-m.trend) + (1-m.gamma)*seasonal
+	}
+	m.trend = m.beta*(level-m.level) + (1-m.beta)*m.trend
+	m.level = level
+}
@@ -0,0 +1,201 @@
+package financial
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// Forecast
+// ---------------------------------------------------------------------------
+
+func TestForecast(t *testing.T) {
+	calc := New()
+
+	// month m (1-based) of a series; forecasts continue the same function
+	linear := func(m int) float64 { return 100 + 10*float64(m-1) }
+	pattern := []float64{10, -10, 5, -5, 0, 0, 20, -20, 3, -3, 8, -8}
+	seasonal := func(m int) float64 { return 100 + 2*float64(m-1) + pattern[(m-1)%12] }
+	series := func(n int, f func(int) float64) []float64 {
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = f(i + 1)
+		}
+		return s
+	}
+
+	// Noisy trend too short for a seasonal fit.
+	noise := []float64{3, -4, 1, 6, -2, -5, 4, 0, -3, 5}
+	noisy := make([]float64, len(noise))
+	for i := range noisy {
+		noisy[i] = 50 + 5*float64(i) + noise[i]
+	}
+
+	tests := []struct {
+		name         string
+		forecast     *domain.ForecastData
+		wantErr      bool
+		errContains  string
+		wantModel    string
+		wantSeason   int
+		wantPoints   int
+		wantExact    func(int) float64 // nil for a noisy fit
+		wantTrend    *float64
+		wantGrowth   *float64
+		wantSeasonal []float64
+	}{
+		{
+			// next 12 months sum 4740 vs last 12 months 3300
+			name:       "linear_trend_non_seasonal",
+			forecast:   &domain.ForecastData{Series: series(24, linear), SeasonLength: intPtr(0)},
+			wantModel:  "holt_linear",
+			wantPoints: 12,
+			wantExact:  linear,
+			wantTrend:  ptr(10),
+			wantGrowth: ptr(4740.0/3300 - 1),
+		},
+		{
+			name:         "linear_trend_seasonal",
+			forecast:     &domain.ForecastData{Series: series(24, linear), SeasonLength: intPtr(12)},
+			wantModel:    "holt_winters_additive",
+			wantSeason:   12,
+			wantPoints:   12,
+			wantExact:    linear,
+			wantTrend:    ptr(10),
+			wantGrowth:   ptr(4740.0/3300 - 1),
+			wantSeasonal: make([]float64, 12),
+		},
+		{
+			name:         "seasonal_pattern",
+			forecast:     &domain.ForecastData{Series: series(36, seasonal), Horizon: intPtr(24)},
+			wantModel:    "holt_winters_additive",
+			wantSeason:   12,
+			wantPoints:   24,
+			wantExact:    seasonal,
+			wantTrend:    ptr(2),
+			wantGrowth:   ptr(2196.0/1908 - 1), // the pattern sums to 0 over a year
+			wantSeasonal: pattern,
+		},
+		{
+			name:       "noisy_short_series",
+			forecast:   &domain.ForecastData{Series: noisy, Confidence: ptr(0.8)},
+			wantModel:  "holt_linear",
+			wantPoints: 12,
+		},
+		{
+			name:        "no_forecast",
+			wantErr:     true,
+			errContains: "financials.forecast required",
+		},
+		{
+			name:        "short_series",
+			forecast:    &domain.ForecastData{Series: []float64{1, 2, 3}},
+			wantErr:     true,
+			errContains: "at least 4",
+		},
+		{
+			name:        "short_horizon",
+			forecast:    &domain.ForecastData{Series: series(20, linear), Horizon: intPtr(6)},
+			wantErr:     true,
+			errContains: "between 12 and 36",
+		},
+		{
+			name:        "long_horizon",
+			forecast:    &domain.ForecastData{Series: series(20, linear), Horizon: intPtr(48)},
+			wantErr:     true,
+			errContains: "horizon",
+		},
+		{
+			name:        "explicit_season_too_long",
+			forecast:    &domain.ForecastData{Series: series(20, linear), SeasonLength: intPtr(12)},
+			wantErr:     true,
+			errContains: "two seasons",
+		},
+		{
+			name:        "negative_season",
+			forecast:    &domain.ForecastData{Series: series(20, linear), SeasonLength: intPtr(-1)},
+			wantErr:     true,
+			errContains: "season_length",
+		},
+		{
+			name:        "bad_confidence",
+			forecast:    &domain.ForecastData{Series: series(20, linear), Confidence: ptr(1)},
+			wantErr:     true,
+			errContains: "confidence",
+		},
+		{
+			name:        "bad_alpha",
+			forecast:    &domain.ForecastData{Series: series(20, linear), Alpha: ptr(1.5)},
+			wantErr:     true,
+			errContains: "between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.Forecast(&domain.AppraisalInput{Financials: &domain.FinancialData{Forecast: tt.forecast}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Model != tt.wantModel || result.SeasonLength != tt.wantSeason {
+				t.Errorf("model = %s/%d, want %s/%d", result.Model, result.SeasonLength, tt.wantModel, tt.wantSeason)
+			}
+			if len(result.Forecast) != tt.wantPoints {
+				t.Fatalf("got %d forecast points, want %d", len(result.Forecast), tt.wantPoints)
+			}
+
+			observed := len(tt.forecast.Series)
+			width, total := 0.0, 0.0
+			for h, p := range result.Forecast {
+				if p.Month != observed+h+1 {
+					t.Errorf("point %d month = %d, want %d", h, p.Month, observed+h+1)
+				}
+				if tt.wantExact != nil && (!almostEqual(p.Value, tt.wantExact(p.Month)) || !almostEqual(p.Lower, p.Upper)) {
+					t.Errorf("month %d = %+v, want exactly %v", p.Month, p, tt.wantExact(p.Month))
+				}
+				if p.Lower > p.Value || p.Upper < p.Value || p.Upper-p.Lower < width-1e-9 {
+					t.Fatalf("interval not widening around the forecast: %+v", p)
+				}
+				width = p.Upper - p.Lower
+				total += p.Value
+			}
+			if math.Abs(result.TotalForecast-total) > 1e-9 {
+				t.Errorf("TotalForecast = %v, want %v", result.TotalForecast, total)
+			}
+
+			if tt.wantExact != nil {
+				if result.RMSE > 1e-9 || result.MAPE == nil || *result.MAPE > 1e-9 {
+					t.Errorf("rmse/mape = %v/%v, want 0/0", result.RMSE, result.MAPE)
+				}
+			} else if result.RMSE <= 0 || result.MAPE == nil {
+				t.Errorf("rmse/mape = %v/%v, want a positive fit error", result.RMSE, result.MAPE)
+			}
+			if tt.wantTrend != nil && !almostEqual(result.Trend, *tt.wantTrend) {
+				t.Errorf("Trend = %v, want %v", result.Trend, *tt.wantTrend)
+			}
+			if (result.AnnualGrowth == nil) != (tt.wantGrowth == nil) || result.AnnualGrowth != nil && !almostEqual(*result.AnnualGrowth, *tt.wantGrowth) {
+				t.Errorf("AnnualGrowth = %v, want %v", result.AnnualGrowth, tt.wantGrowth)
+			}
+			if len(result.Seasonal) != len(tt.wantSeasonal) {
+				t.Fatalf("got %d seasonal terms, want %d", len(result.Seasonal), len(tt.wantSeasonal))
+			}
+			for i, s := range result.Seasonal {
+				if !almostEqual(s, tt.wantSeasonal[i]) {
+					t.Errorf("seasonal[%d] = %v, want %v", i, s, tt.wantSeasonal[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package financial
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/fieldpath"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/stats"
+)
+
+const (
+	defaultMonteCarloIterations = 10000
+	maxMonteCarloIterations     = 1000000
+)
+
+// churnFields are the customers churn rates that, when sampled, set the CLV
+// lifespan to 1 / churn.
+var churnFields = []string{"customers.churn_after", "customers.premium_churn_rate", "customers.base_churn_rate"}
+
+// MonteCarlo runs a seeded Monte Carlo stress test. Each iteration samples the
+// configured financials and customers fields (correlated through a Gaussian
+// copula), then runs UnitEconomics, CLV, CACPayback, and BreakEven on the
+// sampled input. A sampled churn rate (at most one of churnFields) replaces
+// average_lifespan_months with 1 / churn for CLV. Reports percentiles per
+// metric and P(margin < 0).
+func (c *Calculator) MonteCarlo(input *domain.AppraisalInput) (*domain.MonteCarloResult, error) {
+	if input.Financials == nil || input.Financials.MonteCarlo == nil {
+		return nil, fmt.Errorf("financials.monte_carlo required")
+	}
+	mc := input.Financials.MonteCarlo
+
+	iterations := defaultMonteCarloIterations
+	if mc.Iterations != nil {
+		iterations = *mc.Iterations
+	}
+	if iterations <= 0 || iterations > maxMonteCarloIterations {
+		return nil, fmt.Errorf("iterations must be between 1 and %d", maxMonteCarloIterations)
+	}
+	seed := uint64(1)
+	if mc.Seed != nil {
+		seed = *mc.Seed
+	}
+
+	samplers, index, err := buildSamplers(mc.Distributions)
+	if err != nil {
+		return nil, err
+	}
+	churn := -1 // sampler index of the churn rate, if any
+	for _, field := range churnFields {
+		if j, ok := index[field]; ok {
+			if churn >= 0 {
+				return nil, fmt.Errorf("sample at most one customers churn rate")
+			}
+			churn = j
+		}
+	}
+	if _, ok := index["financials.average_lifespan_months"]; ok && churn >= 0 {
+		return nil, fmt.Errorf("sample either financials.average_lifespan_months or a customers churn rate, not both")
+	}
+	chol, err := correlationCholesky(mc.Correlations, index)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deep-copy the base input once (without the simulation config). Every
+	// iteration overwrites all sampled fields, so the copy is reused.
+	base := *input
+	fin := *input.Financials
+	fin.MonteCarlo = nil
+	base.Financials = &fin
+	baseJSON, err := json.Marshal(&base)
+	if err != nil {
+		return nil, fmt.Errorf("encoding base input: %w", err)
+	}
+	var sim domain.AppraisalInput
+	if err := json.Unmarshal(baseJSON, &sim); err != nil {
+		return nil, fmt.Errorf("decoding base input: %w", err)
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed))
+	n := len(samplers)
+	z := make([]float64, n)
+	correlated := make([]float64, n)
+
+	var margins, marginPcts, clvs, paybacks, breakEvens []float64
+	failures := map[string]int{}
+
+	for i := 0; i < iterations; i++ {
+		for j := range z {
+			z[j] = rng.NormFloat64()
+		}
+		for j := 0; j < n; j++ {
+			correlated[j] = 0
+			for k := 0; k <= j; k++ {
+				correlated[j] += chol[j][k] * z[k]
+			}
+		}
+
+		for j, s := range samplers {
+			if err := fieldpath.Set(&sim, s.field, s.sample(correlated[j])); err != nil {
+				return nil, err
+			}
+		}
+		if churn >= 0 {
+			// A non-positive churn leaves no lifespan, so CLV fails this
+			// iteration rather than falling back to the base lifespan.
+			sim.Financials.AverageLifespanMonths = nil
+			if rate := samplers[churn].sample(correlated[churn]); rate > 0 {
+				lifespan := 1 / rate
+				sim.Financials.AverageLifespanMonths = &lifespan
+			}
+		}
+
+		if r, err := c.UnitEconomics(&sim); err == nil {
+			margins = append(margins, r.MarginPerCustomer)
+			marginPcts = append(marginPcts, r.MarginPct)
+		} else {
+			failures["margin"]++
+		}
+		if r, err := c.CLV(&sim); err == nil {
+			clvs = append(clvs, r.CLV)
+		} else {
+			failures["clv"]++
+		}
+		if r, err := c.CACPayback(&sim); err == nil {
+			paybacks = append(paybacks, r.Value)
+		} else {
+			failures["cac_payback"]++
+		}
+		if r, err := c.BreakEven(&sim); err == nil {
+			breakEvens = append(breakEvens, r.BreakEvenUnits)
+		} else {
+			failures["break_even_units"]++
+		}
+	}
+
+	result := &domain.MonteCarloResult{
+		Iterations:     iterations,
+		Seed:           seed,
+		Margin:         summarize(margins),
+		MarginPct:      summarize(marginPcts),
+		CLV:            summarize(clvs),
+		CACPayback:     summarize(paybacks),
+		BreakEvenUnits: summarize(breakEvens),
+	}
+	if result.Margin == nil && result.CLV == nil && result.CACPayback == nil && result.BreakEvenUnits == nil {
+		return nil, fmt.Errorf("no financial metric could be computed from the input")
+	}
+	if len(margins) > 0 {
+		negative := 0
+		for _, m := range margins {
+			if m < 0 {
+				negative++
+			}
+		}
+		p := float64(negative) / float64(len(margins))
+		result.ProbabilityNegativeMargin = &p
+	}
+
+	// Report partial failures only; metrics that never ran are simply omitted.
+	for metric, count := range failures {
+		if count < iterations {
+			if result.Errors == nil {
+				result.Errors = map[string]int{}
+			}
+			result.Errors[metric] = count
+		}
+	}
+
+	return result, nil
+}
+
+// sampler maps a standard normal draw to a value of one input distribution.
+type sampler struct {
+	field  string
+	sample func(z float64) float64
+}
+
+// buildSamplers validates distributions and returns samplers plus a field index.
+func buildSamplers(dists []domain.InputDistribution) ([]sampler, map[string]int, error) {
+	if len(dists) == 0 {
+		return nil, nil, fmt.Errorf("at least one distribution required")
+	}
+
+	samplers := make([]sampler, 0, len(dists))
+	index := make(map[string]int, len(dists))
+	for _, d := range dists {
+		if !strings.HasPrefix(d.Field, "financials.") && !strings.HasPrefix(d.Field, "customers.") {
+			return nil, nil, fmt.Errorf("distribution field %q must be under financials or customers", d.Field)
+		}
+		if _, dup := index[d.Field]; dup {
+			return nil, nil, fmt.Errorf("duplicate distribution for %q", d.Field)
+		}
+		if err := fieldpath.Set(&domain.AppraisalInput{}, d.Field, 0); err != nil {
+			return nil, nil, err
+		}
+
+		fn, err := inverseCDF(d)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", d.Field, err)
+		}
+		index[d.Field] = len(samplers)
+		samplers = append(samplers, sampler{field: d.Field, sample: fn})
+	}
+	return samplers, index, nil
+}
+
+// inverseCDF returns the transform from a standard normal draw to d.
+func inverseCDF(d domain.InputDistribution) (func(float64) float64, error) {
+	clamp := func(v float64) float64 {
+		if d.Min != nil {
+			v = math.Max(v, *d.Min)
+		}
+		if d.Max != nil {
+			v = math.Min(v, *d.Max)
+		}
+		return v
+	}
+
+	switch d.Type {
+	case "normal":
+		if d.Mean == nil || d.StdDev == nil || *d.StdDev < 0 {
+			return nil, fmt.Errorf("normal requires mean and non-negative std_dev")
+		}
+		mean, sd := *d.Mean, *d.StdDev
+		return func(z float64) float64 { return clamp(mean + sd*z) }, nil
+
+	case "lognormal":
+		if d.Mean == nil || d.StdDev == nil || *d.Mean <= 0 || *d.StdDev < 0 {
+			return nil, fmt.Errorf("lognormal requires positive mean and non-negative std_dev")
+		}
+		sigma2 := math.Log(1 + (*d.StdDev**d.StdDev)/(*d.Mean**d.Mean))
+		mu := math.Log(*d.Mean) - sigma2/2
+		sigma := math.Sqrt(sigma2)
+		return func(z float64) float64 { return clamp(math.Exp(mu + sigma*z)) }, nil
+
+	case "uniform":
+		if d.Min == nil || d.Max == nil || *d.Max < *d.Min {
+			return nil, fmt.Errorf("uniform requires min <= max")
+		}
+		lo, hi := *d.Min, *d.Max
+		return func(z float64) float64 { return lo + (hi-lo)*stats.NormalCDF(z) }, nil
+
+	case "triangular":
+		if d.Min == nil || d.Mode == nil || d.Max == nil || *d.Min > *d.Mode || *d.Mode > *d.Max || *d.Min == *d.Max {
+			return nil, fmt.Errorf("triangular requires min <= mode <= max with min < max")
+		}
+		a, m, b := *d.Min, *d.Mode, *d.Max
+		split := (m - a) / (b - a)
+		return func(z float64) float64 {
+			u := stats.NormalCDF(z)
+			if u < split {
+				return a + math.Sqrt(u*(b-a)*(m-a))
+			}
+			return b - math.Sqrt((1-u)*(b-a)*(b-m))
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown distribution type %q (use normal, triangular, uniform, or lognormal)", d.Type)
+	}
+}
+
+// correlationCholesky builds the correlation matrix and returns its Cholesky
+// factor. With no correlations it is the identity.
+func correlationCholesky(corrs []domain.InputCorrelation, index map[string]int) ([][]float64, error) {
+	n := len(index)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = 1
+	}
+	for _, c := range corrs {
+		a, okA := index[c.A]
+		b, okB := index[c.B]
+		if !okA || !okB {
+			return nil, fmt.Errorf("correlation %s~%s: both fields need a distribution", c.A, c.B)
+		}
+		if a == b {
+			return nil, fmt.Errorf("correlation %s~%s: fields must differ", c.A, c.B)
+		}
+		if c.Coefficient <= -1 || c.Coefficient >= 1 {
+			return nil, fmt.Errorf("correlation %s~%s: coefficient must be between -1 and 1 (exclusive)", c.A, c.B)
+		}
+		m[a][b] = c.Coefficient
+		m[b][a] = c.Coefficient
+	}
+	l, err := stats.Cholesky(m)
+	if err != nil {
+		return nil, fmt.Errorf("correlations are inconsistent: %w", err)
+	}
+	return l, nil
+}
+
+// summarize returns percentile statistics, or nil for an empty sample.
+func summarize(values []float64) *domain.DistributionSummary {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return &domain.DistributionSummary{
+		Samples: len(sorted),
+		Mean:    stats.Mean(sorted),
+		StdDev:  stats.StdDev(sorted),
+		Min:     sorted[0],
+		P5:      stats.Percentile(sorted, 0.05),
+		P10:     stats.Percentile(sorted, 0.10),
+		P25:     stats.Percentile(sorted, 0.25),
+		P50:     stats.Percentile(sorted, 0.50),
+		P75:     stats.Percentile(sorted, 0.75),
+		P90:     stats.Percentile(sorted, 0.90),
+		P95:     stats.Percentile(sorted, 0.95),
+		Max:     sorted[len(sorted)-1],
+	}
+}
@@ -0,0 +1,266 @@
+package financial
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func monteCarloInput(mc *domain.MonteCarloConfig) *domain.AppraisalInput {
+	return &domain.AppraisalInput{
+		Financials: &domain.FinancialData{
+			AverageCustomerCount: ptr(100),
+			RevenuePerCustomer:   ptr(100),
+			MonteCarlo:           mc,
+		},
+	}
+}
+
+func TestMonteCarlo(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name       string
+		dists      []domain.InputDistribution
+		corrs      []domain.InputCorrelation
+		wantMean   float64
+		wantStdDev float64
+		wantPNeg   float64
+		tolerance  float64
+	}{
+		{
+			name: "normal cost",
+			dists: []domain.InputDistribution{
+				{Field: "financials.direct_cost_per_customer", Type: "normal", Mean: ptr(80), StdDev: ptr(20)},
+			},
+			wantMean: 20, wantStdDev: 20, wantPNeg: 0.1587, tolerance: 0.5,
+		},
+		{
+			name: "uniform partner cost",
+			dists: []domain.InputDistribution{
+				{Field: "financials.partner_licensing_cost", Type: "uniform", Min: ptr(0), Max: ptr(10)},
+			},
+			wantMean: 95, wantStdDev: 10 / math.Sqrt(12), wantPNeg: 0, tolerance: 0.1,
+		},
+		{
+			name: "triangular shared cost",
+			dists: []domain.InputDistribution{
+				{Field: "financials.shared_cost_per_customer", Type: "triangular", Min: ptr(0), Mode: ptr(30), Max: ptr(120)},
+			},
+			// mean (0+30+120)/3 = 50; var (a²+b²+c²-ab-ac-bc)/18 = 650; P(cost>100) = 20²/(120*90)
+			wantMean: 50, wantStdDev: math.Sqrt(650), wantPNeg: 400.0 / 10800, tolerance: 0.5,
+		},
+		{
+			name: "correlated revenue and cost",
+			dists: []domain.InputDistribution{
+				{Field: "financials.revenue_per_customer", Type: "normal", Mean: ptr(100), StdDev: ptr(10)},
+				{Field: "financials.direct_cost_per_customer", Type: "normal", Mean: ptr(50), StdDev: ptr(10)},
+			},
+			corrs: []domain.InputCorrelation{
+				{A: "financials.revenue_per_customer", B: "financials.direct_cost_per_customer", Coefficient: 0.8},
+			},
+			// var = 100 + 100 - 2*0.8*100 = 40
+			wantMean: 50, wantStdDev: math.Sqrt(40), wantPNeg: 0, tolerance: 0.2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.MonteCarlo(monteCarloInput(&domain.MonteCarloConfig{
+				Iterations:    intPtr(20000),
+				Distributions: tt.dists,
+				Correlations:  tt.corrs,
+			}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Iterations != 20000 || result.Seed != 1 {
+				t.Errorf("iterations/seed = %d/%d, want 20000/1", result.Iterations, result.Seed)
+			}
+			m := result.Margin
+			if m == nil || m.Samples != 20000 {
+				t.Fatalf("Margin = %+v, want 20000 samples", m)
+			}
+			if math.Abs(m.Mean-tt.wantMean) > tt.tolerance {
+				t.Errorf("margin mean = %v, want %v", m.Mean, tt.wantMean)
+			}
+			if math.Abs(m.StdDev-tt.wantStdDev) > tt.tolerance {
+				t.Errorf("margin std dev = %v, want %v", m.StdDev, tt.wantStdDev)
+			}
+			if !(m.Min <= m.P5 && m.P5 <= m.P25 && m.P25 <= m.P50 && m.P50 <= m.P75 && m.P75 <= m.P95 && m.P95 <= m.Max) {
+				t.Errorf("percentiles not ordered: %+v", m)
+			}
+			if result.ProbabilityNegativeMargin == nil || math.Abs(*result.ProbabilityNegativeMargin-tt.wantPNeg) > 0.01 {
+				t.Errorf("P(margin<0) = %v, want %v", result.ProbabilityNegativeMargin, tt.wantPNeg)
+			}
+			if result.CLV != nil || result.BreakEvenUnits != nil || result.Errors != nil {
+				t.Errorf("metrics without inputs should be omitted: clv=%v break_even=%v errors=%v", result.CLV, result.BreakEvenUnits, result.Errors)
+			}
+		})
+	}
+}
+
+func TestMonteCarloSeedAndMetrics(t *testing.T) {
+	calc := New()
+
+	run := func(seed uint64) *domain.MonteCarloResult {
+		input := monteCarloInput(&domain.MonteCarloConfig{
+			Iterations: intPtr(500),
+			Seed:       &seed,
+			Distributions: []domain.InputDistribution{
+				{Field: "financials.gross_margin_pct", Type: "uniform", Min: ptr(0.3), Max: ptr(0.5)},
+				{Field: "financials.variable_cost_per_unit", Type: "lognormal", Mean: ptr(40), StdDev: ptr(10), Max: ptr(90)},
+				{Field: "customers.base_churn_rate", Type: "uniform", Min: ptr(0.02), Max: ptr(0.05)},
+			},
+		})
+		input.Product = &domain.ProductDefinition{Name: "Bundle", Price: 100}
+		input.Financials.AverageLifespanMonths = ptr(24)
+		input.Financials.FixedCosts = ptr(6000)
+		input.Financials.TotalAcquisitionSpend = ptr(5000)
+		input.Financials.NewCustomersAcquired = ptr(50)
+
+		result, err := calc.MonteCarlo(input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	a, b, c := run(7), run(7), run(8)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("same seed produced different results")
+	}
+	if reflect.DeepEqual(a, c) {
+		t.Error("different seeds produced identical results")
+	}
+
+	// Sampled churn replaces the 24-month lifespan with 20 to 50 months.
+	if a.CLV == nil || a.CLV.Min < 100*0.3*20-1e-9 || a.CLV.Max > 100*0.5*50+1e-9 || a.CLV.Max <= 100*0.5*24 {
+		t.Errorf("CLV = %+v, want within [600, 2500] and above 1200 at the top", a.CLV)
+	}
+	if a.CACPayback == nil || a.CACPayback.Min < 2-1e-9 || a.CACPayback.Max > 100.0/30+1e-9 {
+		t.Errorf("CACPayback = %+v, want within [2, 3.33]", a.CACPayback)
+	}
+	// Contribution margin >= 10 (cost capped at 90) -> break-even <= 600 units
+	if a.BreakEvenUnits == nil || a.BreakEvenUnits.Max > 600+1e-9 || a.BreakEvenUnits.Samples != 500 {
+		t.Errorf("BreakEvenUnits = %+v", a.BreakEvenUnits)
+	}
+}
+
+func TestMonteCarloErrors(t *testing.T) {
+	calc := New()
+	normal := func(field string) domain.InputDistribution {
+		return domain.InputDistribution{Field: field, Type: "normal", Mean: ptr(1), StdDev: ptr(0.1)}
+	}
+
+	tests := []struct {
+		name    string
+		mc      *domain.MonteCarloConfig
+		wantErr string
+	}{
+		{"no config", nil, "financials.monte_carlo required"},
+		{"no distributions", &domain.MonteCarloConfig{}, "at least one distribution"},
+		{
+			"outside financials",
+			&domain.MonteCarloConfig{Distributions: []domain.InputDistribution{normal("product.price")}},
+			"must be under financials or customers",
+		},
+		{
+			"unknown customers field",
+			&domain.MonteCarloConfig{Distributions: []domain.InputDistribution{normal("customers.nope")}},
+			"unknown field",
+		},
+		{
+			"two churn rates",
+			&domain.MonteCarloConfig{Distributions: []domain.InputDistribution{
+				normal("customers.base_churn_rate"), normal("customers.churn_after"),
+			}},
+			"at most one customers churn rate",
+		},
+		{
+			"churn and lifespan",
+			&domain.MonteCarloConfig{Distributions: []domain.InputDistribution{
+				normal("customers.base_churn_rate"), normal("financials.average_lifespan_months"),
+			}},
+			"not both",
+		},
+		{
+			"unknown field",
+			&domain.MonteCarloConfig{Distributions: []domain.InputDistribution{normal("financials.nope")}},
+			"unknown field",
+		},
+		{
+			"unknown type",
+			&domain.MonteCarloConfig{Distributions: []domain.InputDistribution{{Field: "financials.cogs", Type: "beta"}}},
+			"unknown distribution type",
+		},
+		{
+			"triangular out of order",
+			&domain.MonteCarloConfig{Distributions: []domain.InputDistribution{
+				{Field: "financials.cogs", Type: "triangular", Min: ptr(5), Mode: ptr(1), Max: ptr(10)},
+			}},
+			"min <= mode <= max",
+		},
+		{
+			"correlation without distribution",
+			&domain.MonteCarloConfig{
+				Distributions: []domain.InputDistribution{normal("financials.cogs")},
+				Correlations:  []domain.InputCorrelation{{A: "financials.cogs", B: "financials.fixed_costs", Coefficient: 0.5}},
+			},
+			"both fields need a distribution",
+		},
+		{
+			"inconsistent correlations",
+			&domain.MonteCarloConfig{
+				Distributions: []domain.InputDistribution{
+					normal("financials.cogs"), normal("financials.fixed_costs"), normal("financials.shared_cost_per_customer"),
+				},
+				Correlations: []domain.InputCorrelation{
+					{A: "financials.cogs", B: "financials.fixed_costs", Coefficient: 0.9},
+					{A: "financials.cogs", B: "financials.shared_cost_per_customer", Coefficient: 0.9},
+					{A: "financials.fixed_costs", B: "financials.shared_cost_per_customer", Coefficient: -0.9},
+				},
+			},
+			"inconsistent",
+		},
+		{
+			"zero iterations",
+			&domain.MonteCarloConfig{Iterations: intPtr(0), Distributions: []domain.InputDistribution{normal("financials.cogs")}},
+			"iterations must be between",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.MonteCarlo(monteCarloInput(tt.mc))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+
+	// Any numeric customers field can be sampled, without touching the input.
+	input := monteCarloInput(&domain.MonteCarloConfig{Iterations: intPtr(100), Distributions: []domain.InputDistribution{
+		normal("customers.churn_before"), normal("customers.total_customers"),
+	}})
+	if _, err := calc.MonteCarlo(input); err != nil {
+		t.Errorf("customers fields: unexpected error: %v", err)
+	}
+	if input.Customers != nil {
+		t.Errorf("MonteCarlo modified the input: customers = %+v", input.Customers)
+	}
+
+	// No metric computable at all
+	_, err := calc.MonteCarlo(&domain.AppraisalInput{Financials: &domain.FinancialData{
+		MonteCarlo: &domain.MonteCarloConfig{Distributions: []domain.InputDistribution{normal("financials.cogs")}},
+	}})
+	if err == nil || !strings.Contains(err.Error(), "no financial metric") {
+		t.Errorf("error = %v, want no financial metric", err)
+	}
+}
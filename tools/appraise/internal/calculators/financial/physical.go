@@ -0,0 +1,118 @@
+package financial
+
+import (
+	"fmt"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
+)
+
+// LandedCost builds the per-unit landed cost of a physical product from
+// financials.physical_goods (see valuation.LandedCost):
+//
+//	ex-works = BOM (or unit_cost) + assembly + packaging
+//	duty     = duty_rate * (ex-works + freight)
+//	landed   = ex-works + freight + duty + other_landed_cost
+//
+// With inventory_turns it also reports days inventory outstanding, and with
+// annual_units the annual landed cost and average inventory value.
+func (c *Calculator) LandedCost(input *domain.AppraisalInput) (*domain.LandedCostResult, error) {
+	pg, err := physicalGoods(input)
+	if err != nil {
+		return nil, err
+	}
+	return valuation.LandedCost(pg)
+}
+
+// ChannelMargin walks the price waterfall of a physical product from the
+// retail price through each channel partner's margin to the manufacturer's
+// net price, then deducts landed cost, returns and the warranty reserve:
+//
+//	net revenue  = net price * (1 - return_rate)
+//	return cost  = return_rate * (return_cost - return_recovery_rate * landed)
+//	warranty     = warranty_reserve_pct * net revenue
+//	contribution = net revenue - landed - return cost - warranty
+//
+// Per unit shipped. The cash conversion cycle is DIO + DSO - DPO; with
+// annual_units, working capital is inventory + receivables - payables.
+func (c *Calculator) ChannelMargin(input *domain.AppraisalInput) (*domain.ChannelMarginResult, error) {
+	pg, err := physicalGoods(input)
+	if err != nil {
+		return nil, err
+	}
+	landed, err := c.LandedCost(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var retail float64
+	switch {
+	case pg.RetailPrice != nil:
+		retail = *pg.RetailPrice
+	case input.Product != nil && input.Product.Price > 0:
+		retail = input.Product.Price
+	default:
+		return nil, fmt.Errorf("physical_goods.retail_price or product price required")
+	}
+	if retail <= 0 {
+		return nil, fmt.Errorf("retail price must be positive")
+	}
+	returnRate := valuation.ValueOr(pg.ReturnRate, 0)
+	recovery := valuation.ValueOr(pg.ReturnRecoveryRate, 0)
+	if returnRate < 0 || returnRate > 1 || recovery < 0 || recovery > 1 {
+		return nil, fmt.Errorf("return_rate and return_recovery_rate must be between 0 and 1")
+	}
+
+	result := &domain.ChannelMarginResult{
+		RetailPrice: retail,
+		LandedCost:  landed.LandedCost,
+	}
+	price := retail
+	for _, ch := range pg.ChannelMargins {
+		if ch.Margin < 0 || ch.Margin >= 1 {
+			return nil, fmt.Errorf("channel %q: margin must be in [0, 1)", ch.Name)
+		}
+		buy := price * (1 - ch.Margin)
+		result.Channels = append(result.Channels, domain.ChannelStep{
+			Name:         ch.Name,
+			Margin:       ch.Margin,
+			SellPrice:    price,
+			BuyPrice:     buy,
+			MarginAmount: price - buy,
+		})
+		price = buy
+	}
+	result.NetPrice = price
+	result.ChannelShare = (retail - price) / retail
+
+	result.NetRevenue = price * (1 - returnRate)
+	result.ReturnCost = returnRate * (valuation.ValueOr(pg.ReturnCost, 0) - recovery*landed.LandedCost)
+	result.WarrantyReserve = valuation.ValueOr(pg.WarrantyReservePct, 0) * result.NetRevenue
+	result.UnitContribution = result.NetRevenue - landed.LandedCost - result.ReturnCost - result.WarrantyReserve
+	if result.NetRevenue != 0 {
+		result.ContributionMarginPct = result.UnitContribution / result.NetRevenue
+	}
+	result.Viable = result.UnitContribution > 0
+
+	if landed.DaysInventoryOutstanding != nil {
+		dso := valuation.ValueOr(pg.DaysSalesOutstanding, 0)
+		dpo := valuation.ValueOr(pg.DaysPayablesOutstanding, 0)
+		ccc := *landed.DaysInventoryOutstanding + dso - dpo
+		result.CashConversionCycleDays = &ccc
+		if landed.AverageInventoryValue != nil {
+			receivables := result.NetRevenue * *pg.AnnualUnits * dso / 365
+			payables := *landed.AnnualLandedCost * dpo / 365
+			wc := *landed.AverageInventoryValue + receivables - payables
+			result.WorkingCapital = &wc
+		}
+	}
+
+	return result, nil
+}
+
+func physicalGoods(input *domain.AppraisalInput) (*domain.PhysicalGoodsData, error) {
+	if input.Financials == nil || input.Financials.PhysicalGoods == nil {
+		return nil, fmt.Errorf("financials.physical_goods required")
+	}
+	return input.Financials.PhysicalGoods, nil
+}
@@ -0,0 +1,291 @@
+package financial
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// sensorGoods is a device with a two-part BOM sold through a retailer and a
+// distributor: ex-works 40, landed 60.
+func sensorGoods() *domain.PhysicalGoodsData {
+	return &domain.PhysicalGoodsData{
+		BillOfMaterials: []domain.BOMItem{
+			{Name: "board", UnitCost: 20},
+			{Name: "sensor", UnitCost: 5, Quantity: ptr(2)},
+		},
+		AssemblyCost:            ptr(5),
+		PackagingCost:           ptr(5),
+		FreightPerUnit:          ptr(10),
+		DutyRate:                ptr(0.10),
+		OtherLandedCost:         ptr(5),
+		ChannelMargins:          []domain.ChannelMargin{{Name: "retailer", Margin: 0.4}, {Name: "distributor", Margin: 0.25}},
+		ReturnRate:              ptr(0.1),
+		ReturnCost:              ptr(10),
+		ReturnRecoveryRate:      ptr(0.1),
+		WarrantyReservePct:      ptr(0.02),
+		AnnualUnits:             ptr(1200),
+		InventoryTurns:          ptr(6),
+		DaysSalesOutstanding:    ptr(30),
+		DaysPayablesOutstanding: ptr(45),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// LandedCost
+// ---------------------------------------------------------------------------
+
+func TestLandedCost(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name          string
+		goods         *domain.PhysicalGoodsData
+		wantErr       bool
+		errContains   string
+		wantBOM       float64
+		wantExWorks   float64
+		wantDuty      float64
+		wantLanded    float64
+		wantLogistics float64
+		wantDIO       *float64
+		wantAnnual    *float64
+		wantInventory *float64
+	}{
+		{
+			// BOM 20 + 2*5 = 30; ex-works 40; duty 10% of (40 + 10) = 5; landed 40 + 10 + 5 + 5
+			name:          "bom_with_inventory",
+			goods:         sensorGoods(),
+			wantBOM:       30,
+			wantExWorks:   40,
+			wantDuty:      5,
+			wantLanded:    60,
+			wantLogistics: 20.0 / 60,
+			wantDIO:       ptr(365.0 / 6),
+			wantAnnual:    ptr(72000),
+			wantInventory: ptr(12000),
+		},
+		{
+			name:        "unit_cost_only",
+			goods:       &domain.PhysicalGoodsData{UnitCost: ptr(25)},
+			wantBOM:     25,
+			wantExWorks: 25,
+			wantLanded:  25,
+		},
+		{
+			name:        "no_physical_goods",
+			wantErr:     true,
+			errContains: "physical_goods required",
+		},
+		{
+			name:        "no_unit_cost",
+			goods:       &domain.PhysicalGoodsData{FreightPerUnit: ptr(10)},
+			wantErr:     true,
+			errContains: "bill_of_materials or unit_cost",
+		},
+		{
+			name:        "negative_bom_quantity",
+			goods:       &domain.PhysicalGoodsData{BillOfMaterials: []domain.BOMItem{{Name: "board", UnitCost: 20, Quantity: ptr(-1)}}},
+			wantErr:     true,
+			errContains: `bill_of_materials "board"`,
+		},
+		{
+			name:        "negative_duty",
+			goods:       &domain.PhysicalGoodsData{UnitCost: ptr(25), DutyRate: ptr(-0.1)},
+			wantErr:     true,
+			errContains: "duty_rate",
+		},
+		{
+			name:        "zero_turns",
+			goods:       &domain.PhysicalGoodsData{UnitCost: ptr(25), InventoryTurns: ptr(0)},
+			wantErr:     true,
+			errContains: "inventory_turns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.LandedCost(&domain.AppraisalInput{Financials: &domain.FinancialData{PhysicalGoods: tt.goods}})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !almostEqual(result.BOMCost, tt.wantBOM) || !almostEqual(result.ExWorksCost, tt.wantExWorks) {
+				t.Errorf("BOM/ex-works = %v/%v, want %v/%v", result.BOMCost, result.ExWorksCost, tt.wantBOM, tt.wantExWorks)
+			}
+			if !almostEqual(result.Duty, tt.wantDuty) || !almostEqual(result.LandedCost, tt.wantLanded) {
+				t.Errorf("duty/landed = %v/%v, want %v/%v", result.Duty, result.LandedCost, tt.wantDuty, tt.wantLanded)
+			}
+			if !almostEqual(result.LogisticsShare, tt.wantLogistics) {
+				t.Errorf("LogisticsShare = %v, want %v", result.LogisticsShare, tt.wantLogistics)
+			}
+			if (result.DaysInventoryOutstanding == nil) != (tt.wantDIO == nil) || result.DaysInventoryOutstanding != nil && !almostEqual(*result.DaysInventoryOutstanding, *tt.wantDIO) {
+				t.Errorf("DaysInventoryOutstanding = %v, want %v", result.DaysInventoryOutstanding, tt.wantDIO)
+			}
+			if (result.AnnualLandedCost == nil) != (tt.wantAnnual == nil) || result.AnnualLandedCost != nil && !almostEqual(*result.AnnualLandedCost, *tt.wantAnnual) {
+				t.Errorf("AnnualLandedCost = %v, want %v", result.AnnualLandedCost, tt.wantAnnual)
+			}
+			if (result.AverageInventoryValue == nil) != (tt.wantInventory == nil) || result.AverageInventoryValue != nil && !almostEqual(*result.AverageInventoryValue, *tt.wantInventory) {
+				t.Errorf("AverageInventoryValue = %v, want %v", result.AverageInventoryValue, tt.wantInventory)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// ChannelMargin
+// ---------------------------------------------------------------------------
+
+func TestChannelMargin(t *testing.T) {
+	calc := New()
+
+	modified := func(mod func(*domain.PhysicalGoodsData)) *domain.PhysicalGoodsData {
+		pg := sensorGoods()
+		mod(pg)
+		return pg
+	}
+
+	tests := []struct {
+		name             string
+		price            float64
+		goods            *domain.PhysicalGoodsData
+		wantErr          bool
+		errContains      string
+		wantBuyPrices    []float64
+		wantNetPrice     float64
+		wantChannelShare float64
+		wantNetRevenue   float64
+		wantReturnCost   float64
+		wantWarranty     float64
+		wantContribution float64
+		wantViable       bool
+		wantCCC          *float64
+		wantWC           *float64
+	}{
+		{
+			// 200 retail -> 120 after the retailer's 40% -> 90 after the
+			// distributor's 25%; net revenue 90 * 0.9 = 81; returns
+			// 0.1 * (10 - 0.1*60) = 0.4; warranty 2% of 81
+			name:             "two_channels_with_working_capital",
+			price:            200,
+			goods:            sensorGoods(),
+			wantBuyPrices:    []float64{120, 90},
+			wantNetPrice:     90,
+			wantChannelShare: 0.55,
+			wantNetRevenue:   81,
+			wantReturnCost:   0.4,
+			wantWarranty:     1.62,
+			wantContribution: 18.98,
+			wantViable:       true,
+			wantCCC:          ptr(365.0/6 + 30 - 45),
+			wantWC:           ptr(12000 + 81*1200*30/365.0 - 72000*45/365.0),
+		},
+		{
+			name:             "retail_price_overrides_product_price",
+			price:            200,
+			goods:            &domain.PhysicalGoodsData{UnitCost: ptr(60), RetailPrice: ptr(50)},
+			wantNetPrice:     50,
+			wantNetRevenue:   50,
+			wantContribution: -10,
+		},
+		{
+			name:        "no_physical_goods",
+			price:       200,
+			wantErr:     true,
+			errContains: "physical_goods required",
+		},
+		{
+			name:        "no_unit_cost",
+			price:       200,
+			goods:       modified(func(pg *domain.PhysicalGoodsData) { pg.BillOfMaterials = nil }),
+			wantErr:     true,
+			errContains: "bill_of_materials or unit_cost",
+		},
+		{
+			name:        "no_retail_price",
+			goods:       sensorGoods(),
+			wantErr:     true,
+			errContains: "retail_price",
+		},
+		{
+			name:        "bad_channel_margin",
+			price:       200,
+			goods:       modified(func(pg *domain.PhysicalGoodsData) { pg.ChannelMargins[0].Margin = 1 }),
+			wantErr:     true,
+			errContains: `channel "retailer"`,
+		},
+		{
+			name:        "bad_return_rate",
+			price:       200,
+			goods:       modified(func(pg *domain.PhysicalGoodsData) { pg.ReturnRate = ptr(1.2) }),
+			wantErr:     true,
+			errContains: "return_rate",
+		},
+		{
+			name:        "zero_turns",
+			price:       200,
+			goods:       modified(func(pg *domain.PhysicalGoodsData) { pg.InventoryTurns = ptr(0) }),
+			wantErr:     true,
+			errContains: "inventory_turns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &domain.AppraisalInput{Financials: &domain.FinancialData{PhysicalGoods: tt.goods}}
+			if tt.price > 0 {
+				input.Product = &domain.ProductDefinition{Price: tt.price}
+			}
+			result, err := calc.ChannelMargin(input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Channels) != len(tt.wantBuyPrices) {
+				t.Fatalf("got %d channels, want %d", len(result.Channels), len(tt.wantBuyPrices))
+			}
+			for i, ch := range result.Channels {
+				if !almostEqual(ch.BuyPrice, tt.wantBuyPrices[i]) {
+					t.Errorf("channel %s BuyPrice = %v, want %v", ch.Name, ch.BuyPrice, tt.wantBuyPrices[i])
+				}
+			}
+			if !almostEqual(result.NetPrice, tt.wantNetPrice) || !almostEqual(result.ChannelShare, tt.wantChannelShare) {
+				t.Errorf("net price/channel share = %v/%v, want %v/%v", result.NetPrice, result.ChannelShare, tt.wantNetPrice, tt.wantChannelShare)
+			}
+			if !almostEqual(result.NetRevenue, tt.wantNetRevenue) || !almostEqual(result.ReturnCost, tt.wantReturnCost) ||
+				!almostEqual(result.WarrantyReserve, tt.wantWarranty) {
+				t.Errorf("net revenue/returns/warranty = %v/%v/%v, want %v/%v/%v",
+					result.NetRevenue, result.ReturnCost, result.WarrantyReserve, tt.wantNetRevenue, tt.wantReturnCost, tt.wantWarranty)
+			}
+			if !almostEqual(result.UnitContribution, tt.wantContribution) || result.Viable != tt.wantViable {
+				t.Errorf("contribution/viable = %v/%v, want %v/%v", result.UnitContribution, result.Viable, tt.wantContribution, tt.wantViable)
+			}
+			if !almostEqual(result.ContributionMarginPct, tt.wantContribution/tt.wantNetRevenue) {
+				t.Errorf("ContributionMarginPct = %v, want %v", result.ContributionMarginPct, tt.wantContribution/tt.wantNetRevenue)
+			}
+			if (result.CashConversionCycleDays == nil) != (tt.wantCCC == nil) || result.CashConversionCycleDays != nil && !almostEqual(*result.CashConversionCycleDays, *tt.wantCCC) {
+				t.Errorf("CashConversionCycleDays = %v, want %v", result.CashConversionCycleDays, tt.wantCCC)
+			}
+			if (result.WorkingCapital == nil) != (tt.wantWC == nil) || result.WorkingCapital != nil && !almostEqual(*result.WorkingCapital, *tt.wantWC) {
+				t.Errorf("WorkingCapital = %v, want %v", result.WorkingCapital, tt.wantWC)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package financial
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
+)
+
+const (
+	defaultProjectionMonths = 36
+	defaultDiscountRate     = 0.10
+)
+
+// Projection builds a month-by-month P&L and cash-flow table (FIN-6).
+//
+// Each month: churned = customers * monthly_churn, customers += new - churned,
+// revenue = customers * price, operating profit = revenue - variable - fixed,
+// cash flow = operating profit - new * CAC. Month 0 holds -launch_investment.
+// NPV discounts at the monthly equivalent of the annual discount rate; IRR is
+// solved monthly and annualized. Break-even month is the first month
+// cumulative cash turns non-negative; discounted payback uses discounted flows.
+func (c *Calculator) Projection(input *domain.AppraisalInput) (*domain.ProjectionResult, error) {
+	if input.Financials == nil || input.Financials.Projection == nil {
+		return nil, fmt.Errorf("financials.projection required")
+	}
+	f := input.Financials
+	p := f.Projection
+
+	months := defaultProjectionMonths
+	if p.Months != nil {
+		months = *p.Months
+	}
+	if months <= 0 {
+		return nil, fmt.Errorf("months must be positive")
+	}
+
+	annualRate := defaultDiscountRate
+	if p.DiscountRate != nil {
+		annualRate = *p.DiscountRate
+	}
+	if annualRate <= -1 {
+		return nil, fmt.Errorf("discount_rate must be greater than -1")
+	}
+
+	var price float64
+	switch {
+	case p.PricePerCustomer != nil:
+		price = *p.PricePerCustomer
+	case f.RevenuePerCustomer != nil:
+		price = *f.RevenuePerCustomer
+	case input.Product != nil:
+		price = input.Product.Price
+	default:
+		return nil, fmt.Errorf("price_per_customer, revenue_per_customer, or product price required")
+	}
+
+	var cost float64
+	if p.CostPerCustomer != nil {
+		cost = *p.CostPerCustomer
+	} else if f.DirectCostPerCustomer != nil {
+		cost = *f.DirectCostPerCustomer
+	}
+
+	churn := valuation.ValueOr(p.MonthlyChurn, 0)
+	if churn < 0 || churn > 1 {
+		return nil, fmt.Errorf("monthly_churn must be between 0 and 1")
+	}
+	customers := valuation.ValueOr(p.StartingCustomers, 0)
+	if customers < 0 {
+		return nil, fmt.Errorf("starting_customers must be non-negative")
+	}
+	if customers == 0 && len(p.NewCustomers) == 0 {
+		return nil, fmt.Errorf("starting_customers or new_customers required")
+	}
+
+	priceSchedule, err := sortSchedule("price_changes", p.PriceChanges)
+	if err != nil {
+		return nil, err
+	}
+	costSchedule, err := sortSchedule("cost_changes", p.CostChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	fixed := valuation.ValueOr(p.FixedCostsMonthly, 0)
+	cac := valuation.ValueOr(p.CACPerCustomer, 0)
+	investment := valuation.ValueOr(p.LaunchInvestment, 0)
+	monthlyRate := math.Pow(1+annualRate, 1.0/12) - 1
+
+	result := &domain.ProjectionResult{DiscountRate: annualRate}
+
+	flows := make([]float64, 0, months+1)
+	month0 := domain.ProjectionMonth{
+		Month:      0,
+		Customers:  customers,
+		Investment: investment,
+		CashFlow:   -investment,
+	}
+	month0.CumulativeCash = month0.CashFlow
+	month0.DiscountedCashFlow = month0.CashFlow
+	month0.CumulativeDiscountedCash = month0.CashFlow
+	result.Months = append(result.Months, month0)
+	flows = append(flows, month0.CashFlow)
+
+	cumulative := month0.CumulativeCash
+	cumulativeDiscounted := month0.CumulativeDiscountedCash
+	peakDeficit := math.Min(cumulative, 0)
+
+	for m := 1; m <= months; m++ {
+		price = scheduledValue(priceSchedule, m, price)
+		cost = scheduledValue(costSchedule, m, cost)
+
+		newCustomers := rampValue(p.NewCustomers, m)
+		churned := customers * churn
+		customers = customers - churned + newCustomers
+
+		row := domain.ProjectionMonth{
+			Month:            m,
+			Customers:        customers,
+			NewCustomers:     newCustomers,
+			ChurnedCustomers: churned,
+			Price:            price,
+			Revenue:          customers * price,
+			VariableCost:     customers * cost,
+			FixedCost:        fixed,
+			AcquisitionCost:  newCustomers * cac,
+		}
+		row.OperatingProfit = row.Revenue - row.VariableCost - row.FixedCost
+		row.CashFlow = row.OperatingProfit - row.AcquisitionCost
+		row.DiscountedCashFlow = row.CashFlow / math.Pow(1+monthlyRate, float64(m))
+
+		cumulative += row.CashFlow
+		cumulativeDiscounted += row.DiscountedCashFlow
+		row.CumulativeCash = cumulative
+		row.CumulativeDiscountedCash = cumulativeDiscounted
+		peakDeficit = math.Min(peakDeficit, cumulative)
+
+		if result.OperatingBreakEvenMonth == nil && row.OperatingProfit >= 0 {
+			result.OperatingBreakEvenMonth = intPtr(m)
+		}
+		if result.BreakEvenMonth == nil && cumulative >= 0 {
+			result.BreakEvenMonth = intPtr(m)
+		}
+		if result.DiscountedPaybackMonth == nil && cumulativeDiscounted >= 0 {
+			result.DiscountedPaybackMonth = intPtr(m)
+		}
+
+		result.TotalRevenue += row.Revenue
+		result.Months = append(result.Months, row)
+		flows = append(flows, row.CashFlow)
+	}
+
+	result.NPV = cumulativeDiscounted
+	result.TotalCashFlow = cumulative
+	result.PeakFundingNeed = -peakDeficit
+	result.EndingCustomers = customers
+	if irr, ok := monthlyIRR(flows); ok {
+		annual := math.Pow(1+irr, 12) - 1
+		result.IRR = &annual
+	}
+
+	return result, nil
+}
+
+// sortSchedule validates a schedule and orders it by month.
+func sortSchedule(field string, schedule []domain.ScheduledValue) ([]domain.ScheduledValue, error) {
+	sorted := append([]domain.ScheduledValue(nil), schedule...)
+	for _, s := range sorted {
+		if s.Month < 1 {
+			return nil, fmt.Errorf("%s: month must be >= 1", field)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Month < sorted[j].Month })
+	return sorted, nil
+}
+
+// scheduledValue returns the value in effect at month m, or current if no
+// schedule entry starts at m.
+func scheduledValue(schedule []domain.ScheduledValue, m int, current float64) float64 {
+	for _, s := range schedule {
+		if s.Month == m {
+			current = s.Value
+		}
+	}
+	return current
+}
+
+// rampValue returns the ramp entry for month m (1-based); the last value repeats.
+func rampValue(ramp []float64, m int) float64 {
+	if len(ramp) == 0 {
+		return 0
+	}
+	if m > len(ramp) {
+		return ramp[len(ramp)-1]
+	}
+	return ramp[m-1]
+}
+
+// npvAt discounts flows (flows[t] at period t) at the given per-period rate.
+func npvAt(rate float64, flows []float64) float64 {
+	total := 0.0
+	for t, cf := range flows {
+		total += cf / math.Pow(1+rate, float64(t))
+	}
+	return total
+}
+
+// monthlyIRR solves npvAt(r) = 0 by bisection. Returns false when the flows
+// never change sign or no root is bracketed.
+func monthlyIRR(flows []float64) (float64, bool) {
+	hasPos, hasNeg := false, false
+	for _, cf := range flows {
+		hasPos = hasPos || cf > 0
+		hasNeg = hasNeg || cf < 0
+	}
+	if !hasPos || !hasNeg {
+		return 0, false
+	}
+
+	lo, hi := -0.99, 1.0
+	fLo := npvAt(lo, flows)
+	fHi := npvAt(hi, flows)
+	for fLo*fHi > 0 && hi < 1e3 {
+		hi *= 2
+		fHi = npvAt(hi, flows)
+	}
+	if fLo*fHi > 0 {
+		return 0, false
+	}
+
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		fMid := npvAt(mid, flows)
+		if fMid == 0 || hi-lo < 1e-12 {
+			return mid, true
+		}
+		if fLo*fMid < 0 {
+			hi = mid
+		} else {
+			lo, fLo = mid, fMid
+		}
+	}
+	return (lo + hi) / 2, true
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,191 @@
+package financial
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func TestProjection(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name                string
+		launch              float64
+		discountRate        float64
+		wantNPV             float64
+		wantBreakEven       int
+		wantDiscountedBreak int
+		wantIRR             *float64
+		wantPeak, wantTotal float64
+	}{
+		// Monthly IRR 23.375% -> annualized (1.23375)^12 - 1
+		{"undiscounted", 1000, 0, 500, 2, 2, ptr(11.437529805), 1000, 500},
+		{"10% annual", 1000, 0.10, 476.391752416534, 2, 3, ptr(11.437529805), 1000, 500},
+		// Cash never goes negative: break-even in the first month, no IRR.
+		{"no launch investment", 0, 0, 1500, 1, 1, nil, 0, 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &domain.AppraisalInput{
+				Financials: &domain.FinancialData{
+					Projection: &domain.ProjectionData{
+						Months:            intPtr(3),
+						LaunchInvestment:  ptr(tt.launch),
+						DiscountRate:      ptr(tt.discountRate),
+						StartingCustomers: ptr(100),
+						PricePerCustomer:  ptr(10),
+						CostPerCustomer:   ptr(4),
+						FixedCostsMonthly: ptr(100),
+					},
+				},
+			}
+			result, err := calc.Projection(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Months) != 4 {
+				t.Fatalf("got %d rows, want 4 (month 0 + 3)", len(result.Months))
+			}
+			for _, row := range result.Months[1:] {
+				if !almostEqual(row.OperatingProfit, 500) || !almostEqual(row.CashFlow, 500) {
+					t.Errorf("month %d profit/cash = %v/%v, want 500/500", row.Month, row.OperatingProfit, row.CashFlow)
+				}
+			}
+			if !almostEqual(result.Months[0].CashFlow, -tt.launch) {
+				t.Errorf("month 0 cash flow = %v, want %v", result.Months[0].CashFlow, -tt.launch)
+			}
+			if math.Abs(result.NPV-tt.wantNPV) > 1e-6 {
+				t.Errorf("NPV = %v, want %v", result.NPV, tt.wantNPV)
+			}
+			if result.BreakEvenMonth == nil || *result.BreakEvenMonth != tt.wantBreakEven {
+				t.Errorf("BreakEvenMonth = %v, want %d", result.BreakEvenMonth, tt.wantBreakEven)
+			}
+			if result.OperatingBreakEvenMonth == nil || *result.OperatingBreakEvenMonth != 1 {
+				t.Errorf("OperatingBreakEvenMonth = %v, want 1", result.OperatingBreakEvenMonth)
+			}
+			if result.DiscountedPaybackMonth == nil || *result.DiscountedPaybackMonth != tt.wantDiscountedBreak {
+				t.Errorf("DiscountedPaybackMonth = %v, want %d", result.DiscountedPaybackMonth, tt.wantDiscountedBreak)
+			}
+			if (result.IRR == nil) != (tt.wantIRR == nil) || (tt.wantIRR != nil && math.Abs(*result.IRR-*tt.wantIRR) > 1e-6) {
+				t.Errorf("IRR = %v, want %v", result.IRR, tt.wantIRR)
+			}
+			if !almostEqual(result.PeakFundingNeed, tt.wantPeak) || !almostEqual(result.TotalCashFlow, tt.wantTotal) {
+				t.Errorf("peak/total = %v/%v, want %v/%v", result.PeakFundingNeed, result.TotalCashFlow, tt.wantPeak, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProjectionRampChurnAndSchedules(t *testing.T) {
+	calc := New()
+
+	result, err := calc.Projection(&domain.AppraisalInput{
+		Product: &domain.ProductDefinition{Name: "Bundle", Price: 10},
+		Financials: &domain.FinancialData{
+			DirectCostPerCustomer: ptr(2),
+			Projection: &domain.ProjectionData{
+				Months:         intPtr(4),
+				NewCustomers:   []float64{10, 20}, // 20 repeats
+				MonthlyChurn:   ptr(0.1),
+				PriceChanges:   []domain.ScheduledValue{{Month: 3, Value: 12}},
+				CACPerCustomer: ptr(30),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		customers, churned, price, cashFlow float64
+	}{
+		{10, 0, 10, -220},
+		{29, 1, 10, -368},
+		{46.1, 2.9, 12, -139},
+		{61.49, 4.61, 12, 14.9},
+	}
+	for i, w := range want {
+		row := result.Months[i+1]
+		if math.Abs(row.Customers-w.customers) > 1e-9 || math.Abs(row.ChurnedCustomers-w.churned) > 1e-9 {
+			t.Errorf("month %d customers/churned = %v/%v, want %v/%v", row.Month, row.Customers, row.ChurnedCustomers, w.customers, w.churned)
+		}
+		if row.Price != w.price {
+			t.Errorf("month %d price = %v, want %v", row.Month, row.Price, w.price)
+		}
+		if math.Abs(row.CashFlow-w.cashFlow) > 1e-9 {
+			t.Errorf("month %d cash flow = %v, want %v", row.Month, row.CashFlow, w.cashFlow)
+		}
+	}
+	if result.BreakEvenMonth != nil {
+		t.Errorf("BreakEvenMonth = %d, want nil (never recovers)", *result.BreakEvenMonth)
+	}
+	if math.Abs(result.PeakFundingNeed-727) > 1e-9 {
+		t.Errorf("PeakFundingNeed = %v, want 727", result.PeakFundingNeed)
+	}
+	if math.Abs(result.EndingCustomers-61.49) > 1e-9 {
+		t.Errorf("EndingCustomers = %v, want 61.49", result.EndingCustomers)
+	}
+	if result.DiscountRate != defaultDiscountRate {
+		t.Errorf("DiscountRate = %v, want default %v", result.DiscountRate, defaultDiscountRate)
+	}
+}
+
+func TestProjectionErrors(t *testing.T) {
+	calc := New()
+
+	tests := []struct {
+		name    string
+		input   *domain.AppraisalInput
+		wantErr string
+	}{
+		{"no financials", &domain.AppraisalInput{}, "financials.projection required"},
+		{
+			"no price",
+			&domain.AppraisalInput{Financials: &domain.FinancialData{Projection: &domain.ProjectionData{StartingCustomers: ptr(10)}}},
+			"price_per_customer",
+		},
+		{
+			"no customers",
+			&domain.AppraisalInput{Financials: &domain.FinancialData{Projection: &domain.ProjectionData{PricePerCustomer: ptr(10)}}},
+			"starting_customers or new_customers required",
+		},
+		{
+			"bad churn",
+			&domain.AppraisalInput{Financials: &domain.FinancialData{Projection: &domain.ProjectionData{
+				PricePerCustomer: ptr(10), StartingCustomers: ptr(10), MonthlyChurn: ptr(1.5),
+			}}},
+			"monthly_churn",
+		},
+		{
+			"bad schedule month",
+			&domain.AppraisalInput{Financials: &domain.FinancialData{Projection: &domain.ProjectionData{
+				PricePerCustomer: ptr(10), StartingCustomers: ptr(10),
+				CostChanges: []domain.ScheduledValue{{Month: 0, Value: 1}},
+			}}},
+			"cost_changes",
+		},
+		{
+			"zero months",
+			&domain.AppraisalInput{Financials: &domain.FinancialData{Projection: &domain.ProjectionData{
+				Months: intPtr(0), PricePerCustomer: ptr(10), StartingCustomers: ptr(10),
+			}}},
+			"months must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := calc.Projection(tt.input)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
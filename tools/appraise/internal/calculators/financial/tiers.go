@@ -0,0 +1,152 @@
+package financial
+
+import (
+	"fmt"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// TierEconomics calculates unit economics per tier and blended across the
+// tier mix. Tier shares are normalized, so percentages or fractions both
+// work. A tier without cost_per_customer uses direct_cost_per_customer.
+// Each of financials.tier_mix_shifts is applied to the current mix on its
+// own and reported as the change in blended ARPU and margin (and in total
+// revenue and gross profit when the customer base is known).
+func (c *Calculator) TierEconomics(input *domain.AppraisalInput) (*domain.TierEconomicsResult, error) {
+	if len(input.Tiers) == 0 {
+		return nil, fmt.Errorf("tiers required")
+	}
+
+	var fallbackCost *float64
+	var shifts []domain.TierMixShift
+	var customers *float64
+	if f := input.Financials; f != nil {
+		fallbackCost = f.DirectCostPerCustomer
+		shifts = f.TierMixShifts
+		customers = f.AverageCustomerCount
+	}
+	if customers == nil && input.Customers != nil {
+		customers = input.Customers.TotalCustomers
+	}
+
+	index := make(map[string]int, len(input.Tiers))
+	prices := make([]float64, len(input.Tiers))
+	costs := make([]float64, len(input.Tiers))
+	shares := make([]float64, len(input.Tiers))
+	shareSum := 0.0
+	for i, tier := range input.Tiers {
+		if _, dup := index[tier.Name]; dup {
+			return nil, fmt.Errorf("duplicate tier %q", tier.Name)
+		}
+		index[tier.Name] = i
+		if tier.CustomerShare == nil || *tier.CustomerShare < 0 {
+			return nil, fmt.Errorf("tier %q: customer_share required (non-negative)", tier.Name)
+		}
+		cost := tier.CostPerCustomer
+		if cost == nil {
+			cost = fallbackCost
+		}
+		if cost == nil {
+			return nil, fmt.Errorf("tier %q: cost_per_customer or financials.direct_cost_per_customer required", tier.Name)
+		}
+		prices[i], costs[i], shares[i] = tier.Price, *cost, *tier.CustomerShare
+		shareSum += *tier.CustomerShare
+	}
+	if shareSum <= 0 {
+		return nil, fmt.Errorf("customer shares must sum to a positive value")
+	}
+	for i := range shares {
+		shares[i] /= shareSum
+	}
+
+	arpu, cost := blend(prices, shares), blend(costs, shares)
+	result := &domain.TierEconomicsResult{
+		BlendedARPU:   arpu,
+		BlendedCost:   cost,
+		BlendedMargin: arpu - cost,
+		Customers:     customers,
+	}
+	if arpu != 0 {
+		result.BlendedMarginPct = result.BlendedMargin / arpu
+	}
+	if customers != nil {
+		revenue := arpu * *customers
+		profit := result.BlendedMargin * *customers
+		result.Revenue = &revenue
+		result.GrossProfit = &profit
+	}
+
+	for i, tier := range input.Tiers {
+		te := domain.TierEconomics{
+			Name:            tier.Name,
+			Price:           prices[i],
+			CostPerCustomer: costs[i],
+			Margin:          prices[i] - costs[i],
+			Share:           shares[i],
+		}
+		if prices[i] != 0 {
+			te.MarginPct = te.Margin / prices[i]
+		}
+		if arpu != 0 {
+			te.RevenueShare = shares[i] * prices[i] / arpu
+		}
+		if result.BlendedMargin != 0 {
+			te.MarginShare = shares[i] * te.Margin / result.BlendedMargin
+		}
+		if customers != nil {
+			n := shares[i] * *customers
+			te.Customers = &n
+		}
+		result.Tiers = append(result.Tiers, te)
+	}
+
+	for _, s := range shifts {
+		from, okFrom := index[s.From]
+		to, okTo := index[s.To]
+		if !okFrom || !okTo {
+			return nil, fmt.Errorf("mix shift %s->%s: unknown tier", s.From, s.To)
+		}
+		if from == to {
+			return nil, fmt.Errorf("mix shift %s->%s: tiers must differ", s.From, s.To)
+		}
+		if s.Share < 0 || s.Share > 1 {
+			return nil, fmt.Errorf("mix shift %s->%s: share must be between 0 and 1", s.From, s.To)
+		}
+
+		shifted := append([]float64(nil), shares...)
+		moved := shifted[from] * s.Share
+		shifted[from] -= moved
+		shifted[to] += moved
+
+		shiftARPU := blend(prices, shifted)
+		shiftMargin := shiftARPU - blend(costs, shifted)
+		sr := domain.TierMixShiftResult{
+			From:          s.From,
+			To:            s.To,
+			Share:         s.Share,
+			MovedShare:    moved,
+			BlendedARPU:   shiftARPU,
+			BlendedMargin: shiftMargin,
+			ARPUDelta:     shiftARPU - arpu,
+			MarginDelta:   shiftMargin - result.BlendedMargin,
+		}
+		if customers != nil {
+			revenueDelta := sr.ARPUDelta * *customers
+			profitDelta := sr.MarginDelta * *customers
+			sr.RevenueDelta = &revenueDelta
+			sr.GrossProfitDelta = &profitDelta
+		}
+		result.MixShifts = append(result.MixShifts, sr)
+	}
+
+	return result, nil
+}
+
+// blend returns the share-weighted average of values.
+func blend(values, shares []float64) float64 {
+	total := 0.0
+	for i, v := range values {
+		total += v * shares[i]
+	}
+	return total
+}
@@ -0,0 +1,228 @@
+package financial
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// TierEconomics
+// ---------------------------------------------------------------------------
+
+func TestTierEconomics(t *testing.T) {
+	calc := New()
+
+	threeTiers := func() []domain.TierDefinition {
+		return []domain.TierDefinition{
+			{Name: "entry", Level: 1, Price: 10, CustomerShare: ptr(30), CostPerCustomer: ptr(4)},
+			{Name: "middle", Level: 2, Price: 20, CustomerShare: ptr(50), CostPerCustomer: ptr(8)},
+			{Name: "premium", Level: 3, Price: 40, CustomerShare: ptr(20)},
+		}
+	}
+	shifts := []domain.TierMixShift{
+		{From: "middle", To: "premium", Share: 0.10},
+		{From: "premium", To: "entry", Share: 0.5},
+	}
+
+	type wantShift struct {
+		arpuDelta, marginDelta float64
+		revenueDelta           *float64
+	}
+
+	tests := []struct {
+		name          string
+		input         *domain.AppraisalInput
+		wantErr       bool
+		errContains   string
+		wantARPU      float64
+		wantCost      float64
+		wantMarginPct float64
+		wantRevenue   *float64
+		wantMargins   []float64
+		wantMarginShr []float64
+		wantCustomers []float64
+		wantMixShifts []wantShift
+	}{
+		{
+			// ARPU = .3*10 + .5*20 + .2*40 = 21; cost = 1.2 + 4 + .2*20 = 9.2
+			name: "three_tiers_with_base",
+			input: &domain.AppraisalInput{
+				Tiers: threeTiers(),
+				Financials: &domain.FinancialData{
+					DirectCostPerCustomer: ptr(20), // premium falls back to this
+					AverageCustomerCount:  ptr(1000),
+					TierMixShifts:         shifts,
+				},
+			},
+			wantARPU:      21,
+			wantCost:      9.2,
+			wantMarginPct: 11.8 / 21,
+			wantRevenue:   ptr(21000),
+			wantMargins:   []float64{6, 12, 20},
+			wantMarginShr: []float64{1.8 / 11.8, 6.0 / 11.8, 4.0 / 11.8},
+			wantCustomers: []float64{300, 500, 200},
+			wantMixShifts: []wantShift{
+				// 10% of middle (5% of base) to premium: shares .3/.45/.25
+				{arpuDelta: 1, marginDelta: 0.4, revenueDelta: ptr(1000)},
+				// half of premium (10% of base) down to entry
+				{arpuDelta: -3, marginDelta: -1.4, revenueDelta: ptr(-3000)},
+			},
+		},
+		{
+			name: "without_customer_base",
+			input: &domain.AppraisalInput{
+				Tiers: threeTiers(),
+				Financials: &domain.FinancialData{
+					DirectCostPerCustomer: ptr(20),
+					TierMixShifts:         shifts,
+				},
+			},
+			wantARPU:      21,
+			wantCost:      9.2,
+			wantMarginPct: 11.8 / 21,
+			wantMargins:   []float64{6, 12, 20},
+			wantMarginShr: []float64{1.8 / 11.8, 6.0 / 11.8, 4.0 / 11.8},
+			wantMixShifts: []wantShift{{arpuDelta: 1, marginDelta: 0.4}, {arpuDelta: -3, marginDelta: -1.4}},
+		},
+		{
+			name:        "no_tiers",
+			input:       &domain.AppraisalInput{Financials: &domain.FinancialData{}},
+			wantErr:     true,
+			errContains: "tiers required",
+		},
+		{
+			name: "missing_share",
+			input: &domain.AppraisalInput{Tiers: []domain.TierDefinition{
+				{Name: "entry", Price: 10, CustomerShare: ptr(30), CostPerCustomer: ptr(4)},
+				{Name: "middle", Price: 20, CostPerCustomer: ptr(8)},
+			}},
+			wantErr:     true,
+			errContains: `tier "middle": customer_share required`,
+		},
+		{
+			name:        "missing_cost",
+			input:       &domain.AppraisalInput{Tiers: threeTiers(), Financials: &domain.FinancialData{}},
+			wantErr:     true,
+			errContains: `tier "premium": cost_per_customer`,
+		},
+		{
+			name: "duplicate_tier",
+			input: &domain.AppraisalInput{Tiers: []domain.TierDefinition{
+				{Name: "entry", Price: 10, CustomerShare: ptr(30), CostPerCustomer: ptr(4)},
+				{Name: "entry", Price: 20, CustomerShare: ptr(50), CostPerCustomer: ptr(8)},
+			}},
+			wantErr:     true,
+			errContains: "duplicate tier",
+		},
+		{
+			name: "zero_shares",
+			input: &domain.AppraisalInput{Tiers: []domain.TierDefinition{
+				{Name: "entry", Price: 10, CustomerShare: ptr(0), CostPerCustomer: ptr(4)},
+				{Name: "middle", Price: 20, CustomerShare: ptr(0), CostPerCustomer: ptr(8)},
+			}},
+			wantErr:     true,
+			errContains: "sum to a positive value",
+		},
+		{
+			name: "unknown_shift_tier",
+			input: &domain.AppraisalInput{
+				Tiers: threeTiers(),
+				Financials: &domain.FinancialData{
+					DirectCostPerCustomer: ptr(20),
+					TierMixShifts:         []domain.TierMixShift{{From: "middle", To: "gold", Share: 0.1}},
+				},
+			},
+			wantErr:     true,
+			errContains: "unknown tier",
+		},
+		{
+			name: "same_shift_tier",
+			input: &domain.AppraisalInput{
+				Tiers: threeTiers(),
+				Financials: &domain.FinancialData{
+					DirectCostPerCustomer: ptr(20),
+					TierMixShifts:         []domain.TierMixShift{{From: "middle", To: "middle", Share: 0.1}},
+				},
+			},
+			wantErr:     true,
+			errContains: "tiers must differ",
+		},
+		{
+			name: "bad_shift_share",
+			input: &domain.AppraisalInput{
+				Tiers: threeTiers(),
+				Financials: &domain.FinancialData{
+					DirectCostPerCustomer: ptr(20),
+					TierMixShifts:         []domain.TierMixShift{{From: "middle", To: "premium", Share: 1.5}},
+				},
+			},
+			wantErr:     true,
+			errContains: "between 0 and 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := calc.TierEconomics(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !almostEqual(result.BlendedARPU, tt.wantARPU) {
+				t.Errorf("BlendedARPU = %v, want %v", result.BlendedARPU, tt.wantARPU)
+			}
+			if !almostEqual(result.BlendedCost, tt.wantCost) {
+				t.Errorf("BlendedCost = %v, want %v", result.BlendedCost, tt.wantCost)
+			}
+			if !almostEqual(result.BlendedMargin, tt.wantARPU-tt.wantCost) || !almostEqual(result.BlendedMarginPct, tt.wantMarginPct) {
+				t.Errorf("BlendedMargin = %v (%v), want %v (%v)", result.BlendedMargin, result.BlendedMarginPct, tt.wantARPU-tt.wantCost, tt.wantMarginPct)
+			}
+			if (result.Revenue == nil) != (tt.wantRevenue == nil) || result.Revenue != nil && !almostEqual(*result.Revenue, *tt.wantRevenue) {
+				t.Errorf("Revenue = %v, want %v", result.Revenue, tt.wantRevenue)
+			}
+			if tt.wantRevenue != nil && (result.GrossProfit == nil || !almostEqual(*result.GrossProfit, *tt.wantRevenue*tt.wantMarginPct)) {
+				t.Errorf("GrossProfit = %v, want %v", result.GrossProfit, *tt.wantRevenue*tt.wantMarginPct)
+			}
+
+			if len(result.Tiers) != len(tt.wantMargins) {
+				t.Fatalf("got %d tiers, want %d", len(result.Tiers), len(tt.wantMargins))
+			}
+			for i, te := range result.Tiers {
+				if !almostEqual(te.Margin, tt.wantMargins[i]) || !almostEqual(te.MarginShare, tt.wantMarginShr[i]) {
+					t.Errorf("tier %s margin/share = %v/%v, want %v/%v", te.Name, te.Margin, te.MarginShare, tt.wantMargins[i], tt.wantMarginShr[i])
+				}
+				var wantCustomers *float64
+				if tt.wantCustomers != nil {
+					wantCustomers = &tt.wantCustomers[i]
+				}
+				if (te.Customers == nil) != (wantCustomers == nil) || te.Customers != nil && !almostEqual(*te.Customers, *wantCustomers) {
+					t.Errorf("tier %s customers = %v, want %v", te.Name, te.Customers, wantCustomers)
+				}
+			}
+
+			if len(result.MixShifts) != len(tt.wantMixShifts) {
+				t.Fatalf("got %d mix shifts, want %d", len(result.MixShifts), len(tt.wantMixShifts))
+			}
+			for i, w := range tt.wantMixShifts {
+				got := result.MixShifts[i]
+				if math.Abs(got.ARPUDelta-w.arpuDelta) > 1e-9 || math.Abs(got.MarginDelta-w.marginDelta) > 1e-9 {
+					t.Errorf("shift %s->%s ARPU/margin delta = %v/%v, want %v/%v", got.From, got.To, got.ARPUDelta, got.MarginDelta, w.arpuDelta, w.marginDelta)
+				}
+				if (got.RevenueDelta == nil) != (w.revenueDelta == nil) || got.RevenueDelta != nil && !almostEqual(*got.RevenueDelta, *w.revenueDelta) {
+					t.Errorf("shift %s->%s RevenueDelta = %v, want %v", got.From, got.To, got.RevenueDelta, w.revenueDelta)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package financial
+
+import (
+	"fmt"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
+)
+
+// PriceWaterfall walks each of financials.sales_channels from list price
+// through its deductions (channel discounts, promotions, payment fees,
+// returns, taxes, partner fees) to the pocket price, then subtracts the unit
+// cost for the pocket margin. Channels are blended by normalized volume
+// share; deductions with the same name are also blended across channels.
+//
+// List price defaults to physical_goods.retail_price, then product.price.
+// Unit cost defaults to direct_cost_per_customer, then the landed cost of
+// physical_goods.
+func (c *Calculator) PriceWaterfall(input *domain.AppraisalInput) (*domain.PriceWaterfallResult, error) {
+	if input.Financials == nil || len(input.Financials.SalesChannels) == 0 {
+		return nil, fmt.Errorf("financials.sales_channels required")
+	}
+	f := input.Financials
+
+	var defaultList *float64
+	switch {
+	case f.PhysicalGoods != nil && f.PhysicalGoods.RetailPrice != nil:
+		defaultList = f.PhysicalGoods.RetailPrice
+	case input.Product != nil && input.Product.Price > 0:
+		defaultList = &input.Product.Price
+	}
+	defaultCost := f.DirectCostPerCustomer
+	if defaultCost == nil && hasLandedCostInputs(f.PhysicalGoods) {
+		landed, err := c.LandedCost(input)
+		if err != nil {
+			return nil, fmt.Errorf("landed cost: %w", err)
+		}
+		defaultCost = &landed.LandedCost
+	}
+
+	shareSum := 0.0
+	for _, ch := range f.SalesChannels {
+		if ch.VolumeShare < 0 {
+			return nil, fmt.Errorf("channel %q: volume_share must be non-negative", ch.Name)
+		}
+		shareSum += ch.VolumeShare
+	}
+	if shareSum <= 0 {
+		return nil, fmt.Errorf("channel volume shares must sum to a positive value")
+	}
+
+	result := &domain.PriceWaterfallResult{}
+	blended := map[string]int{}
+	for _, ch := range f.SalesChannels {
+		list := ch.ListPrice
+		if list == nil {
+			list = defaultList
+		}
+		if list == nil || *list <= 0 {
+			return nil, fmt.Errorf("channel %q: list_price or product price required", ch.Name)
+		}
+		cost := ch.UnitCost
+		if cost == nil {
+			cost = defaultCost
+		}
+		if cost == nil {
+			return nil, fmt.Errorf("channel %q: unit_cost, direct_cost_per_customer or physical_goods required", ch.Name)
+		}
+
+		cw := domain.ChannelWaterfall{
+			Name:        ch.Name,
+			VolumeShare: ch.VolumeShare / shareSum,
+			ListPrice:   *list,
+			UnitCost:    *cost,
+		}
+		price := *list
+		for _, d := range ch.Deductions {
+			amount := valuation.ValueOr(d.Amount, 0)
+			if d.Pct != nil {
+				if *d.Pct < 0 || *d.Pct > 1 {
+					return nil, fmt.Errorf("channel %q, deduction %q: pct must be between 0 and 1", ch.Name, d.Name)
+				}
+				switch d.Basis {
+				case "", "running":
+					amount += *d.Pct * price
+				case "list":
+					amount += *d.Pct * *list
+				default:
+					return nil, fmt.Errorf("channel %q, deduction %q: basis must be running or list", ch.Name, d.Name)
+				}
+			}
+			price -= amount
+			cw.Steps = append(cw.Steps, domain.WaterfallStep{
+				Name:       d.Name,
+				Amount:     amount,
+				PriceAfter: price,
+				PctOfList:  amount / *list,
+			})
+
+			i, ok := blended[d.Name]
+			if !ok {
+				i = len(result.Deductions)
+				blended[d.Name] = i
+				result.Deductions = append(result.Deductions, domain.WaterfallStep{Name: d.Name})
+			}
+			result.Deductions[i].Amount += cw.VolumeShare * amount
+		}
+		cw.PocketPrice = price
+		cw.Leakage = 1 - price / *list
+		cw.PocketMargin = price - *cost
+		if price != 0 {
+			cw.PocketMarginPct = cw.PocketMargin / price
+		}
+
+		result.BlendedListPrice += cw.VolumeShare * *list
+		result.BlendedPocketPrice += cw.VolumeShare * price
+		result.BlendedUnitCost += cw.VolumeShare * *cost
+		result.Channels = append(result.Channels, cw)
+	}
+
+	price := result.BlendedListPrice
+	for i := range result.Deductions {
+		price -= result.Deductions[i].Amount
+		result.Deductions[i].PriceAfter = price
+		result.Deductions[i].PctOfList = result.Deductions[i].Amount / result.BlendedListPrice
+	}
+	result.BlendedPocketMargin = result.BlendedPocketPrice - result.BlendedUnitCost
+	if result.BlendedPocketPrice != 0 {
+		result.BlendedPocketMarginPct = result.BlendedPocketMargin / result.BlendedPocketPrice
+	}
+	result.Leakage = 1 - result.BlendedPocketPrice/result.BlendedListPrice
+
+	best, worst := 0, 0
+	for i, cw := range result.Channels {
+		if cw.PocketMargin > result.Channels[best].PocketMargin {
+			best = i
+		}
+		if cw.PocketMargin < result.Channels[worst].PocketMargin {
+			worst = i
+		}
+	}
+	result.BestChannel = result.Channels[best].Name
+	result.WorstChannel = result.Channels[worst].Name
+
+	return result, nil
+}
+
+// hasLandedCostInputs reports whether physical_goods carries any of the cost
+// build-up fields, as opposed to only a retail price or channel terms.
+func hasLandedCostInputs(pg *domain.PhysicalGoodsData) bool {
+	return pg != nil && (len(pg.BillOfMaterials) > 0 || pg.UnitCost != nil || pg.AssemblyCost != nil ||
+		pg.PackagingCost != nil || pg.FreightPerUnit != nil || pg.DutyRate != nil || pg.OtherLandedCost != nil)
+}
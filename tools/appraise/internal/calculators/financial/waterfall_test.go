@@ -0,0 +1,270 @@
+package financial
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// ---------------------------------------------------------------------------
+// PriceWaterfall
+// ---------------------------------------------------------------------------
+
+func TestPriceWaterfall(t *testing.T) {
+	calc := New()
+
+	// online and retail channels, 60/40 by volume
+	channels := func() []domain.SalesChannel {
+		return []domain.SalesChannel{
+			{
+				Name:        "online",
+				VolumeShare: 60,
+				Deductions: []domain.PriceDeduction{
+					{Name: "promotion", Pct: ptr(0.10)},
+					{Name: "payment_fee", Pct: ptr(0.03), Basis: "list"},
+					{Name: "returns", Pct: ptr(0.05)},
+				},
+			},
+			{
+				Name:        "retail",
+				VolumeShare: 40,
+				UnitCost:    ptr(45),
+				Deductions: []domain.PriceDeduction{
+					{Name: "channel_discount", Pct: ptr(0.35)},
+					{Name: "promotion", Amount: ptr(5)},
+					{Name: "partner_fee", Pct: ptr(0.10), Amount: ptr(1)},
+				},
+			},
+		}
+	}
+
+	type deduction struct {
+		name   string
+		amount float64
+	}
+
+	tests := []struct {
+		name           string
+		price          float64
+		financials     *domain.FinancialData
+		wantErr        bool
+		errContains    string
+		wantListPrice  float64
+		wantUnitCosts  []float64
+		wantPocket     []float64
+		wantLeakages   []float64
+		wantBlended    float64
+		wantBlendCost  float64
+		wantLeakage    float64
+		wantBest       string
+		wantWorst      string
+		wantDeductions []deduction
+	}{
+		{
+			// online: 100 -10 -3 (3% of list) -4.35 (5% of 87) = 82.65
+			// retail: 100 -35 -5 -(6+1) = 53, own unit cost 45
+			name:          "two_channels",
+			price:         100,
+			financials:    &domain.FinancialData{DirectCostPerCustomer: ptr(40), SalesChannels: channels()},
+			wantListPrice: 100,
+			wantUnitCosts: []float64{40, 45},
+			wantPocket:    []float64{82.65, 53},
+			wantLeakages:  []float64{0.1735, 0.47},
+			wantBlended:   70.79,
+			wantBlendCost: 42,
+			wantLeakage:   0.2921,
+			wantBest:      "online",
+			wantWorst:     "retail",
+			wantDeductions: []deduction{
+				{"promotion", 8}, {"payment_fee", 1.8}, {"returns", 2.61}, {"channel_discount", 14}, {"partner_fee", 2.8},
+			},
+		},
+		{
+			// retail price 120 lists both channels; online costs the landed 30 + 5,
+			// retail pays 10% + 1 on 120 - 42 - 5 = 73
+			name:  "landed_cost_and_retail_price",
+			price: 100,
+			financials: &domain.FinancialData{
+				PhysicalGoods: &domain.PhysicalGoodsData{UnitCost: ptr(30), FreightPerUnit: ptr(5), RetailPrice: ptr(120)},
+				SalesChannels: channels(),
+			},
+			wantListPrice: 120,
+			wantUnitCosts: []float64{35, 45},
+			wantPocket:    []float64{99.18, 64.7},
+			wantLeakages:  []float64{0.1735, 55.3 / 120},
+			wantBlended:   85.388,
+			wantBlendCost: 39,
+			wantLeakage:   1 - 85.388/120,
+			wantBest:      "online",
+			wantWorst:     "retail",
+			wantDeductions: []deduction{
+				{"promotion", 9.2}, {"payment_fee", 2.16}, {"returns", 3.132}, {"channel_discount", 16.8}, {"partner_fee", 3.32},
+			},
+		},
+		{
+			name:        "no_channels",
+			price:       100,
+			financials:  &domain.FinancialData{DirectCostPerCustomer: ptr(40)},
+			wantErr:     true,
+			errContains: "sales_channels required",
+		},
+		{
+			name:        "no_list_price",
+			financials:  &domain.FinancialData{DirectCostPerCustomer: ptr(40), SalesChannels: channels()},
+			wantErr:     true,
+			errContains: `channel "online": list_price`,
+		},
+		{
+			name:        "no_unit_cost",
+			price:       100,
+			financials:  &domain.FinancialData{SalesChannels: channels()},
+			wantErr:     true,
+			errContains: `channel "online": unit_cost`,
+		},
+		{
+			name:  "retail_price_only",
+			price: 100,
+			financials: &domain.FinancialData{
+				PhysicalGoods: &domain.PhysicalGoodsData{RetailPrice: ptr(120)},
+				SalesChannels: channels(),
+			},
+			wantErr:     true,
+			errContains: `channel "online": unit_cost`,
+		},
+		{
+			name:  "bad_landed_cost",
+			price: 100,
+			financials: &domain.FinancialData{
+				PhysicalGoods: &domain.PhysicalGoodsData{FreightPerUnit: ptr(5)},
+				SalesChannels: channels(),
+			},
+			wantErr:     true,
+			errContains: "landed cost: physical_goods.bill_of_materials or unit_cost required",
+		},
+		{
+			name:  "negative_share",
+			price: 100,
+			financials: &domain.FinancialData{
+				DirectCostPerCustomer: ptr(40),
+				SalesChannels:         []domain.SalesChannel{{Name: "online", VolumeShare: -1}},
+			},
+			wantErr:     true,
+			errContains: "volume_share must be non-negative",
+		},
+		{
+			name:  "zero_shares",
+			price: 100,
+			financials: &domain.FinancialData{
+				DirectCostPerCustomer: ptr(40),
+				SalesChannels:         []domain.SalesChannel{{Name: "online"}, {Name: "retail"}},
+			},
+			wantErr:     true,
+			errContains: "sum to a positive value",
+		},
+		{
+			name:  "bad_basis",
+			price: 100,
+			financials: &domain.FinancialData{
+				DirectCostPerCustomer: ptr(40),
+				SalesChannels: []domain.SalesChannel{{
+					Name:        "online",
+					VolumeShare: 1,
+					Deductions:  []domain.PriceDeduction{{Name: "promotion", Pct: ptr(0.1), Basis: "net"}},
+				}},
+			},
+			wantErr:     true,
+			errContains: "basis must be",
+		},
+		{
+			name:  "negative_pct",
+			price: 100,
+			financials: &domain.FinancialData{
+				DirectCostPerCustomer: ptr(40),
+				SalesChannels: []domain.SalesChannel{{
+					Name:        "online",
+					VolumeShare: 1,
+					Deductions:  []domain.PriceDeduction{{Name: "promotion", Pct: ptr(-0.1)}},
+				}},
+			},
+			wantErr:     true,
+			errContains: `deduction "promotion": pct must be between 0 and 1`,
+		},
+		{
+			name:  "pct_above_one",
+			price: 100,
+			financials: &domain.FinancialData{
+				DirectCostPerCustomer: ptr(40),
+				SalesChannels: []domain.SalesChannel{{
+					Name:        "online",
+					VolumeShare: 1,
+					Deductions:  []domain.PriceDeduction{{Name: "promotion", Pct: ptr(15)}},
+				}},
+			},
+			wantErr:     true,
+			errContains: `deduction "promotion": pct must be between 0 and 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &domain.AppraisalInput{Financials: tt.financials}
+			if tt.price > 0 {
+				input.Product = &domain.ProductDefinition{Price: tt.price}
+			}
+			result, err := calc.PriceWaterfall(input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.Channels) != len(tt.wantPocket) {
+				t.Fatalf("got %d channels, want %d", len(result.Channels), len(tt.wantPocket))
+			}
+			for i, ch := range result.Channels {
+				if !almostEqual(ch.ListPrice, tt.wantListPrice) || !almostEqual(ch.UnitCost, tt.wantUnitCosts[i]) {
+					t.Errorf("%s list/cost = %v/%v, want %v/%v", ch.Name, ch.ListPrice, ch.UnitCost, tt.wantListPrice, tt.wantUnitCosts[i])
+				}
+				if !almostEqual(ch.PocketPrice, tt.wantPocket[i]) || !almostEqual(ch.PocketMargin, tt.wantPocket[i]-tt.wantUnitCosts[i]) {
+					t.Errorf("%s pocket price/margin = %v/%v, want %v/%v", ch.Name, ch.PocketPrice, ch.PocketMargin, tt.wantPocket[i], tt.wantPocket[i]-tt.wantUnitCosts[i])
+				}
+				if !almostEqual(ch.Leakage, tt.wantLeakages[i]) {
+					t.Errorf("%s Leakage = %v, want %v", ch.Name, ch.Leakage, tt.wantLeakages[i])
+				}
+				if last := ch.Steps[len(ch.Steps)-1]; !almostEqual(last.PriceAfter, ch.PocketPrice) {
+					t.Errorf("%s waterfall ends at %v, want %v", ch.Name, last.PriceAfter, ch.PocketPrice)
+				}
+			}
+
+			if !almostEqual(result.BlendedPocketPrice, tt.wantBlended) || !almostEqual(result.BlendedUnitCost, tt.wantBlendCost) {
+				t.Errorf("blended pocket/cost = %v/%v, want %v/%v", result.BlendedPocketPrice, result.BlendedUnitCost, tt.wantBlended, tt.wantBlendCost)
+			}
+			if !almostEqual(result.BlendedPocketMargin, tt.wantBlended-tt.wantBlendCost) || !almostEqual(result.Leakage, tt.wantLeakage) {
+				t.Errorf("blended margin/leakage = %v/%v, want %v/%v", result.BlendedPocketMargin, result.Leakage, tt.wantBlended-tt.wantBlendCost, tt.wantLeakage)
+			}
+			if result.BestChannel != tt.wantBest || result.WorstChannel != tt.wantWorst {
+				t.Errorf("best/worst = %s/%s, want %s/%s", result.BestChannel, result.WorstChannel, tt.wantBest, tt.wantWorst)
+			}
+
+			if len(result.Deductions) != len(tt.wantDeductions) {
+				t.Fatalf("got %d blended deductions, want %d", len(result.Deductions), len(tt.wantDeductions))
+			}
+			for i, w := range tt.wantDeductions {
+				if d := result.Deductions[i]; d.Name != w.name || !almostEqual(d.Amount, w.amount) {
+					t.Errorf("deduction %d = %+v, want %s %v", i, d, w.name, w.amount)
+				}
+			}
+			if last := result.Deductions[len(result.Deductions)-1]; !almostEqual(last.PriceAfter, result.BlendedPocketPrice) {
+				t.Errorf("blended waterfall ends at %v, want %v", last.PriceAfter, result.BlendedPocketPrice)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"math"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/valuation"
 )
 
 // Calculator implements all pricing module functions.
@@ -27,25 +28,16 @@ func New() *Calculator {
 // BVR calculates Bundle Value Ratio = Sum(standalone prices) / bundle price.
 // Interpretation: <1.0 negative, 1.0-1.3 marginal, 1.3-1.5 adequate, 1.5-2.0 strong, >2.0 very strong.
 func (c *Calculator) BVR(input *domain.AppraisalInput) (*domain.BVRResult, error) {
-	if input.Product == nil {
-		return nil, fmt.Errorf("product definition required")
-	}
-	if len(input.Product.Components) == 0 {
-		return nil, fmt.Errorf("product must have components for BVR calculation")
-	}
-	if input.Product.Price <= 0 {
-		return nil, fmt.Errorf("product price must be positive")
+	bvr, standaloneSum, err := valuation.BVR(input.Product)
+	if err != nil {
+		return nil, err
 	}
 
-	standaloneSum := 0.0
 	componentValues := make(map[string]float64)
 	for _, comp := range input.Product.Components {
-		standaloneSum += comp.StandalonePrice
 		componentValues[comp.Name] = comp.StandalonePrice
 	}
 
-	bvr := standaloneSum / input.Product.Price
-
 	var interp string
 	switch {
 	case bvr < 1.0:
@@ -139,6 +131,8 @@ func (c *Calculator) TierGapAnalysis(input *domain.AppraisalInput) (*domain.Tier
 
 // CostFloor calculates the minimum viable price:
 // CostFloor = direct + partner/licensing + shared + CAC(amortized) + service + target margin.
+// For physical goods without direct_cost_per_customer, the landed unit cost
+// from financials.physical_goods is used as the direct cost.
 func (c *Calculator) CostFloor(input *domain.AppraisalInput) (*domain.CostFloorResult, error) {
 	if input.Financials == nil {
 		return nil, fmt.Errorf("financial data required")
@@ -147,29 +141,9 @@ func (c *Calculator) CostFloor(input *domain.AppraisalInput) (*domain.CostFloorR
 		return nil, fmt.Errorf("product definition required for price comparison")
 	}
 
-	f := input.Financials
-	floor := 0.0
-
-	if f.DirectCostPerCustomer != nil {
-		floor += *f.DirectCostPerCustomer
-	}
-	if f.PartnerLicensingCost != nil {
-		floor += *f.PartnerLicensingCost
-	}
-	if f.SharedCostPerCustomer != nil {
-		floor += *f.SharedCostPerCustomer
-	}
-	if f.TotalAcquisitionSpend != nil && f.NewCustomersAcquired != nil && *f.NewCustomersAcquired > 0 {
-		floor += *f.TotalAcquisitionSpend / *f.NewCustomersAcquired
-	}
-	if f.CustomerServiceCost != nil {
-		floor += *f.CustomerServiceCost
-	}
-	if f.TargetMinMargin != nil {
-		// Add margin as absolute amount: floor / (1 - margin%) - floor
-		if *f.TargetMinMargin < 1.0 {
-			floor = floor / (1.0 - *f.TargetMinMargin)
-		}
+	floor, landed, err := valuation.CostFloor(input.Financials)
+	if err != nil {
+		return nil, err
 	}
 
 	margin := input.Product.Price - floor
@@ -179,6 +153,7 @@ func (c *Calculator) CostFloor(input *domain.AppraisalInput) (*domain.CostFloorR
 		CurrentPrice: input.Product.Price,
 		Margin:       margin,
 		ClearsFloor:  input.Product.Price >= floor,
+		LandedCost:   landed,
 	}, nil
 }
 
@@ -265,24 +240,11 @@ func (c *Calculator) PremiumPriceIndex(input *domain.AppraisalInput) (*domain.Si
 // BundleDiscount calculates the effective discount percentage.
 // Discount = 1 - (bundle price / standalone sum).
 func (c *Calculator) BundleDiscount(input *domain.AppraisalInput) (*domain.SingleValueResult, error) {
-	if input.Product == nil {
-		return nil, fmt.Errorf("product definition required")
-	}
-	if len(input.Product.Components) == 0 {
-		return nil, fmt.Errorf("product must have components")
+	discount, err := valuation.BundleDiscount(input.Product)
+	if err != nil {
+		return nil, err
 	}
 
-	standaloneSum := 0.0
-	for _, comp := range input.Product.Components {
-		standaloneSum += comp.StandalonePrice
-	}
-
-	if standaloneSum <= 0 {
-		return nil, fmt.Errorf("standalone sum must be positive")
-	}
-
-	discount := 1.0 - (input.Product.Price / standaloneSum)
-
 	var interp string
 	absPct := math.Abs(discount * 100)
 	switch {
@@ -588,6 +588,24 @@ func TestCostFloor(t *testing.T) {
 			wantMargin: 0.0,
 			wantClears: true,
 		},
+		{
+			name: "physical_goods_landed_cost_as_direct_cost",
+			input: &domain.AppraisalInput{
+				Product: &domain.ProductDefinition{Price: 100.0},
+				Financials: &domain.FinancialData{
+					CustomerServiceCost: ptr(5.0),
+					PhysicalGoods: &domain.PhysicalGoodsData{
+						UnitCost:       ptr(40.0),
+						FreightPerUnit: ptr(10.0),
+						DutyRate:       ptr(0.10),
+					},
+				},
+			},
+			// landed = 40 + 10 + 0.10*50 = 55; floor = 55 + 5 = 60
+			wantFloor:  60.0,
+			wantMargin: 40.0,
+			wantClears: true,
+		},
 		// Error cases
 		{
 			name: "nil_financials",
@@ -607,6 +625,17 @@ func TestCostFloor(t *testing.T) {
 			wantErr:     true,
 			errContains: "product definition required",
 		},
+		{
+			name: "physical_goods_without_unit_cost",
+			input: &domain.AppraisalInput{
+				Product: &domain.ProductDefinition{Price: 100.0},
+				Financials: &domain.FinancialData{
+					PhysicalGoods: &domain.PhysicalGoodsData{FreightPerUnit: ptr(10.0)},
+				},
+			},
+			wantErr:     true,
+			errContains: "landed cost",
+		},
 	}
 
 	for _, tt := range tests {
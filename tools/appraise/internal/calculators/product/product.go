@@ -8,13 +8,14 @@
 //   FeatureUtilizationRate - Features used per customer / total available features
 //   ComponentActivationRate - Customers activating component / total bundle customers
 //   AttachRate             - Customers using component monthly / total bundle customers
-//   TrialConversion        - Paid conversions / trial users
+//   TrialConversion        - Paid conversions / trial users; Wilson CI, chi-square vs control arm
 package product
 
 import (
 	"fmt"
 
 	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/stats"
 )
 
 // Calculator implements all product module functions.
@@ -232,6 +233,9 @@ func (c *Calculator) AttachRate(input *domain.AppraisalInput) ([]domain.SingleVa
 
 // TrialConversion calculates paid conversions / trial users.
 // Benchmarks: self-serve 3-5%, sales-assisted 5-7%, top 8-15%.
+// The rate comes with a 95% Wilson interval; with an A/B control arm
+// (control_trial_users, control_paid_conversions) a chi-square test says
+// whether the two conversion rates differ.
 func (c *Calculator) TrialConversion(input *domain.AppraisalInput) (*domain.SingleValueResult, error) {
 	if input.Customers == nil {
 		return nil, fmt.Errorf("customer metrics required")
@@ -245,6 +249,10 @@ func (c *Calculator) TrialConversion(input *domain.AppraisalInput) (*domain.Sing
 		return nil, fmt.Errorf("trial_users must be positive")
 	}
 
+	if *m.PaidConversions < 0 || *m.PaidConversions > *m.TrialUsers {
+		return nil, fmt.Errorf("paid_conversions must be between 0 and trial_users")
+	}
+
 	rate := *m.PaidConversions / *m.TrialUsers
 
 	var interp string
@@ -259,8 +267,42 @@ func (c *Calculator) TrialConversion(input *domain.AppraisalInput) (*domain.Sing
 		interp = "below_benchmark"
 	}
 
+	lower, upper := stats.WilsonInterval(*m.PaidConversions, *m.TrialUsers, stats.DefaultConfidence)
+	sig := &domain.Significance{
+		Confidence: stats.DefaultConfidence,
+		CILower:    lower,
+		CIUpper:    upper,
+		CIMethod:   "wilson",
+	}
+	if m.ControlTrialUsers != nil || m.ControlPaidConversions != nil {
+		if m.ControlTrialUsers == nil || m.ControlPaidConversions == nil {
+			return nil, fmt.Errorf("control_trial_users and control_paid_conversions required together")
+		}
+		if *m.ControlTrialUsers <= 0 {
+			return nil, fmt.Errorf("control_trial_users must be positive")
+		}
+		if *m.ControlPaidConversions < 0 || *m.ControlPaidConversions > *m.ControlTrialUsers {
+			return nil, fmt.Errorf("control_paid_conversions must be between 0 and control_trial_users")
+		}
+		chi2, df, p, err := stats.ChiSquareTest([][]float64{
+			{*m.PaidConversions, *m.TrialUsers - *m.PaidConversions},
+			{*m.ControlPaidConversions, *m.ControlTrialUsers - *m.ControlPaidConversions},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("conversion significance: %w", err)
+		}
+		dof := float64(df)
+		sig.Test = "chi_square"
+		sig.Statistic = chi2
+		sig.DF = &dof
+		sig.PValue = &p
+		significant := p < 1-stats.DefaultConfidence
+		sig.Significant = &significant
+	}
+
 	return &domain.SingleValueResult{
 		Value:          rate,
 		Interpretation: interp,
+		Significance:   sig,
 	}, nil
 }
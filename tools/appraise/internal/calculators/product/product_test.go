@@ -1104,6 +1104,65 @@ func TestTrialConversion(t *testing.T) {
 	}
 }
 
+func TestTrialConversionSignificance(t *testing.T) {
+	calc := New()
+	trial := func(m domain.CustomerMetrics) *domain.AppraisalInput {
+		return &domain.AppraisalInput{Customers: &m}
+	}
+
+	result, err := calc.TrialConversion(trial(domain.CustomerMetrics{TrialUsers: ptr(1000), PaidConversions: ptr(60)}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sig := result.Significance
+	if sig == nil || sig.CIMethod != "wilson" || sig.Test != "" || sig.PValue != nil || sig.Significant != nil ||
+		!(sig.CILower < 0.06 && 0.06 < sig.CIUpper) || sig.CILower < 0.04 || sig.CIUpper > 0.08 {
+		t.Errorf("Significance = %+v", sig)
+	}
+
+	// 6.0% vs 4.0% control on 1000 trials each
+	result, err = calc.TrialConversion(trial(domain.CustomerMetrics{
+		TrialUsers: ptr(1000), PaidConversions: ptr(60),
+		ControlTrialUsers: ptr(1000), ControlPaidConversions: ptr(40),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sig = result.Significance
+	wantChi2 := 0.02 * 0.02 / (0.05 * 0.95 * 0.002)
+	if sig.Test != "chi_square" || !approxEqual(sig.Statistic, wantChi2, 1e-9) || sig.DF == nil || *sig.DF != 1 ||
+		sig.PValue == nil || *sig.PValue > 0.05 || sig.Significant == nil || !*sig.Significant {
+		t.Errorf("Significance = %+v, want chi2 %v", sig, wantChi2)
+	}
+
+	// Nobody converts in either arm: no difference, not an error.
+	result, err = calc.TrialConversion(trial(domain.CustomerMetrics{
+		TrialUsers: ptr(100), PaidConversions: ptr(0),
+		ControlTrialUsers: ptr(100), ControlPaidConversions: ptr(0),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sig = result.Significance; result.Value != 0 || sig.Statistic != 0 || sig.PValue == nil || *sig.PValue != 1 || sig.Significant == nil || *sig.Significant {
+		t.Errorf("Significance = %+v, want statistic 0, p 1", sig)
+	}
+	if sig.CILower != 0 {
+		t.Errorf("CILower = %v, want 0 with no conversions", sig.CILower)
+	}
+
+	errorCases := []domain.CustomerMetrics{
+		{TrialUsers: ptr(100), PaidConversions: ptr(120)},
+		{TrialUsers: ptr(100), PaidConversions: ptr(10), ControlTrialUsers: ptr(100)},
+		{TrialUsers: ptr(100), PaidConversions: ptr(10), ControlTrialUsers: ptr(100), ControlPaidConversions: ptr(-1)},
+		{TrialUsers: ptr(100), PaidConversions: ptr(10), ControlTrialUsers: ptr(0), ControlPaidConversions: ptr(0)},
+	}
+	for i, m := range errorCases {
+		if _, err := calc.TrialConversion(trial(m)); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
@@ -54,18 +54,129 @@ func (r *Registry) Functions(module string) ([]string, error) {
 // moduleFunctions lists all available functions per module.
 var moduleFunctions = map[string][]string{
 	"pricing":   {"bvr", "tier_gap", "cost_floor", "price_value_ratio", "premium_price_index", "bundle_discount"},
-	"bundle":    {"classify", "dead_weight", "cross_subsidy", "component_activation", "multi_component_usage"},
-	"financial": {"unit_economics", "gross_margin", "clv", "cac_payback", "break_even", "cannibalization", "stress_test", "incremental_revenue", "revenue_uplift"},
-	"customer":  {"churn_rate", "retention_rate", "nps", "csat", "churn_reduction", "revenue_growth", "service_revenue_share"},
+	"bundle":    {"classify", "dead_weight", "cross_subsidy", "component_activation", "multi_component_usage", "swappable", "removal_impact", "over_provisioning", "partner_cost_ratio", "cross_subsidy_stress", "engagement"},
+	"financial": {"unit_economics", "gross_margin", "clv", "cac_payback", "break_even", "cannibalization", "stress_test", "incremental_revenue", "revenue_uplift", "projection", "clv_advanced", "monte_carlo", "cannibalization_timeline", "churn_value", "tier_economics", "landed_cost", "channel_margin", "price_waterfall", "break_even_schedule", "forecast"},
+	"customer":  {"churn_rate", "retention_rate", "survival", "cohorts", "nps", "nps_survey", "csat", "reviews", "topics", "churn_reduction", "revenue_growth", "service_revenue_share"},
 	"product":   {"penetration_rate", "migration_rate", "cannibalization_rate", "cross_sell_rate", "feature_utilization", "component_activation_rate", "attach_rate", "trial_conversion"},
 	"scoring":   {"go_no_go", "risk_matrix", "dimension_score"},
 }
 
+// primaryOutputs names the headline numeric output of each function, as a
+// JSON path into its result. Functions not listed return SingleValueResult,
+// whose primary output is "value".
+var primaryOutputs = map[string]string{
+	"pricing.bvr":                        "bvr",
+	"pricing.tier_gap":                   "gaps[0].value_to_price_ratio",
+	"pricing.cost_floor":                 "margin",
+	"bundle.classify":                    "killers_count",
+	"bundle.dead_weight":                 "dead_weight_ratio",
+	"bundle.cross_subsidy":               "net_margin",
+	"bundle.component_activation":        "[0].value",
+	"bundle.swappable":                   "bvr_expected",
+	"bundle.removal_impact":              "candidates[0].bvr_delta",
+	"bundle.over_provisioning":           "wasted_cost",
+	"bundle.partner_cost_ratio":          "ratio",
+	"bundle.cross_subsidy_stress":        "stressed_net_margin",
+	"bundle.engagement":                  "components[0].monthly_active_rate",
+	"financial.unit_economics":           "margin_per_customer",
+	"financial.clv":                      "clv",
+	"financial.break_even":               "break_even_units",
+	"financial.break_even_schedule":      "max_profit",
+	"financial.cannibalization":          "net_revenue_delta",
+	"financial.stress_test":              "stressed_margin",
+	"financial.forecast":                 "total_forecast",
+	"financial.projection":               "npv",
+	"financial.clv_advanced":             "clv",
+	"financial.monte_carlo":              "probability_negative_margin",
+	"financial.cannibalization_timeline": "cumulative_net_revenue",
+	"financial.churn_value":              "retained_annual_revenue",
+	"financial.tier_economics":           "blended_margin",
+	"financial.landed_cost":              "landed_cost",
+	"financial.price_waterfall":          "blended_pocket_margin",
+	"financial.channel_margin":           "unit_contribution",
+	"customer.reviews":                   "bayesian_rating",
+	"customer.cohorts":                   "average.monthly_churn",
+	"customer.survival":                  "monthly_churn",
+	"customer.topics":                    "negative_share",
+	"customer.nps_survey":                "nps",
+	"product.component_activation_rate":  "[0].value",
+	"product.attach_rate":                "[0].value",
+	"scoring.go_no_go":                   "weighted_score",
+	"scoring.risk_matrix":                "avg_score",
+	"scoring.dimension_score":            "score",
+}
+
+// usageRateFunctions read component activation_30d or monthly_active_rate,
+// which usage_events override when present.
+var usageRateFunctions = map[string]bool{
+	"bundle.dead_weight":                true,
+	"bundle.component_activation":       true,
+	"bundle.swappable":                  true,
+	"bundle.removal_impact":             true,
+	"product.component_activation_rate": true,
+	"product.attach_rate":               true,
+}
+
+// lifespanFunctions compute CLV from average_lifespan_months, which customer
+// records supply when it is not given.
+var lifespanFunctions = map[string]bool{
+	"financial.clv":         true,
+	"financial.monte_carlo": true,
+}
+
+// withSurvivalLifespan fills a missing average_lifespan_months with the
+// lifespan estimated from customer records, on a copy. Inputs with a lifespan
+// or without records are returned as they are.
+func (r *Registry) withSurvivalLifespan(input *domain.AppraisalInput) (*domain.AppraisalInput, error) {
+	if input.Financials == nil || input.Financials.AverageLifespanMonths != nil ||
+		input.Customers == nil || len(input.Customers.CustomerRecords) == 0 {
+		return input, nil
+	}
+	survival, err := r.customer.Survival(input)
+	if err != nil {
+		return nil, fmt.Errorf("survival: %w", err)
+	}
+	if survival.AverageLifespanMonths == nil {
+		return nil, fmt.Errorf("average_lifespan_months required: no churn in customer_records")
+	}
+	in, fin := *input, *input.Financials
+	fin.AverageLifespanMonths = survival.AverageLifespanMonths
+	in.Financials = &fin
+	return &in, nil
+}
+
+// PrimaryOutput returns the JSON path of a function's headline numeric output.
+func (r *Registry) PrimaryOutput(module, function string) string {
+	if path, ok := primaryOutputs[module+"."+function]; ok {
+		return path
+	}
+	return "value"
+}
+
 // Execute runs a calculation by module and function name.
 // Returns the result as a JSON-serializable interface{}.
 func (r *Registry) Execute(module, function string, input *domain.AppraisalInput) (interface{}, error) {
 	key := module + "." + function
 
+	// Raw usage events take precedence over pre-aggregated component rates:
+	// derive them first, on a copy, for the functions that read those rates.
+	if input.UsageEvents != nil && usageRateFunctions[key] {
+		engagement, err := r.bundle.Engagement(input)
+		if err != nil {
+			return nil, fmt.Errorf("usage_events: %w", err)
+		}
+		input = bundle.WithEngagement(input, engagement.Components)
+	}
+
+	// Without average_lifespan_months, CLV uses the lifespan estimated from
+	// customer records, again on a copy.
+	if lifespanFunctions[key] {
+		var err error
+		if input, err = r.withSurvivalLifespan(input); err != nil {
+			return nil, err
+		}
+	}
+
 	switch key {
 	// Pricing module
 	case "pricing.bvr":
@@ -92,6 +203,18 @@ func (r *Registry) Execute(module, function string, input *domain.AppraisalInput
 		return r.bundle.ComponentActivation(input)
 	case "bundle.multi_component_usage":
 		return r.bundle.MultiComponentUsage(input)
+	case "bundle.swappable":
+		return r.bundle.Swappable(input)
+	case "bundle.removal_impact":
+		return r.bundle.RemovalImpact(input)
+	case "bundle.over_provisioning":
+		return r.bundle.OverProvisioning(input)
+	case "bundle.partner_cost_ratio":
+		return r.bundle.PartnerCostRatio(input)
+	case "bundle.cross_subsidy_stress":
+		return r.bundle.CrossSubsidyStress(input)
+	case "bundle.engagement":
+		return r.bundle.Engagement(input)
 
 	// Financial module
 	case "financial.unit_economics":
@@ -112,6 +235,28 @@ func (r *Registry) Execute(module, function string, input *domain.AppraisalInput
 		return r.financial.IncrementalRevenue(input)
 	case "financial.revenue_uplift":
 		return r.financial.RevenueUplift(input)
+	case "financial.projection":
+		return r.financial.Projection(input)
+	case "financial.clv_advanced":
+		return r.financial.CLVAdvanced(input)
+	case "financial.monte_carlo":
+		return r.financial.MonteCarlo(input)
+	case "financial.cannibalization_timeline":
+		return r.financial.CannibalizationTimeline(input)
+	case "financial.churn_value":
+		return r.financial.ChurnValue(input)
+	case "financial.tier_economics":
+		return r.financial.TierEconomics(input)
+	case "financial.landed_cost":
+		return r.financial.LandedCost(input)
+	case "financial.channel_margin":
+		return r.financial.ChannelMargin(input)
+	case "financial.price_waterfall":
+		return r.financial.PriceWaterfall(input)
+	case "financial.break_even_schedule":
+		return r.financial.BreakEvenSchedule(input)
+	case "financial.forecast":
+		return r.financial.Forecast(input)
 
 	// Customer module
 	case "customer.churn_rate":
@@ -120,8 +265,18 @@ func (r *Registry) Execute(module, function string, input *domain.AppraisalInput
 		return r.customer.RetentionRate(input)
 	case "customer.nps":
 		return r.customer.NPS(input)
+	case "customer.nps_survey":
+		return r.customer.NPSSurvey(input)
 	case "customer.csat":
 		return r.customer.CSAT(input)
+	case "customer.reviews":
+		return r.customer.Reviews(input)
+	case "customer.cohorts":
+		return r.customer.Cohorts(input)
+	case "customer.survival":
+		return r.customer.Survival(input)
+	case "customer.topics":
+		return r.customer.Topics(input)
 	case "customer.churn_reduction":
 		return r.customer.ChurnReductionImpact(input)
 	case "customer.revenue_growth":
@@ -0,0 +1,100 @@
+package calculators
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func ptr(v float64) *float64  { return &v }
+func strPtr(s string) *string { return &s }
+
+func TestExecuteCLVLifespan(t *testing.T) {
+	// 365 + 182.5 observed days to 2024-01-01, one churned
+	records := []domain.CustomerRecord{
+		{StartDate: "2023-01-01", EndDate: strPtr("2024-01-01")},
+		{StartDate: "2023-07-02T12:00:00Z"},
+	}
+
+	tests := []struct {
+		name     string
+		lifespan *float64
+		records  []domain.CustomerRecord
+		wantLife float64
+		wantErr  string
+	}{
+		{"from customer records", nil, records, 547.5 / (365.25 / 12), ""},
+		{"explicit lifespan wins", ptr(24), records, 24, ""},
+		{"records without churn", nil, records[1:], 0, "no churn in customer_records"},
+		{"bad records", nil, []domain.CustomerRecord{{StartDate: "soon"}}, 0, "survival: "},
+		{"no lifespan source", nil, nil, 0, "average_lifespan_months required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &domain.AppraisalInput{
+				Financials: &domain.FinancialData{
+					RevenuePerCustomer:    ptr(100),
+					GrossMarginPct:        ptr(0.5),
+					AverageLifespanMonths: tt.lifespan,
+				},
+				Customers: &domain.CustomerMetrics{CustomerRecords: tt.records},
+			}
+			out, err := NewRegistry().Execute("financial", "clv", input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result := out.(*domain.CLVResult)
+			if math.Abs(result.LifespanMonths-tt.wantLife) > 1e-9 || math.Abs(result.CLV-50*tt.wantLife) > 1e-9 {
+				t.Errorf("lifespan/CLV = %v/%v, want %v/%v", result.LifespanMonths, result.CLV, tt.wantLife, 50*tt.wantLife)
+			}
+			if (input.Financials.AverageLifespanMonths == nil) != (tt.lifespan == nil) {
+				t.Error("Execute modified the input")
+			}
+		})
+	}
+}
+
+func TestExecuteMonteCarloLifespan(t *testing.T) {
+	// Same records as above: the simulated CLV uses the survival lifespan,
+	// as financial.clv does.
+	records := []domain.CustomerRecord{
+		{StartDate: "2023-01-01", EndDate: strPtr("2024-01-01")},
+		{StartDate: "2023-07-02T12:00:00Z"},
+	}
+	iterations := 50
+	input := &domain.AppraisalInput{
+		Financials: &domain.FinancialData{
+			RevenuePerCustomer: ptr(100),
+			GrossMarginPct:     ptr(0.5),
+			MonteCarlo: &domain.MonteCarloConfig{
+				Iterations: &iterations,
+				Distributions: []domain.InputDistribution{
+					{Field: "financials.direct_cost_per_customer", Type: "uniform", Min: ptr(10), Max: ptr(20)},
+				},
+			},
+		},
+		Customers: &domain.CustomerMetrics{CustomerRecords: records},
+	}
+
+	out, err := NewRegistry().Execute("financial", "monte_carlo", input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantCLV := 50 * 547.5 / (365.25 / 12)
+	clv := out.(*domain.MonteCarloResult).CLV
+	if clv == nil || math.Abs(clv.Min-wantCLV) > 1e-9 || math.Abs(clv.Max-wantCLV) > 1e-9 {
+		t.Errorf("CLV = %+v, want %v", clv, wantCLV)
+	}
+	if input.Financials.AverageLifespanMonths != nil {
+		t.Error("Execute modified the input")
+	}
+}
@@ -0,0 +1,23 @@
+// Package dates parses the dates and timestamps of the appraisal input, so
+// every calculator accepts the same formats.
+package dates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// layouts are the accepted formats, most specific first.
+var layouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// Parse parses an RFC 3339 timestamp or a plain date, in UTC.
+func Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use RFC 3339 or YYYY-MM-DD)", s)
+}
@@ -11,14 +11,24 @@ package domain
 // An agent (or human) populates the relevant sections and passes the JSON
 // to any calculator module.
 type AppraisalInput struct {
-	Product     *ProductDefinition  `json:"product,omitempty"`
-	Tiers       []TierDefinition    `json:"tiers,omitempty"`
-	Competitors []CompetitorData    `json:"competitors,omitempty"`
-	Customers   *CustomerMetrics    `json:"customers,omitempty"`
-	Financials  *FinancialData      `json:"financials,omitempty"`
-	Market      *MarketContext      `json:"market,omitempty"`
-	Components  []ComponentData     `json:"components,omitempty"`
-	Scoring     *ScoringInput       `json:"scoring,omitempty"`
+	Product     *ProductDefinition `json:"product,omitempty"`
+	Tiers       []TierDefinition   `json:"tiers,omitempty"`
+	Competitors []CompetitorData   `json:"competitors,omitempty"`
+	Customers   *CustomerMetrics   `json:"customers,omitempty"`
+	Financials  *FinancialData     `json:"financials,omitempty"`
+	Market      *MarketContext     `json:"market,omitempty"`
+	Components  []ComponentData    `json:"components,omitempty"`
+	Scoring     *ScoringInput      `json:"scoring,omitempty"`
+	UsageEvents *UsageEventData    `json:"usage_events,omitempty"`
+	Scenarios   []Scenario         `json:"scenarios,omitempty"` // named what-if cases over this input
+}
+
+// Scenario is a named variant of the input, e.g. "optimistic". Patch is a
+// JSON merge patch (RFC 7386) applied over the base AppraisalInput: objects
+// merge recursively, null removes a field, and arrays are replaced whole.
+type Scenario struct {
+	Name  string                 `json:"name"`
+	Patch map[string]interface{} `json:"patch"`
 }
 
 // ---------------------------------------------------------------------------
@@ -27,13 +37,15 @@ type AppraisalInput struct {
 
 // ProductDefinition describes the product or bundle being evaluated.
 type ProductDefinition struct {
-	Name        string      `json:"name"`
-	Description *string     `json:"description,omitempty"`
-	Price       float64     `json:"price"`
-	Currency    *string     `json:"currency,omitempty"`
-	Components  []Component `json:"components,omitempty"`
-	Features    []Feature   `json:"features,omitempty"`
-	Category    *string     `json:"category,omitempty"`
+	Name        string         `json:"name"`
+	Description *string        `json:"description,omitempty"`
+	Price       float64        `json:"price"`
+	Currency    *string        `json:"currency,omitempty"`
+	Components  []Component    `json:"components,omitempty"`
+	Features    []Feature      `json:"features,omitempty"`
+	Category    *string        `json:"category,omitempty"`
+	SwapPicks   *int           `json:"swap_picks,omitempty"` // K in a "pick K of N" swappable layer
+	Reviews     []ReviewSource `json:"reviews,omitempty"`
 }
 
 // Component is a single element within a bundle.
@@ -48,6 +60,24 @@ type Component struct {
 	IsSwappable     *bool    `json:"is_swappable,omitempty"`
 }
 
+// ReviewSource is customer review data from one source (marketplace, app
+// store, review site) on a 1-5 star scale: a histogram of counts per star,
+// individual reviews, or only the published average and count. Histogram
+// and reviews are pooled when both are given.
+type ReviewSource struct {
+	Source        string    `json:"source"`
+	Histogram     []float64 `json:"histogram,omitempty"` // counts for 1..5 stars
+	Reviews       []Review  `json:"reviews,omitempty"`
+	AverageRating *float64  `json:"average_rating,omitempty"` // aggregate-only sources
+	ReviewCount   *float64  `json:"review_count,omitempty"`
+}
+
+// Review is a single customer review.
+type Review struct {
+	Rating float64 `json:"rating"` // 1-5; 0 for text-only reviews
+	Text   string  `json:"text,omitempty"`
+}
+
 // Feature describes a discrete product capability used in tier/competitive comparisons.
 type Feature struct {
 	Name        string  `json:"name"`
@@ -62,12 +92,13 @@ type Feature struct {
 
 // TierDefinition represents one tier in a multi-tier product line.
 type TierDefinition struct {
-	Name           string    `json:"name"`                      // e.g. "entry", "middle", "premium"
-	Level          int       `json:"level"`                     // ordinal: 1=entry, 2=middle, 3=premium
-	Price          float64   `json:"price"`
-	Features       []Feature `json:"features,omitempty"`
-	PerceivedValue *float64  `json:"perceived_value,omitempty"` // aggregate perceived value score
-	CustomerShare  *float64  `json:"customer_share,omitempty"`  // actual or expected % of customers
+	Name            string    `json:"name"`  // e.g. "entry", "middle", "premium"
+	Level           int       `json:"level"` // ordinal: 1=entry, 2=middle, 3=premium
+	Price           float64   `json:"price"`
+	Features        []Feature `json:"features,omitempty"`
+	PerceivedValue  *float64  `json:"perceived_value,omitempty"`   // aggregate perceived value score
+	CustomerShare   *float64  `json:"customer_share,omitempty"`    // actual or expected % of customers
+	CostPerCustomer *float64  `json:"cost_per_customer,omitempty"` // variable cost per customer, same period as price
 }
 
 // ---------------------------------------------------------------------------
@@ -76,13 +107,14 @@ type TierDefinition struct {
 
 // CompetitorData captures a competing product for feature-by-feature comparison.
 type CompetitorData struct {
-	Name       string    `json:"name"`
-	Provider   *string   `json:"provider,omitempty"`
-	Price      float64   `json:"price"`
-	Currency   *string   `json:"currency,omitempty"`
-	Features   []Feature `json:"features,omitempty"`
-	Components []Component `json:"components,omitempty"`
-	BVR        *float64  `json:"bvr,omitempty"` // pre-calculated or to be computed
+	Name       string         `json:"name"`
+	Provider   *string        `json:"provider,omitempty"`
+	Price      float64        `json:"price"`
+	Currency   *string        `json:"currency,omitempty"`
+	Features   []Feature      `json:"features,omitempty"`
+	Components []Component    `json:"components,omitempty"`
+	BVR        *float64       `json:"bvr,omitempty"` // pre-calculated or to be computed
+	Reviews    []ReviewSource `json:"reviews,omitempty"`
 }
 
 // ---------------------------------------------------------------------------
@@ -104,10 +136,34 @@ type CustomerMetrics struct {
 	ChurnBefore           *float64 `json:"churn_before,omitempty"` // pre-launch churn
 	ChurnAfter            *float64 `json:"churn_after,omitempty"`  // post-launch churn
 
+	// Churn reduction significance: customers at risk in each period
+	CustomersBefore *float64 `json:"customers_before,omitempty"`
+	CustomersAfter  *float64 `json:"customers_after,omitempty"`
+
+	// Survival: one record per customer, censored at survival_as_of if still active
+	CustomerRecords []CustomerRecord `json:"customer_records,omitempty"`
+	SurvivalAsOf    *string          `json:"survival_as_of,omitempty"` // YYYY-MM-DD; default latest date in records
+
+	// Cohorts: one record per customer per active month
+	CustomerActivity []CustomerMonth `json:"customer_activity,omitempty"`
+
 	// NPS
 	PromotersPct          *float64 `json:"promoters_pct,omitempty"`   // % scoring 9-10
 	DetractorsPct         *float64 `json:"detractors_pct,omitempty"`  // % scoring 0-6
 
+	// NPS survey: raw 0-10 scores, tier names for the premium-vs-base test
+	NPSResponses   []NPSResponse `json:"nps_responses,omitempty"`
+	NPSPremiumTier *string       `json:"nps_premium_tier,omitempty"` // default "premium"
+	NPSBaseTier    *string       `json:"nps_base_tier,omitempty"`    // default "base"
+
+	// Review ratings: Bayesian prior, defaults to the pooled mean and 10 reviews
+	ReviewPriorMean   *float64 `json:"review_prior_mean,omitempty"`
+	ReviewPriorWeight *float64 `json:"review_prior_weight,omitempty"`
+
+	// Review topics: keyword dictionary and optional sentiment lexicon
+	Topics           []TopicDefinition `json:"topics,omitempty"`
+	SentimentLexicon *SentimentLexicon `json:"sentiment_lexicon,omitempty"`
+
 	// CSAT
 	SatisfiedResponses    *float64 `json:"satisfied_responses,omitempty"`
 	TotalResponses        *float64 `json:"total_responses,omitempty"`
@@ -115,6 +171,8 @@ type CustomerMetrics struct {
 	// Conversion and migration
 	TrialUsers            *float64 `json:"trial_users,omitempty"`
 	PaidConversions       *float64 `json:"paid_conversions,omitempty"`
+	ControlTrialUsers     *float64 `json:"control_trial_users,omitempty"` // A/B control arm
+	ControlPaidConversions *float64 `json:"control_paid_conversions,omitempty"`
 	EligibleBase          *float64 `json:"eligible_base,omitempty"`
 	UpgradedCustomers     *float64 `json:"upgraded_customers,omitempty"`
 	MigratedFromStandalone *float64 `json:"migrated_from_standalone,omitempty"`
@@ -132,6 +190,48 @@ type CustomerMetrics struct {
 	TotalRevenue          *float64 `json:"total_revenue,omitempty"`
 }
 
+// NPSResponse is one 0-10 survey answer, optionally tagged for breakdowns.
+type NPSResponse struct {
+	Score   int    `json:"score"`
+	Segment string `json:"segment,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	Region  string `json:"region,omitempty"`
+}
+
+// CustomerRecord is one customer's lifetime. A record without an end date
+// is an active customer, censored at the survival as-of date.
+type CustomerRecord struct {
+	ID        string  `json:"id,omitempty"`
+	StartDate string  `json:"start_date"`         // RFC 3339 or YYYY-MM-DD
+	EndDate   *string `json:"end_date,omitempty"` // churn date; nil = still active
+	Tier      string  `json:"tier,omitempty"`
+	Bundled   *bool   `json:"bundled,omitempty"` // bundle vs standalone; nil = untagged
+}
+
+// CustomerMonth records a customer as active in a calendar month, with the
+// revenue billed that month. Revenue is all-or-nothing across records.
+type CustomerMonth struct {
+	Customer string   `json:"customer"`
+	Month    string   `json:"month"` // YYYY-MM
+	Revenue  *float64 `json:"revenue,omitempty"`
+}
+
+// TopicDefinition is one topic of the review dictionary. Keywords match
+// whole words or phrases, case-insensitively; a trailing * matches any word
+// with that prefix ("batter*").
+type TopicDefinition struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+}
+
+// SentimentLexicon lists the words that score a sentence positive or
+// negative. Lists given here replace the built-in English ones.
+type SentimentLexicon struct {
+	Positive []string `json:"positive,omitempty"`
+	Negative []string `json:"negative,omitempty"`
+	Negators []string `json:"negators,omitempty"` // flip the next two words
+}
+
 // ---------------------------------------------------------------------------
 // Financial data
 // ---------------------------------------------------------------------------
@@ -147,6 +247,11 @@ type FinancialData struct {
 	RevenuePreLaunch       *float64 `json:"revenue_pre_launch,omitempty"`
 	RevenuePostLaunch      *float64 `json:"revenue_post_launch,omitempty"`
 
+	// Revenue uplift significance: per-customer revenue in each tier, in
+	// place of premium_revenue/base_revenue
+	PremiumRevenueSamples []float64 `json:"premium_revenue_samples,omitempty"`
+	BaseRevenueSamples    []float64 `json:"base_revenue_samples,omitempty"`
+
 	// Costs
 	COGS                   *float64 `json:"cogs,omitempty"`
 	DirectCostPerCustomer  *float64 `json:"direct_cost_per_customer,omitempty"`
@@ -174,9 +279,248 @@ type FinancialData struct {
 	NewPremiumCustomers    *float64 `json:"new_premium_customers,omitempty"`
 	NewPremiumRevenue      *float64 `json:"new_premium_revenue,omitempty"` // per customer
 
+	// Break-even schedule: step fixed costs and tiered unit costs by volume
+	CostSchedule *CostSchedule `json:"cost_schedule,omitempty"`
+
+	// Physical goods: landed cost, channel margins, working capital
+	PhysicalGoods *PhysicalGoodsData `json:"physical_goods,omitempty"`
+
+	// Price waterfall: list-to-pocket price deductions per sales channel
+	SalesChannels []SalesChannel `json:"sales_channels,omitempty"`
+
+	// Tier economics: mix shifts to evaluate against the current tier mix
+	TierMixShifts []TierMixShift `json:"tier_mix_shifts,omitempty"`
+
+	// Cannibalization over time
+	CannibalizationTimeline *CannibalizationTimeline `json:"cannibalization_timeline,omitempty"`
+
 	// Stress test parameters
 	CostIncreasePct        *float64 `json:"cost_increase_pct,omitempty"`  // e.g. 0.20 for +20%
 	GrowthDecreasePct      *float64 `json:"growth_decrease_pct,omitempty"` // e.g. 0.30 for -30%
+
+	// Multi-period projection
+	Projection *ProjectionData `json:"projection,omitempty"`
+
+	// Discounted, churn-aware CLV
+	CLVModel *CLVModel `json:"clv_model,omitempty"`
+
+	// Monte Carlo stress test
+	MonteCarlo *MonteCarloConfig `json:"monte_carlo,omitempty"`
+
+	// Forecast from a historical monthly series
+	Forecast *ForecastData `json:"forecast,omitempty"`
+}
+
+// ForecastData is a monthly history (oldest first) to extend with an
+// additive Holt-Winters model. Smoothing parameters left unset are fitted
+// by minimizing one-step-ahead squared error.
+type ForecastData struct {
+	Series       []float64 `json:"series"`
+	Metric       string    `json:"metric,omitempty"`        // label, e.g. "revenue" or "customers"
+	Horizon      *int      `json:"horizon,omitempty"`       // months ahead, 12-36, default 12
+	SeasonLength *int      `json:"season_length,omitempty"` // default 12; 0 or 1 for no seasonality
+	Alpha        *float64  `json:"alpha,omitempty"`         // level smoothing
+	Beta         *float64  `json:"beta,omitempty"`          // trend smoothing
+	Gamma        *float64  `json:"gamma,omitempty"`         // seasonal smoothing
+	Confidence   *float64  `json:"confidence,omitempty"`    // prediction interval, default 0.95
+}
+
+// ProjectionData drives the month-by-month cash-flow projection.
+// Price and per-customer cost default to product.price and
+// direct_cost_per_customer; schedules override them from a given month on.
+type ProjectionData struct {
+	Months            *int             `json:"months,omitempty"`            // default 36
+	LaunchInvestment  *float64         `json:"launch_investment,omitempty"` // month-0 outflow
+	DiscountRate      *float64         `json:"discount_rate,omitempty"`     // annual, default 0.10
+	StartingCustomers *float64         `json:"starting_customers,omitempty"`
+	NewCustomers      []float64        `json:"new_customers,omitempty"`      // monthly ramp; last value repeats
+	MonthlyChurn      *float64         `json:"monthly_churn,omitempty"`      // as decimal
+	PricePerCustomer  *float64         `json:"price_per_customer,omitempty"` // monthly
+	PriceChanges      []ScheduledValue `json:"price_changes,omitempty"`
+	CostPerCustomer   *float64         `json:"cost_per_customer,omitempty"` // monthly variable cost
+	CostChanges       []ScheduledValue `json:"cost_changes,omitempty"`
+	FixedCostsMonthly *float64         `json:"fixed_costs_monthly,omitempty"`
+	CACPerCustomer    *float64         `json:"cac_per_customer,omitempty"`
+}
+
+// CLVModel parameterizes the discounted CLV. Retention comes from a monthly
+// churn rate or an observed retention curve; revenue_per_customer and
+// gross_margin_pct come from FinancialData.
+type CLVModel struct {
+	MonthlyChurn     *float64    `json:"monthly_churn,omitempty"`
+	RetentionCurve   []float64   `json:"retention_curve,omitempty"`   // share of cohort active after month 1, 2, ...
+	DiscountRate     *float64    `json:"discount_rate,omitempty"`     // annual, default 0.10
+	ARPUGrowth       *float64    `json:"arpu_growth,omitempty"`       // annual ARPU growth, as decimal
+	ExpansionRevenue *float64    `json:"expansion_revenue,omitempty"` // extra monthly revenue per retained customer
+	HorizonMonths    *int        `json:"horizon_months,omitempty"`    // default 60
+	Cohorts          []CLVCohort `json:"cohorts,omitempty"`
+}
+
+// CLVCohort overrides the model's retention, revenue, or acquisition cost for
+// one acquisition cohort. Unset fields inherit from the model.
+type CLVCohort struct {
+	Name               string    `json:"name"`
+	Customers          *float64  `json:"customers,omitempty"`
+	MonthlyChurn       *float64  `json:"monthly_churn,omitempty"`
+	RetentionCurve     []float64 `json:"retention_curve,omitempty"`
+	RevenuePerCustomer *float64  `json:"revenue_per_customer,omitempty"`
+	AcquisitionCost    *float64  `json:"acquisition_cost,omitempty"` // CAC per customer
+}
+
+// CostSchedule describes costs that change with volume, on top of
+// fixed_costs and variable_cost_per_unit. Volume is in units (or customers).
+type CostSchedule struct {
+	MaxVolume     float64        `json:"max_volume"` // upper end of the analysed range
+	StepCosts     []StepCost     `json:"step_costs,omitempty"`
+	UnitCostTiers []UnitCostTier `json:"unit_cost_tiers,omitempty"` // ascending from
+	UnitCostMode  string         `json:"unit_cost_mode,omitempty"`  // "all_units" (default) or "graduated"
+	CurvePoints   *int           `json:"curve_points,omitempty"`    // default 20
+}
+
+// StepCost is a fixed cost that rises with volume: either Cost for every
+// started block of Every units (a support team per N customers), or the cost
+// of the highest bracket reached (a tiered licence fee).
+type StepCost struct {
+	Name     string        `json:"name"`
+	Cost     *float64      `json:"cost,omitempty"`
+	Every    *float64      `json:"every,omitempty"`
+	Brackets []CostBracket `json:"brackets,omitempty"` // ascending from
+}
+
+// CostBracket is the cost that applies from a volume onward.
+type CostBracket struct {
+	From float64 `json:"from"`
+	Cost float64 `json:"cost"`
+}
+
+// UnitCostTier is the variable cost per unit from a volume onward. Below the
+// first tier, variable_cost_per_unit applies.
+type UnitCostTier struct {
+	From     float64 `json:"from"`
+	UnitCost float64 `json:"unit_cost"`
+}
+
+// SalesChannel is one route to market in the price waterfall. Deductions are
+// applied in order; volume shares are normalized across channels.
+type SalesChannel struct {
+	Name        string           `json:"name"`
+	VolumeShare float64          `json:"volume_share"`
+	ListPrice   *float64         `json:"list_price,omitempty"` // default product.price
+	Deductions  []PriceDeduction `json:"deductions,omitempty"`
+	UnitCost    *float64         `json:"unit_cost,omitempty"` // default direct cost or landed cost
+}
+
+// PriceDeduction is one step of the price waterfall: a channel discount,
+// promotion, payment fee, returns allowance, tax or partner fee. Pct applies
+// to the price after earlier deductions, or to the list price when basis is
+// "list"; Amount is a fixed per-unit deduction. Both may be set.
+type PriceDeduction struct {
+	Name   string   `json:"name"`
+	Pct    *float64 `json:"pct,omitempty"` // as decimal, 0-1
+	Amount *float64 `json:"amount,omitempty"`
+	Basis  string   `json:"basis,omitempty"` // "running" (default) or "list"
+}
+
+// PhysicalGoodsData is the per-unit cost and channel model of a physical
+// product. Unit cost comes from the bill of materials or unit_cost; landed
+// cost adds freight, duty on the customs value (goods + freight), and other
+// per-unit import costs. Channel margins are listed from the end customer
+// inward (retailer first), each as a share of that channel's selling price.
+type PhysicalGoodsData struct {
+	BillOfMaterials []BOMItem `json:"bill_of_materials,omitempty"`
+	UnitCost        *float64  `json:"unit_cost,omitempty"`      // ex-works cost when no BOM is given
+	AssemblyCost    *float64  `json:"assembly_cost,omitempty"`  // per unit
+	PackagingCost   *float64  `json:"packaging_cost,omitempty"` // per unit
+	FreightPerUnit  *float64  `json:"freight_per_unit,omitempty"`
+	DutyRate        *float64  `json:"duty_rate,omitempty"`         // as decimal, on goods + freight
+	OtherLandedCost *float64  `json:"other_landed_cost,omitempty"` // brokerage, handling, per unit
+
+	RetailPrice        *float64        `json:"retail_price,omitempty"` // default product.price
+	ChannelMargins     []ChannelMargin `json:"channel_margins,omitempty"`
+	ReturnRate         *float64        `json:"return_rate,omitempty"`          // share of units sold that come back
+	ReturnCost         *float64        `json:"return_cost,omitempty"`          // handling cost per returned unit
+	ReturnRecoveryRate *float64        `json:"return_recovery_rate,omitempty"` // share of a returned unit's landed cost recovered
+	WarrantyReservePct *float64        `json:"warranty_reserve_pct,omitempty"` // of net revenue
+
+	AnnualUnits             *float64 `json:"annual_units,omitempty"`
+	InventoryTurns          *float64 `json:"inventory_turns,omitempty"` // per year
+	DaysSalesOutstanding    *float64 `json:"days_sales_outstanding,omitempty"`
+	DaysPayablesOutstanding *float64 `json:"days_payables_outstanding,omitempty"`
+}
+
+// BOMItem is one line of the bill of materials.
+type BOMItem struct {
+	Name     string   `json:"name"`
+	UnitCost float64  `json:"unit_cost"`
+	Quantity *float64 `json:"quantity,omitempty"` // default 1
+}
+
+// ChannelMargin is the margin one channel partner keeps on its selling price.
+type ChannelMargin struct {
+	Name   string  `json:"name"`   // e.g. "retailer", "distributor"
+	Margin float64 `json:"margin"` // as decimal of the partner's selling price
+}
+
+// TierMixShift moves a fraction of one tier's customers to another tier,
+// e.g. {from: "middle", to: "premium", share: 0.10}.
+type TierMixShift struct {
+	From  string  `json:"from"`
+	To    string  `json:"to"`
+	Share float64 `json:"share"` // fraction of the from tier's customers that move
+}
+
+// CannibalizationTimeline models migration from standalone products to the
+// bundle month by month, against a no-bundle baseline in which the
+// standalone base keeps churning at its own rate. Revenue defaults come from
+// the static cannibalization fields; churn defaults from customers.
+type CannibalizationTimeline struct {
+	Months              *int      `json:"months,omitempty"`               // default 24
+	StandaloneCustomers *float64  `json:"standalone_customers,omitempty"` // standalone base at launch
+	StandaloneRevenue   *float64  `json:"standalone_revenue,omitempty"`   // monthly per customer; default migrated_customer_old_revenue
+	BundleRevenue       *float64  `json:"bundle_revenue,omitempty"`       // monthly per customer; default migrated_customer_new_revenue
+	MigrationRates      []float64 `json:"migration_rates,omitempty"`      // monthly share of remaining standalone base migrating; last value repeats
+	NewBundleCustomers  []float64 `json:"new_bundle_customers,omitempty"` // monthly new premium acquisitions; last value repeats
+	StandaloneChurn     *float64  `json:"standalone_churn,omitempty"`     // monthly; default customers.base_churn_rate
+	BundleChurn         *float64  `json:"bundle_churn,omitempty"`         // monthly; default: standalone churn less churn_reduction, else customers.premium_churn_rate
+	ChurnReduction      *float64  `json:"churn_reduction,omitempty"`      // relative, e.g. 0.30 = bundle churns 30% less
+}
+
+// MonteCarloConfig describes uncertain inputs for the Monte Carlo stress test.
+// Each distribution targets a numeric field by JSON path under financials or
+// customers (e.g. "financials.cogs", "customers.base_churn_rate").
+type MonteCarloConfig struct {
+	Iterations    *int                `json:"iterations,omitempty"` // default 10000
+	Seed          *uint64             `json:"seed,omitempty"`       // default 1
+	Distributions []InputDistribution `json:"distributions"`
+	Correlations  []InputCorrelation  `json:"correlations,omitempty"`
+}
+
+// InputDistribution is the sampling distribution of one input field.
+// normal: mean, std_dev. lognormal: mean, std_dev of the value itself.
+// uniform: min, max. triangular: min, mode, max.
+// min/max also clamp normal and lognormal draws when set.
+type InputDistribution struct {
+	Field  string   `json:"field"`
+	Type   string   `json:"type"` // normal, triangular, uniform, lognormal
+	Mean   *float64 `json:"mean,omitempty"`
+	StdDev *float64 `json:"std_dev,omitempty"`
+	Min    *float64 `json:"min,omitempty"`
+	Mode   *float64 `json:"mode,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+}
+
+// InputCorrelation is the rank-style correlation between two sampled fields,
+// applied through a Gaussian copula.
+type InputCorrelation struct {
+	A           string  `json:"a"`
+	B           string  `json:"b"`
+	Coefficient float64 `json:"coefficient"` // -1 to 1
+}
+
+// ScheduledValue sets a value effective from Month (1-based) onward.
+type ScheduledValue struct {
+	Month int     `json:"month"`
+	Value float64 `json:"value"`
 }
 
 // ---------------------------------------------------------------------------
@@ -214,6 +558,43 @@ type ComponentData struct {
 	RevenueContrib    *float64 `json:"revenue_contribution,omitempty"`
 	DirectCost        *float64 `json:"direct_cost,omitempty"`
 	Category          *string  `json:"category,omitempty"`
+	IsSwappable       *bool    `json:"is_swappable,omitempty"`     // part of the customer-chosen layer
+	RemovalPriceDelta *float64 `json:"removal_price_delta,omitempty"` // bundle price change if removed
+	Partner           *string  `json:"partner,omitempty"`             // third party supplying/licensing the component
+	UsageShock        *float64 `json:"usage_shock,omitempty"`         // stress: usage change, scales cost (1.0 = 2x usage)
+	PriceShock        *float64 `json:"price_shock,omitempty"`         // stress: revenue change (-0.2 = lose 20% pricing power)
+
+	// Usage-capped allowance (over-provisioning analysis)
+	Allowance           *float64  `json:"allowance,omitempty"`            // included units per customer per period
+	UnitCost            *float64  `json:"unit_cost,omitempty"`            // cost per provisioned unit
+	UsageDistribution   []float64 `json:"usage_distribution,omitempty"`   // observed units used, one per customer
+	AllowancePercentile *float64  `json:"allowance_percentile,omitempty"` // right-sizing target, default 0.90
+}
+
+// UsageEventData holds raw component usage events (customer, component, timestamp).
+// Events come inline, from a CSV/JSON Lines file, or both. When present, the
+// engagement calculator derives activation_30d and monthly_active_rate per component.
+type UsageEventData struct {
+	File           *string         `json:"file,omitempty"`            // path to .csv or .jsonl, relative to the input file
+	Format         *string         `json:"format,omitempty"`          // "csv" | "jsonl"; default from file extension
+	Events         []UsageEvent    `json:"events,omitempty"`          // inline events
+	Customers      []UsageCustomer `json:"customers,omitempty"`       // bundle customers and start dates
+	TotalCustomers *float64        `json:"total_customers,omitempty"` // denominator when customers list is absent
+	PeriodStart    *string         `json:"period_start,omitempty"`    // YYYY-MM-DD; default first event
+	PeriodEnd      *string         `json:"period_end,omitempty"`      // YYYY-MM-DD; default last event
+}
+
+// UsageEvent is a single use of a bundle component by a customer.
+type UsageEvent struct {
+	Customer  string `json:"customer"`
+	Component string `json:"component"`
+	Timestamp string `json:"timestamp"` // RFC 3339 or YYYY-MM-DD
+}
+
+// UsageCustomer is a bundle customer with the date their bundle started.
+type UsageCustomer struct {
+	ID        string  `json:"id"`
+	StartDate *string `json:"start_date,omitempty"` // default: period start
 }
 
 // ---------------------------------------------------------------------------
@@ -296,10 +677,11 @@ type TierGap struct {
 
 // CostFloorResult holds cost floor calculation output.
 type CostFloorResult struct {
-	CostFloor      float64 `json:"cost_floor"`
-	CurrentPrice   float64 `json:"current_price"`
-	Margin         float64 `json:"margin"`
-	ClearsFloor    bool    `json:"clears_floor"`
+	CostFloor    float64  `json:"cost_floor"`
+	CurrentPrice float64  `json:"current_price"`
+	Margin       float64  `json:"margin"`
+	ClearsFloor  bool     `json:"clears_floor"`
+	LandedCost   *float64 `json:"landed_cost,omitempty"` // used as direct cost for physical goods
 }
 
 // LFKResult holds Leaders/Fillers/Killers classification output.
@@ -328,6 +710,177 @@ type DeadWeightResult struct {
 	ComponentUsage  map[string]float64  `json:"component_usage"`
 }
 
+// SwappableResult holds "pick K of N" swappable bundle evaluation output.
+type SwappableResult struct {
+	FixedComponents         int             `json:"fixed_components"`
+	SwappableComponents     int             `json:"swappable_components"` // N
+	Picks                   int             `json:"picks"`                // K
+	ExpectedDeadWeightRatio float64         `json:"expected_dead_weight_ratio"`
+	ForcedDeadWeightRatio   float64         `json:"forced_dead_weight_ratio"` // all N included
+	Threshold               float64         `json:"threshold"`                // 0.40
+	Passes                  bool            `json:"passes"`
+	ExpectedCost            float64         `json:"expected_cost"`
+	BestCaseCost            float64         `json:"best_case_cost"`
+	WorstCaseCost           float64         `json:"worst_case_cost"`
+	WorstCaseMargin         float64         `json:"worst_case_margin"`
+	BundlePrice             float64         `json:"bundle_price"`
+	BVRMin                  float64         `json:"bvr_min"`
+	BVRExpected             float64         `json:"bvr_expected"`
+	BVRMax                  float64         `json:"bvr_max"`
+	Slots                   []SwappableSlot `json:"slots"`
+}
+
+// SwappableSlot is the expected selection and usage of one swappable component.
+type SwappableSlot struct {
+	Name            string  `json:"name"`
+	UsageRate       float64 `json:"usage_rate"`        // usage if forced into every bundle
+	PickShare       float64 `json:"pick_share"`        // share of customers choosing it
+	UsageWhenPicked float64 `json:"usage_when_picked"` // usage among customers who chose it
+	Cost            float64 `json:"cost"`
+	StandalonePrice float64 `json:"standalone_price"`
+	DeadWeight      bool    `json:"dead_weight"`
+}
+
+// RemovalImpactResult holds component removal ("what if we drop X") analysis.
+type RemovalImpactResult struct {
+	Baseline   RemovalScenario    `json:"baseline"`
+	Candidates []RemovalCandidate `json:"candidates"` // ranked, best removal first
+}
+
+// RemovalScenario is the bundle's key metrics for one composition.
+// Pointer metrics are nil when the input lacks the data to compute them.
+type RemovalScenario struct {
+	BundlePrice     float64  `json:"bundle_price"`
+	BVR             *float64 `json:"bvr,omitempty"`
+	BundleDiscount  *float64 `json:"bundle_discount,omitempty"`
+	CostFloorMargin *float64 `json:"cost_floor_margin,omitempty"`
+	DeadWeightRatio float64  `json:"dead_weight_ratio"`
+	Leaders         int      `json:"leaders_count"`
+	Fillers         int      `json:"fillers_count"`
+	Killers         int      `json:"killers_count"`
+}
+
+// RemovalCandidate is the bundle recomputed without one component.
+type RemovalCandidate struct {
+	Rank           int    `json:"rank"`
+	Component      string `json:"component"`
+	Classification string `json:"classification"` // current L/F/K role
+	RemovalScenario
+	BVRDelta             *float64 `json:"bvr_delta,omitempty"`
+	BundleDiscountDelta  *float64 `json:"bundle_discount_delta,omitempty"`
+	CostFloorMarginDelta *float64 `json:"cost_floor_margin_delta,omitempty"`
+	DeadWeightDelta      float64  `json:"dead_weight_delta"`
+}
+
+// OverProvisioningResult holds over-provisioning waste analysis output.
+type OverProvisioningResult struct {
+	ProvisionedCost float64                    `json:"provisioned_cost"` // per customer, all capped components
+	WastedCost      float64                    `json:"wasted_cost"`      // per customer
+	WasteShare      float64                    `json:"waste_share"`
+	Interpretation  string                     `json:"interpretation"`
+	Components      []OverProvisionedComponent `json:"components"`
+}
+
+// OverProvisionedComponent is allowance utilization and waste for one component.
+type OverProvisionedComponent struct {
+	Name               string  `json:"name"`
+	Allowance          float64 `json:"allowance"`
+	UnitCost           float64 `json:"unit_cost"`
+	Customers          int     `json:"customers"` // usage observations
+	MeanUsage          float64 `json:"mean_usage"`
+	MedianUsage        float64 `json:"median_usage"`
+	Utilization        float64 `json:"utilization"` // mean used allowance / allowance
+	ProvisionedCost    float64 `json:"provisioned_cost"`
+	WastedCost         float64 `json:"wasted_cost"`
+	WasteShare         float64 `json:"waste_share"`
+	CapHitShare        float64 `json:"cap_hit_share"` // share of customers at or above the allowance
+	Percentile         float64 `json:"percentile"`
+	SuggestedAllowance float64 `json:"suggested_allowance"`
+	SavingsPerCustomer float64 `json:"savings_per_customer"` // negative = allowance too tight
+}
+
+// CrossSubsidyStressResult holds the cross-subsidy sustainability test output.
+type CrossSubsidyStressResult struct {
+	BaseNetMargin     float64 `json:"base_net_margin"`
+	StressedNetMargin float64 `json:"stressed_net_margin"`
+	SurvivesStress    bool    `json:"survives_stress"`
+	DefaultShocks     bool    `json:"default_shocks"` // no shocks given: recipients at 2x usage
+	// Break points: nil when net margin never turns negative along that axis.
+	ShockBreakPoint         *float64                      `json:"shock_break_point,omitempty"`          // multiple of the applied shocks
+	RecipientUsageBreakEven *float64                      `json:"recipient_usage_break_even,omitempty"` // usage increase on all recipients
+	SourcePriceBreakEven    *float64                      `json:"source_price_break_even,omitempty"`    // price drop on all sources
+	Components              []CrossSubsidyStressComponent `json:"components"`
+}
+
+// CrossSubsidyStressComponent is base and stressed margin for one component.
+type CrossSubsidyStressComponent struct {
+	Name            string   `json:"name"`
+	Role            string   `json:"role"` // "source" or "recipient" at base
+	UsageShock      float64  `json:"usage_shock"`
+	PriceShock      float64  `json:"price_shock"`
+	BaseMargin      float64  `json:"base_margin"`
+	StressedRevenue float64  `json:"stressed_revenue"`
+	StressedCost    float64  `json:"stressed_cost"`
+	StressedMargin  float64  `json:"stressed_margin"`
+	UsageBreakPoint *float64 `json:"usage_break_point,omitempty"` // this component's usage increase alone that turns net margin negative
+	PriceBreakPoint *float64 `json:"price_break_point,omitempty"` // this component's price drop alone that turns net margin negative
+}
+
+// PartnerCostResult holds the Partner/Licensing Cost Ratio and partner dependency analysis.
+type PartnerCostResult struct {
+	PartnerCostPerCustomer     float64       `json:"partner_cost_per_customer"`
+	PremiumRevenueDelta        float64       `json:"premium_revenue_delta"` // per customer
+	Ratio                      float64       `json:"ratio"`
+	Threshold                  float64       `json:"threshold"` // 0.30
+	Passes                     bool          `json:"passes"`
+	Interpretation             string        `json:"interpretation"`
+	MarginPerCustomer          float64       `json:"margin_per_customer"`
+	HHI                        float64       `json:"hhi"`           // Herfindahl index of attributed partner cost shares
+	Concentration              string        `json:"concentration"` // "low", "moderate", "high"
+	TopPartner                 string        `json:"top_partner,omitempty"`
+	TopPartnerMarginDependence *float64      `json:"top_partner_margin_dependence,omitempty"`
+	Partners                   []PartnerCost `json:"partners"`
+}
+
+// PartnerCost is the cost and margin dependency attributed to one partner.
+type PartnerCost struct {
+	Partner                string   `json:"partner"`
+	Components             []string `json:"components,omitempty"`
+	Cost                   float64  `json:"cost"`
+	CostShare              float64  `json:"cost_share"`
+	DeltaRatio             float64  `json:"delta_ratio"`                         // cost / premium revenue delta
+	MarginDependence       *float64 `json:"margin_dependence,omitempty"`         // margin lost if partner doubles price
+	BreakEvenPriceIncrease *float64 `json:"break_even_price_increase,omitempty"` // partner price rise that erases margin
+}
+
+// EngagementResult holds component engagement derived from raw usage events.
+type EngagementResult struct {
+	Customers   int                   `json:"customers"` // denominator
+	Events      int                   `json:"events"`
+	PeriodStart string                `json:"period_start"`
+	PeriodEnd   string                `json:"period_end"`
+	Months      int                   `json:"months"`
+	Components  []ComponentEngagement `json:"components"`
+}
+
+// ComponentEngagement is the engagement profile of one component.
+type ComponentEngagement struct {
+	Name                  string            `json:"name"`
+	Events                int               `json:"events"`
+	ActiveCustomers       int               `json:"active_customers"` // used at least once in the period
+	Activation30d         float64           `json:"activation_30d"`
+	MonthlyActiveRate     float64           `json:"monthly_active_rate"`
+	EngagementRate        float64           `json:"engagement_rate"`  // events per customer per month
+	EngagementDepth       float64           `json:"engagement_depth"` // events per active customer-month
+	FrequencyDistribution []FrequencyBucket `json:"frequency_distribution"`
+}
+
+// FrequencyBucket is the share of customer-months with a given event count.
+type FrequencyBucket struct {
+	Label string  `json:"label"` // "0", "1", "2-4", "5-9", "10+"
+	Share float64 `json:"share"`
+}
+
 // CrossSubsidyResult holds cross-subsidy analysis output.
 type CrossSubsidyResult struct {
 	NetMargin        float64                     `json:"net_margin"`
@@ -362,6 +915,211 @@ type CLVResult struct {
 	LifespanMonths   float64 `json:"lifespan_months"`
 }
 
+// PriceWaterfallResult walks list price to pocket price per sales channel
+// and blends the channels by volume share.
+type PriceWaterfallResult struct {
+	Channels               []ChannelWaterfall `json:"channels"`
+	Deductions             []WaterfallStep    `json:"deductions"` // volume-weighted, by deduction name
+	BlendedListPrice       float64            `json:"blended_list_price"`
+	BlendedPocketPrice     float64            `json:"blended_pocket_price"`
+	BlendedUnitCost        float64            `json:"blended_unit_cost"`
+	BlendedPocketMargin    float64            `json:"blended_pocket_margin"`
+	BlendedPocketMarginPct float64            `json:"blended_pocket_margin_pct"` // of pocket price
+	Leakage                float64            `json:"leakage"`                   // 1 - pocket / list
+	BestChannel            string             `json:"best_channel"`              // highest pocket margin
+	WorstChannel           string             `json:"worst_channel"`
+}
+
+// ChannelWaterfall is the price waterfall of one sales channel.
+type ChannelWaterfall struct {
+	Name            string          `json:"name"`
+	VolumeShare     float64         `json:"volume_share"` // normalized
+	ListPrice       float64         `json:"list_price"`
+	Steps           []WaterfallStep `json:"steps"`
+	PocketPrice     float64         `json:"pocket_price"`
+	Leakage         float64         `json:"leakage"`
+	UnitCost        float64         `json:"unit_cost"`
+	PocketMargin    float64         `json:"pocket_margin"`
+	PocketMarginPct float64         `json:"pocket_margin_pct"`
+}
+
+// WaterfallStep is one deduction in a price waterfall.
+type WaterfallStep struct {
+	Name       string  `json:"name"`
+	Amount     float64 `json:"amount"`
+	PriceAfter float64 `json:"price_after"`
+	PctOfList  float64 `json:"pct_of_list"`
+}
+
+// LandedCostResult builds up the per-unit landed cost of a physical product.
+// Inventory fields are set when inventory_turns is given; values need
+// annual_units.
+type LandedCostResult struct {
+	BOMCost                  float64  `json:"bom_cost"`
+	AssemblyCost             float64  `json:"assembly_cost"`
+	PackagingCost            float64  `json:"packaging_cost"`
+	ExWorksCost              float64  `json:"ex_works_cost"`
+	Freight                  float64  `json:"freight"`
+	Duty                     float64  `json:"duty"`
+	OtherLandedCost          float64  `json:"other_landed_cost"`
+	LandedCost               float64  `json:"landed_cost"`
+	LogisticsShare           float64  `json:"logistics_share"` // (freight + duty + other) / landed cost
+	DaysInventoryOutstanding *float64 `json:"days_inventory_outstanding,omitempty"`
+	AnnualLandedCost         *float64 `json:"annual_landed_cost,omitempty"`
+	AverageInventoryValue    *float64 `json:"average_inventory_value,omitempty"`
+}
+
+// ChannelMarginResult is the per-unit waterfall from retail price to the
+// manufacturer's contribution after returns and warranty, plus the cash
+// conversion cycle. Working capital needs annual_units.
+type ChannelMarginResult struct {
+	RetailPrice             float64       `json:"retail_price"`
+	Channels                []ChannelStep `json:"channels,omitempty"`
+	NetPrice                float64       `json:"net_price"`     // manufacturer selling price
+	ChannelShare            float64       `json:"channel_share"` // share of retail price kept by the channel
+	NetRevenue              float64       `json:"net_revenue"`   // per unit shipped, after refunds
+	LandedCost              float64       `json:"landed_cost"`
+	ReturnCost              float64       `json:"return_cost"` // per unit shipped: handling less recovered cost
+	WarrantyReserve         float64       `json:"warranty_reserve"`
+	UnitContribution        float64       `json:"unit_contribution"`
+	ContributionMarginPct   float64       `json:"contribution_margin_pct"` // of net revenue
+	CashConversionCycleDays *float64      `json:"cash_conversion_cycle_days,omitempty"`
+	WorkingCapital          *float64      `json:"working_capital,omitempty"` // inventory + receivables - payables
+	Viable                  bool          `json:"viable"`                    // unit contribution > 0
+}
+
+// ChannelStep is one channel partner in the price waterfall.
+type ChannelStep struct {
+	Name         string  `json:"name"`
+	Margin       float64 `json:"margin"`
+	SellPrice    float64 `json:"sell_price"`
+	BuyPrice     float64 `json:"buy_price"`
+	MarginAmount float64 `json:"margin_amount"`
+}
+
+// TierEconomicsResult holds per-tier unit economics and the blended figures
+// weighted by customer share. Totals are set when the customer base is known.
+type TierEconomicsResult struct {
+	Tiers            []TierEconomics      `json:"tiers"`
+	BlendedARPU      float64              `json:"blended_arpu"`
+	BlendedCost      float64              `json:"blended_cost"`
+	BlendedMargin    float64              `json:"blended_margin"` // per customer
+	BlendedMarginPct float64              `json:"blended_margin_pct"`
+	Customers        *float64             `json:"customers,omitempty"`
+	Revenue          *float64             `json:"revenue,omitempty"`
+	GrossProfit      *float64             `json:"gross_profit,omitempty"`
+	MixShifts        []TierMixShiftResult `json:"mix_shifts,omitempty"`
+}
+
+// TierEconomics is the unit economics of one tier.
+type TierEconomics struct {
+	Name            string   `json:"name"`
+	Price           float64  `json:"price"`
+	CostPerCustomer float64  `json:"cost_per_customer"`
+	Margin          float64  `json:"margin"`
+	MarginPct       float64  `json:"margin_pct"`
+	Share           float64  `json:"share"` // normalized customer share
+	RevenueShare    float64  `json:"revenue_share"`
+	MarginShare     float64  `json:"margin_share"` // share of blended margin; can exceed 1 when other tiers lose money
+	Customers       *float64 `json:"customers,omitempty"`
+}
+
+// TierMixShiftResult is the blended outcome of one mix shift, applied to the
+// current mix on its own.
+type TierMixShiftResult struct {
+	From             string   `json:"from"`
+	To               string   `json:"to"`
+	Share            float64  `json:"share"`
+	MovedShare       float64  `json:"moved_share"` // fraction of all customers that move
+	BlendedARPU      float64  `json:"blended_arpu"`
+	BlendedMargin    float64  `json:"blended_margin"`
+	ARPUDelta        float64  `json:"arpu_delta"`
+	MarginDelta      float64  `json:"margin_delta"`
+	RevenueDelta     *float64 `json:"revenue_delta,omitempty"`
+	GrossProfitDelta *float64 `json:"gross_profit_delta,omitempty"`
+}
+
+// ChurnValueResult values a churn reduction in customers, revenue, and CLV.
+// Annual figures follow the current base for the next 12 months; CLV uses
+// gross margin when given, else revenue. Discount fields are nil when the
+// product has no bundle discount.
+type ChurnValueResult struct {
+	ChurnBefore               float64  `json:"churn_before"` // monthly
+	ChurnAfter                float64  `json:"churn_after"`
+	ChurnReduction            float64  `json:"churn_reduction"` // relative
+	Customers                 float64  `json:"customers"`
+	RevenuePerCustomer        float64  `json:"revenue_per_customer"` // monthly
+	GrossMarginPct            *float64 `json:"gross_margin_pct,omitempty"`
+	CustomersSavedPerMonth    float64  `json:"customers_saved_per_month"`
+	RetainedCustomers12M      float64  `json:"retained_customers_12m"`
+	RetainedAnnualRevenue     float64  `json:"retained_annual_revenue"`
+	RetainedAnnualGrossProfit float64  `json:"retained_annual_gross_profit"`
+	LifespanBeforeMonths      float64  `json:"lifespan_before_months"`
+	LifespanAfterMonths       float64  `json:"lifespan_after_months"`
+	CLVBefore                 float64  `json:"clv_before"`
+	CLVAfter                  float64  `json:"clv_after"`
+	IncrementalCLV            float64  `json:"incremental_clv"` // per customer
+	TotalIncrementalCLV       float64  `json:"total_incremental_clv"`
+	DiscountPerCustomer       *float64 `json:"discount_per_customer,omitempty"` // standalone sum - bundle price, monthly
+	AnnualDiscountCost        *float64 `json:"annual_discount_cost,omitempty"`
+	DiscountPaybackRatio      *float64 `json:"discount_payback_ratio,omitempty"` // retained gross profit / discount cost
+	Interpretation            string   `json:"interpretation"`
+}
+
+// CLVAdvancedResult holds the discounted, churn-aware CLV.
+type CLVAdvancedResult struct {
+	CLV                    float64     `json:"clv"` // discounted
+	UndiscountedCLV        float64     `json:"undiscounted_clv"`
+	ExpectedLifetimeMonths float64     `json:"expected_lifetime_months"` // within horizon
+	HorizonMonths          int         `json:"horizon_months"`
+	DiscountRate           float64     `json:"discount_rate"`
+	CAC                    *float64    `json:"cac,omitempty"`
+	LTVToCAC               *float64    `json:"ltv_to_cac,omitempty"`
+	Interpretation         string      `json:"interpretation,omitempty"`
+	Cohorts                []CohortCLV `json:"cohorts,omitempty"`
+	BlendedCLV             *float64    `json:"blended_clv,omitempty"` // customer-weighted across cohorts
+}
+
+// CohortCLV is the discounted CLV of one acquisition cohort.
+type CohortCLV struct {
+	Name                   string   `json:"name"`
+	Customers              float64  `json:"customers,omitempty"`
+	CLV                    float64  `json:"clv"`
+	ExpectedLifetimeMonths float64  `json:"expected_lifetime_months"`
+	CohortValue            float64  `json:"cohort_value,omitempty"` // CLV * customers
+	LTVToCAC               *float64 `json:"ltv_to_cac,omitempty"`
+}
+
+// MonteCarloResult holds the simulated distribution of financial outcomes.
+// A metric is omitted when its calculator could not run on the input.
+type MonteCarloResult struct {
+	Iterations                int                  `json:"iterations"`
+	Seed                      uint64               `json:"seed"`
+	Margin                    *DistributionSummary `json:"margin,omitempty"` // margin per customer
+	MarginPct                 *DistributionSummary `json:"margin_pct,omitempty"`
+	CLV                       *DistributionSummary `json:"clv,omitempty"`
+	CACPayback                *DistributionSummary `json:"cac_payback,omitempty"` // months
+	BreakEvenUnits            *DistributionSummary `json:"break_even_units,omitempty"`
+	ProbabilityNegativeMargin *float64             `json:"probability_negative_margin,omitempty"`
+	Errors                    map[string]int       `json:"errors,omitempty"` // failed iterations per metric
+}
+
+// DistributionSummary describes a simulated sample.
+type DistributionSummary struct {
+	Samples int     `json:"samples"`
+	Mean    float64 `json:"mean"`
+	StdDev  float64 `json:"std_dev"`
+	Min     float64 `json:"min"`
+	P5      float64 `json:"p5"`
+	P10     float64 `json:"p10"`
+	P25     float64 `json:"p25"`
+	P50     float64 `json:"p50"`
+	P75     float64 `json:"p75"`
+	P90     float64 `json:"p90"`
+	P95     float64 `json:"p95"`
+	Max     float64 `json:"max"`
+}
+
 // BreakEvenResult holds break-even analysis output.
 type BreakEvenResult struct {
 	BreakEvenUnits  float64 `json:"break_even_units"`
@@ -369,6 +1127,107 @@ type BreakEvenResult struct {
 	ContribMargin   float64 `json:"contribution_margin"`
 }
 
+// BreakEvenScheduleResult holds every break-even crossing over the volume
+// range and the margin curve by volume. FirstBreakEven is nil when the
+// product never breaks even within max_volume.
+type BreakEvenScheduleResult struct {
+	Price           float64             `json:"price"`
+	MaxVolume       float64             `json:"max_volume"`
+	Crossings       []BreakEvenCrossing `json:"crossings"`
+	FirstBreakEven  *float64            `json:"first_break_even,omitempty"`
+	ProfitableAtMax bool                `json:"profitable_at_max"`
+	MaxProfit       float64             `json:"max_profit"`
+	MaxProfitVolume float64             `json:"max_profit_volume"`
+	Curve           []MarginPoint       `json:"curve"`
+}
+
+// BreakEvenCrossing is a volume where profit changes sign. Direction is
+// "to_profit" or "to_loss"; Cause names the step cost or unit cost tier that
+// starts at that volume, if any.
+type BreakEvenCrossing struct {
+	Volume    float64 `json:"volume"`
+	Direction string  `json:"direction"`
+	Cause     string  `json:"cause,omitempty"`
+}
+
+// MarginPoint is one volume on the margin curve.
+type MarginPoint struct {
+	Volume       float64 `json:"volume"`
+	Revenue      float64 `json:"revenue"`
+	VariableCost float64 `json:"variable_cost"`
+	FixedCost    float64 `json:"fixed_cost"` // fixed_costs + step costs
+	Profit       float64 `json:"profit"`
+	MarginPct    float64 `json:"margin_pct"`
+}
+
+// ForecastResult holds the fitted model and the projection with prediction
+// intervals. AnnualGrowth compares the next 12 forecast months with the last
+// 12 observed, when both exist.
+type ForecastResult struct {
+	Model         string          `json:"model"` // "holt_winters_additive" or "holt_linear"
+	Metric        string          `json:"metric,omitempty"`
+	Observations  int             `json:"observations"`
+	SeasonLength  int             `json:"season_length,omitempty"`
+	Alpha         float64         `json:"alpha"`
+	Beta          float64         `json:"beta"`
+	Gamma         float64         `json:"gamma"`
+	Level         float64         `json:"level"`
+	Trend         float64         `json:"trend"`              // per month
+	Seasonal      []float64       `json:"seasonal,omitempty"` // indexed by calendar position of the next months
+	RMSE          float64         `json:"rmse"`               // one-step-ahead, in-sample
+	MAPE          *float64        `json:"mape,omitempty"`     // when all observations are positive
+	Confidence    float64         `json:"confidence"`
+	Forecast      []ForecastPoint `json:"forecast"`
+	TotalForecast float64         `json:"total_forecast"`
+	AnnualGrowth  *float64        `json:"annual_growth,omitempty"`
+}
+
+// ForecastPoint is one forecast month. Month counts from the first
+// observation (1-based), so the first forecast month is observations + 1.
+type ForecastPoint struct {
+	Month int     `json:"month"`
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// ProjectionResult holds the multi-period cash-flow projection.
+// Month 0 carries the launch investment. Break-even and payback months are
+// nil when not reached within the horizon; IRR is nil when cash flows never
+// change sign.
+type ProjectionResult struct {
+	Months                  []ProjectionMonth `json:"months"`
+	DiscountRate            float64           `json:"discount_rate"` // annual
+	NPV                     float64           `json:"npv"`
+	IRR                     *float64          `json:"irr,omitempty"`                        // annualized
+	BreakEvenMonth          *int              `json:"break_even_month,omitempty"`           // first month with cumulative cash >= 0
+	OperatingBreakEvenMonth *int              `json:"operating_break_even_month,omitempty"` // first month with operating profit >= 0
+	DiscountedPaybackMonth  *int              `json:"discounted_payback_month,omitempty"`
+	TotalRevenue            float64           `json:"total_revenue"`
+	TotalCashFlow           float64           `json:"total_cash_flow"`
+	PeakFundingNeed         float64           `json:"peak_funding_need"` // deepest cumulative cash deficit
+	EndingCustomers         float64           `json:"ending_customers"`
+}
+
+// ProjectionMonth is one row of the projected P&L and cash-flow table.
+type ProjectionMonth struct {
+	Month                    int     `json:"month"`
+	Customers                float64 `json:"customers"` // end of month
+	NewCustomers             float64 `json:"new_customers"`
+	ChurnedCustomers         float64 `json:"churned_customers"`
+	Price                    float64 `json:"price"`
+	Revenue                  float64 `json:"revenue"`
+	VariableCost             float64 `json:"variable_cost"`
+	FixedCost                float64 `json:"fixed_cost"`
+	AcquisitionCost          float64 `json:"acquisition_cost"`
+	Investment               float64 `json:"investment"`
+	OperatingProfit          float64 `json:"operating_profit"` // revenue - variable - fixed
+	CashFlow                 float64 `json:"cash_flow"`
+	CumulativeCash           float64 `json:"cumulative_cash"`
+	DiscountedCashFlow       float64 `json:"discounted_cash_flow"`
+	CumulativeDiscountedCash float64 `json:"cumulative_discounted_cash"`
+}
+
 // StressTestResult holds stress test output.
 type StressTestResult struct {
 	BaseMargin         float64 `json:"base_margin"`
@@ -386,6 +1245,42 @@ type CannibalizationResult struct {
 	NetPositive            bool    `json:"net_positive"`
 }
 
+// CannibalizationTimelineResult is the month-by-month net revenue effect of
+// launching the bundle versus the no-bundle baseline. Month fields are nil
+// when the condition is never met within the horizon.
+type CannibalizationTimelineResult struct {
+	Months                  []CannibalizationMonth `json:"months"`
+	StandaloneChurn         float64                `json:"standalone_churn"`
+	BundleChurn             float64                `json:"bundle_churn"`
+	TotalMigrationEffect    float64                `json:"total_migration_effect"`
+	TotalAcquisitionEffect  float64                `json:"total_acquisition_effect"`
+	CumulativeNetRevenue    float64                `json:"cumulative_net_revenue"`
+	NetPositive             bool                   `json:"net_positive"`                        // cumulative net > 0 at the horizon
+	MigrationOffsetMonth    *int                   `json:"migration_offset_month,omitempty"`    // first month retained migrants out-earn their standalone baseline
+	MonthlyPositiveMonth    *int                   `json:"monthly_positive_month,omitempty"`    // first month with net effect > 0
+	CumulativePositiveMonth *int                   `json:"cumulative_positive_month,omitempty"` // first month cumulative net turns > 0 and stays there
+}
+
+// CannibalizationMonth is one month of the cannibalization timeline.
+// Migration effect = migrated revenue - forgone standalone revenue (what the
+// migrants would still pay standalone); acquisition effect = new bundle
+// revenue. Net effect is their sum.
+type CannibalizationMonth struct {
+	Month                    int     `json:"month"`
+	Migrations               float64 `json:"migrations"`
+	StandaloneCustomers      float64 `json:"standalone_customers"`
+	MigratedCustomers        float64 `json:"migrated_customers"`   // migrants still active
+	NewBundleCustomers       float64 `json:"new_bundle_customers"` // new acquisitions still active
+	BaselineRevenue          float64 `json:"baseline_revenue"`     // no-bundle standalone revenue
+	Revenue                  float64 `json:"revenue"`              // with the bundle
+	MigratedRevenue          float64 `json:"migrated_revenue"`
+	ForgoneStandaloneRevenue float64 `json:"forgone_standalone_revenue"`
+	MigrationEffect          float64 `json:"migration_effect"`
+	AcquisitionEffect        float64 `json:"acquisition_effect"`
+	NetEffect                float64 `json:"net_effect"` // revenue - baseline revenue
+	CumulativeNet            float64 `json:"cumulative_net"`
+}
+
 // GoNoGoResult holds the final weighted scoring output.
 type GoNoGoResult struct {
 	WeightedScore float64             `json:"weighted_score"`
@@ -421,8 +1316,271 @@ type ScoredRisk struct {
 	Level       string  `json:"level"` // "low", "medium", "high", "critical"
 }
 
+// NPSSurveyResult is NPS from raw responses with its sampling error, broken
+// down by segment, tier and region, and the premium-vs-base tier test.
+type NPSSurveyResult struct {
+	NPSStats
+	Segments       []NPSGroup     `json:"segments,omitempty"`
+	Tiers          []NPSGroup     `json:"tiers,omitempty"`
+	Regions        []NPSGroup     `json:"regions,omitempty"`
+	TierComparison *NPSComparison `json:"tier_comparison,omitempty"`
+	Interpretation string         `json:"interpretation"`
+}
+
+// NPSStats holds NPS (-100..100) for a set of responses. The margin of
+// error and CI are at 95%.
+type NPSStats struct {
+	Responses     int     `json:"responses"`
+	Promoters     int     `json:"promoters"`
+	Passives      int     `json:"passives"`
+	Detractors    int     `json:"detractors"`
+	PromotersPct  float64 `json:"promoters_pct"`
+	PassivesPct   float64 `json:"passives_pct"`
+	DetractorsPct float64 `json:"detractors_pct"`
+	NPS           float64 `json:"nps"`
+	StandardError float64 `json:"standard_error"`
+	MarginOfError float64 `json:"margin_of_error"`
+	CILower       float64 `json:"ci_lower"`
+	CIUpper       float64 `json:"ci_upper"`
+}
+
+// NPSGroup is NPS for one segment, tier or region.
+type NPSGroup struct {
+	Name string `json:"name"`
+	NPSStats
+}
+
+// NPSComparison tests premium-tier NPS against base-tier NPS with a
+// two-sample z-test.
+type NPSComparison struct {
+	PremiumTier            string  `json:"premium_tier"`
+	BaseTier               string  `json:"base_tier"`
+	Difference             float64 `json:"difference"` // premium NPS - base NPS
+	StandardError          float64 `json:"standard_error"`
+	Z                      float64 `json:"z"`
+	PValue                 float64 `json:"p_value"`                 // two-sided
+	PValueAbove            float64 `json:"p_value_above"`           // one-sided, premium > base
+	SignificantlyDifferent bool    `json:"significantly_different"` // p_value < 0.05
+	SignificantlyAbove     bool    `json:"significantly_above"`     // p_value_above < 0.05
+}
+
+// ReviewsResult summarizes the product's review ratings across sources,
+// its top-2-box CSAT, and how it compares with each competitor that has
+// reviews. Deltas are product minus competitor.
+type ReviewsResult struct {
+	ReviewSummary
+	PriorMean          float64            `json:"prior_mean"`
+	PriorWeight        float64            `json:"prior_weight"`
+	CSAT               *float64           `json:"csat,omitempty"` // top-2-box share, via the CSAT calculation
+	CSATInterpretation string             `json:"csat_interpretation,omitempty"`
+	Competitors        []ReviewComparison `json:"competitors,omitempty"`
+}
+
+// ReviewSummary pools review sources for one product. Distribution and box
+// shares cover the sources with per-star data; the means cover all sources.
+type ReviewSummary struct {
+	Name           string                `json:"name"`
+	Sources        []ReviewSourceSummary `json:"sources"`
+	ReviewCount    float64               `json:"review_count"`
+	MeanRating     float64               `json:"mean_rating"`              // review-count weighted
+	BayesianRating float64               `json:"bayesian_rating"`          // shrunk toward the prior mean
+	Distribution   []float64             `json:"distribution,omitempty"`   // shares of 1..5 stars
+	TopTwoBox      *float64              `json:"top_two_box,omitempty"`    // share of 4-5 stars
+	BottomTwoBox   *float64              `json:"bottom_two_box,omitempty"` // share of 1-2 stars
+}
+
+// ReviewSourceSummary is one review source.
+type ReviewSourceSummary struct {
+	Source       string    `json:"source"`
+	ReviewCount  float64   `json:"review_count"`
+	MeanRating   float64   `json:"mean_rating"`
+	Distribution []float64 `json:"distribution,omitempty"`
+}
+
+// ReviewComparison sets a competitor's reviews against the product's.
+type ReviewComparison struct {
+	ReviewSummary
+	MeanDelta            float64  `json:"mean_delta"`
+	BayesianDelta        float64  `json:"bayesian_delta"`
+	TopTwoBoxDelta       *float64 `json:"top_two_box_delta,omitempty"`
+	DistributionDistance *float64 `json:"distribution_distance,omitempty"` // total variation, 0-1
+}
+
+// TopicsResult counts topic mentions and their polarity in the review text
+// of the product and each competitor.
+type TopicsResult struct {
+	ProductTopics
+	Competitors []ProductTopics `json:"competitors,omitempty"`
+}
+
+// ProductTopics is the topic breakdown for one product's reviews.
+// TopComplaints and TopPraise rank topics by negative and positive mentions.
+type ProductTopics struct {
+	Name          string      `json:"name"`
+	Reviews       int         `json:"reviews"`        // reviews with text
+	NegativeShare float64     `json:"negative_share"` // reviews with a negative topic mention
+	Topics        []TopicStat `json:"topics"`
+	TopComplaints []string    `json:"top_complaints,omitempty"`
+	TopPraise     []string    `json:"top_praise,omitempty"`
+}
+
+// TopicStat counts the reviews mentioning a topic.
+type TopicStat struct {
+	Topic        string  `json:"topic"`
+	Mentions     int     `json:"mentions"`
+	Share        float64 `json:"share"` // of reviews with text
+	Positive     int     `json:"positive"`
+	Negative     int     `json:"negative"`
+	Neutral      int     `json:"neutral"`
+	NetSentiment float64 `json:"net_sentiment"` // (positive - negative) / mentions
+}
+
+// SurvivalResult holds Kaplan-Meier retention for all customer records and
+// broken down by start-month cohort, tier and bundle vs standalone.
+type SurvivalResult struct {
+	SurvivalCurve
+	AsOf     string          `json:"as_of"`
+	Cohorts  []SurvivalCurve `json:"cohorts,omitempty"`
+	Tiers    []SurvivalCurve `json:"tiers,omitempty"`
+	Bundling []SurvivalCurve `json:"bundling,omitempty"` // "bundle" and "standalone"
+}
+
+// SurvivalCurve is the Kaplan-Meier estimate for one group of customers.
+// AverageLifespanMonths is observed customer-months / churned customers, the
+// constant-churn lifespan that financials.average_lifespan_months expects;
+// nil when nobody churned, as is MedianMonths while retention stays above 50%.
+type SurvivalCurve struct {
+	Name                  string          `json:"name,omitempty"`
+	Customers             int             `json:"customers"`
+	Churned               int             `json:"churned"`
+	Censored              int             `json:"censored"`
+	ExposureMonths        float64         `json:"exposure_months"`
+	MonthlyChurn          float64         `json:"monthly_churn"` // churned / exposure months
+	AverageLifespanMonths *float64        `json:"average_lifespan_months,omitempty"`
+	MedianMonths          *float64        `json:"median_months,omitempty"`
+	Curve                 []SurvivalPoint `json:"curve"`
+}
+
+// SurvivalPoint is retention at the end of a month since start.
+type SurvivalPoint struct {
+	Month     int     `json:"month"`
+	Retention float64 `json:"retention"`
+	Hazard    float64 `json:"hazard"`  // churn during this month among those retained at its start
+	AtRisk    int     `json:"at_risk"` // customers observed into this month
+}
+
+// CohortsResult is the cohort matrix: one row per acquisition month, indexed
+// by months since acquisition (0 = acquisition month), and the average curve
+// across cohorts.
+type CohortsResult struct {
+	Customers int         `json:"customers"`
+	Cohorts   []CohortRow `json:"cohorts"`
+	Average   CohortCurve `json:"average"` // pooled over the cohorts observed at each month
+}
+
+// CohortRow is one acquisition cohort.
+type CohortRow struct {
+	Cohort    string `json:"cohort"` // YYYY-MM
+	Customers int    `json:"customers"`
+	CohortCurve
+}
+
+// CohortCurve holds retention and revenue by months since acquisition.
+// Revenue series are present only when the activity records carry revenue.
+// MonthlyChurn is the share of customers active in one month and not the
+// next, over all month-to-month transitions.
+type CohortCurve struct {
+	Active       []int     `json:"active"`
+	Retention    []float64 `json:"retention"` // active / cohort customers
+	Revenue      []float64 `json:"revenue,omitempty"`
+	NRR          []float64 `json:"nrr,omitempty"`  // revenue / month-0 revenue
+	GRR          []float64 `json:"grr,omitempty"`  // as NRR, each customer capped at their month-0 revenue
+	ARPU         []float64 `json:"arpu,omitempty"` // revenue / active
+	MonthlyChurn float64   `json:"monthly_churn"`
+}
+
 // SingleValueResult is a generic result for simple ratio/rate calculations.
 type SingleValueResult struct {
-	Value          float64 `json:"value"`
-	Interpretation string  `json:"interpretation,omitempty"`
+	Value          float64       `json:"value"`
+	Interpretation string        `json:"interpretation,omitempty"`
+	Significance   *Significance `json:"significance,omitempty"` // when sample sizes are given
+}
+
+// Significance is the sampling evidence behind a value: the confidence
+// interval of the value and, when there is a comparison, the test of no
+// difference.
+type Significance struct {
+	Test        string   `json:"test,omitempty"` // "two_proportion_z", "chi_square", "welch_t"; empty when no test ran
+	Statistic   float64  `json:"statistic,omitempty"`
+	DF          *float64 `json:"df,omitempty"`
+	PValue      *float64 `json:"p_value,omitempty"`     // two-sided
+	Significant *bool    `json:"significant,omitempty"` // p < 1 - confidence
+	Confidence  float64  `json:"confidence"`
+	CILower     float64  `json:"ci_lower"`
+	CIUpper     float64  `json:"ci_upper"`
+	CIMethod    string   `json:"ci_method"` // "log_relative_risk", "wilson", "bootstrap"
+}
+
+// ---------------------------------------------------------------------------
+// Analysis result types (sensitivity, scenarios, goal seek)
+// ---------------------------------------------------------------------------
+
+// SensitivityResult ranks input fields by their effect on one calculator output.
+type SensitivityResult struct {
+	Module     string             `json:"module"`
+	Function   string             `json:"function"`
+	Output     string             `json:"output"` // JSON path of the measured output
+	BaseOutput float64            `json:"base_output"`
+	Range      float64            `json:"range"`                // perturbation, e.g. 0.2 for ±20%
+	Inputs     []SensitivityInput `json:"inputs"`               // ranked by swing, largest first
+	Unaffected []string           `json:"unaffected,omitempty"` // varied fields with no effect
+}
+
+// SensitivityInput is one tornado bar: the output at input*(1-range) and
+// input*(1+range).
+type SensitivityInput struct {
+	Rank       int      `json:"rank"`
+	Field      string   `json:"field"`
+	BaseValue  float64  `json:"base_value"`
+	LowValue   float64  `json:"low_value"`
+	HighValue  float64  `json:"high_value"`
+	LowOutput  *float64 `json:"low_output,omitempty"` // nil when the calculator failed
+	HighOutput *float64 `json:"high_output,omitempty"`
+	Swing      float64  `json:"swing"`                // |high_output - low_output|
+	Elasticity *float64 `json:"elasticity,omitempty"` // % output change per % input change
+	Error      string   `json:"error,omitempty"`
+}
+
+// ScenarioResult compares calculator outputs across the base input and its
+// named scenarios.
+type ScenarioResult struct {
+	Module    string            `json:"module"`
+	Function  string            `json:"function"`
+	Outputs   []string          `json:"outputs"`   // JSON paths compared, in table order
+	Scenarios []ScenarioOutcome `json:"scenarios"` // "base" first, then input order
+}
+
+// ScenarioOutcome holds one scenario's outputs. Changes are relative to base
+// (0.1 = +10%) and omitted where the base output is zero.
+type ScenarioOutcome struct {
+	Name    string             `json:"name"`
+	Values  map[string]float64 `json:"values,omitempty"`
+	Changes map[string]float64 `json:"changes,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// GoalSeekResult is the input value that makes a calculator output hit a target.
+type GoalSeekResult struct {
+	Module         string   `json:"module"`
+	Function       string   `json:"function"`
+	Output         string   `json:"output"` // JSON path of the targeted output
+	Target         float64  `json:"target"`
+	Field          string   `json:"field"`                // input path solved for
+	BaseValue      *float64 `json:"base_value,omitempty"` // nil when the field was unset
+	BaseOutput     *float64 `json:"base_output,omitempty"`
+	Solution       float64  `json:"solution"`
+	ChangeFromBase *float64 `json:"change_from_base,omitempty"` // (solution - base) / |base|
+	AchievedOutput float64  `json:"achieved_output"`
+	Converged      bool     `json:"converged"` // false when the output jumps past the target (step functions)
+	Evaluations    int      `json:"evaluations"`
 }
@@ -0,0 +1,193 @@
+// Package fieldpath reads and writes numeric fields of the appraisal input by
+// their JSON path, e.g. "financials.cogs" or "components[0].marginal_cost".
+// Paths use the JSON tag names from the domain package; nil pointers along the
+// path are allocated on Set.
+package fieldpath
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Get returns the numeric value at path within root (a pointer to a struct).
+func Get(root interface{}, path string) (float64, error) {
+	v, err := resolve(root, path, false)
+	if err != nil {
+		return 0, err
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, fmt.Errorf("%s is not set", path)
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Float64:
+		return v.Float(), nil
+	case reflect.Int:
+		return float64(v.Int()), nil
+	default:
+		return 0, fmt.Errorf("%s is not numeric", path)
+	}
+}
+
+// Set writes value at path within root (a pointer to a struct). Integer
+// fields receive the rounded value.
+func Set(root interface{}, path string, value float64) error {
+	v, err := resolve(root, path, true)
+	if err != nil {
+		return err
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Float64:
+		v.SetFloat(value)
+	case reflect.Int:
+		v.SetInt(int64(math.Round(value)))
+	default:
+		return fmt.Errorf("%s is not numeric", path)
+	}
+	return nil
+}
+
+// resolve walks path and returns the addressable leaf value. When alloc is
+// true, nil struct pointers along the way are allocated.
+func resolve(root interface{}, path string, alloc bool) (reflect.Value, error) {
+	v := reflect.ValueOf(root)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return reflect.Value{}, fmt.Errorf("root must be a non-nil pointer")
+	}
+	if path == "" {
+		return reflect.Value{}, fmt.Errorf("empty field path")
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		name, index, err := parseSegment(segment)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", path, err)
+		}
+
+		v, err = deref(v, alloc, path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("%s: %q is not an object", path, name)
+		}
+		field, ok := fieldByJSONName(v, name)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%s: unknown field %q", path, name)
+		}
+		v = field
+
+		if index >= 0 {
+			if v.Kind() != reflect.Slice {
+				return reflect.Value{}, fmt.Errorf("%s: %q is not a list", path, name)
+			}
+			if index >= v.Len() {
+				return reflect.Value{}, fmt.Errorf("%s: index %d out of range (%d items)", path, index, v.Len())
+			}
+			v = v.Index(index)
+		}
+	}
+	return v, nil
+}
+
+// deref follows struct pointers, allocating nil ones when alloc is true.
+func deref(v reflect.Value, alloc bool, path string) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !alloc || v.Type().Elem().Kind() != reflect.Struct {
+				return reflect.Value{}, fmt.Errorf("%s is not set", path)
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v, nil
+}
+
+// parseSegment splits "name[3]" into ("name", 3); index is -1 when absent.
+func parseSegment(segment string) (string, int, error) {
+	open := strings.IndexByte(segment, '[')
+	if open < 0 {
+		return segment, -1, nil
+	}
+	if !strings.HasSuffix(segment, "]") || open == 0 {
+		return "", 0, fmt.Errorf("malformed segment %q", segment)
+	}
+	index, err := strconv.Atoi(segment[open+1 : len(segment)-1])
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("invalid index in %q", segment)
+	}
+	return segment[:open], index, nil
+}
+
+// fieldByJSONName finds the struct field whose JSON tag name is name.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// List returns the paths of every set, non-zero float64 field in root, in
+// struct order. Slices of structs are walked element by element; numeric
+// slices (distributions, ramps) are skipped.
+func List(root interface{}) []string {
+	var paths []string
+	walk(reflect.ValueOf(root), "", &paths)
+	return paths
+}
+
+func walk(v reflect.Value, prefix string, paths *[]string) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Float64:
+		if v.Float() != 0 {
+			*paths = append(*paths, prefix)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			walk(v.Field(i), path, paths)
+		}
+	case reflect.Slice:
+		elem := v.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), fmt.Sprintf("%s[%d]", prefix, i), paths)
+		}
+	}
+}
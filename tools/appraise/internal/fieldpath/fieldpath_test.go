@@ -0,0 +1,118 @@
+package fieldpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func TestGetSet(t *testing.T) {
+	input := &domain.AppraisalInput{
+		Product:    &domain.ProductDefinition{Name: "Bundle", Price: 20},
+		Financials: &domain.FinancialData{COGS: ptr(5)},
+		Components: []domain.ComponentData{{Name: "A", MarginalCost: ptr(1.5)}},
+	}
+
+	tests := []struct {
+		path string
+		want float64
+	}{
+		{"product.price", 20},
+		{"financials.cogs", 5},
+		{"components[0].marginal_cost", 1.5},
+	}
+	for _, tt := range tests {
+		got, err := Get(input, tt.path)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("Get(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Set allocates nil pointers along the path.
+	if err := Set(input, "customers.base_churn_rate", 0.03); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if input.Customers == nil || input.Customers.BaseChurnRate == nil || *input.Customers.BaseChurnRate != 0.03 {
+		t.Errorf("customers.base_churn_rate not set: %+v", input.Customers)
+	}
+	if err := Set(input, "components[0].marginal_cost", 2); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if *input.Components[0].MarginalCost != 2 {
+		t.Errorf("marginal_cost = %v, want 2", *input.Components[0].MarginalCost)
+	}
+	if err := Set(input, "product.swap_picks", 2.6); err != nil {
+		t.Fatalf("Set int: %v", err)
+	}
+	if *input.Product.SwapPicks != 3 {
+		t.Errorf("swap_picks = %v, want 3 (rounded)", *input.Product.SwapPicks)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	input := &domain.AppraisalInput{Product: &domain.ProductDefinition{Name: "Bundle"}}
+
+	tests := []struct {
+		name    string
+		get     bool
+		path    string
+		wantErr string
+	}{
+		{"unset pointer", true, "financials.cogs", "not set"},
+		{"unknown field", true, "product.colour", "unknown field"},
+		{"not numeric", true, "product.name", "not numeric"},
+		{"index out of range", false, "components[2].marginal_cost", "out of range"},
+		{"malformed index", false, "components[x].marginal_cost", "invalid index"},
+		{"not a list", false, "product[0].price", "not a list"},
+		{"empty path", true, "", "empty field path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.get {
+				_, err = Get(input, tt.path)
+			} else {
+				err = Set(input, tt.path, 1)
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	input := &domain.AppraisalInput{
+		Product: &domain.ProductDefinition{Name: "Bundle", Price: 20},
+		Financials: &domain.FinancialData{
+			COGS:           ptr(5),
+			FixedCosts:     ptr(0), // zero: nothing to perturb
+			MonteCarlo:     &domain.MonteCarloConfig{},
+			Projection:     &domain.ProjectionData{NewCustomers: []float64{1, 2}},
+			GrossMarginPct: ptr(0.4),
+		},
+		Components: []domain.ComponentData{
+			{Name: "A", MarginalCost: ptr(1.5), UsageDistribution: []float64{1, 2}},
+			{Name: "B", StandalonePrice: ptr(9)},
+		},
+	}
+
+	got := List(input)
+	want := []string{
+		"product.price",
+		"financials.cogs",
+		"financials.gross_margin_pct",
+		"components[0].marginal_cost",
+		"components[1].standalone_price",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("List = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,273 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// DefaultConfidence is the confidence level of the calculators' significance
+// tests and intervals.
+const DefaultConfidence = 0.95
+
+// TwoProportionZTest tests whether the proportions x1/n1 and x2/n2 differ,
+// using the pooled standard error. It returns z (positive when the first
+// proportion is larger) and the two-sided p-value.
+func TwoProportionZTest(x1, n1, x2, n2 float64) (z, p float64, err error) {
+	if n1 <= 0 || n2 <= 0 {
+		return 0, 0, fmt.Errorf("sample sizes must be positive")
+	}
+	if x1 < 0 || x1 > n1 || x2 < 0 || x2 > n2 {
+		return 0, 0, fmt.Errorf("successes must be between 0 and the sample size")
+	}
+	p1, p2 := x1/n1, x2/n2
+	pooled := (x1 + x2) / (n1 + n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n2))
+	if se == 0 {
+		// Both samples all-or-nothing in the same direction.
+		return 0, 1, nil
+	}
+	z = (p1 - p2) / se
+	return z, 2 * (1 - NormalCDF(math.Abs(z))), nil
+}
+
+// WilsonInterval returns the Wilson score interval for the proportion x/n
+// at the given confidence level. The bounds are exactly 0 and 1 when x is 0
+// or n, where rounding would otherwise leave them just inside.
+func WilsonInterval(x, n, confidence float64) (lower, upper float64) {
+	if n <= 0 {
+		return 0, 0
+	}
+	z := NormalQuantile(1 - (1-confidence)/2)
+	p := x / n
+	denom := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denom
+	half := z / denom * math.Sqrt(p*(1-p)/n+z*z/(4*n*n))
+	lower, upper = math.Max(0, center-half), math.Min(1, center+half)
+	if x <= 0 {
+		lower = 0
+	}
+	if x >= n {
+		upper = 1
+	}
+	return lower, upper
+}
+
+// ChiSquareTest runs Pearson's chi-square test of independence on a
+// contingency table of observed counts (rows x columns, at least 2x2). It
+// returns the statistic, degrees of freedom and p-value. Empty columns carry
+// no evidence and are left out; with fewer than two outcomes observed the
+// table shows no difference (statistic 0, p = 1).
+func ChiSquareTest(observed [][]float64) (chi2 float64, df int, p float64, err error) {
+	rows := len(observed)
+	if rows < 2 || len(observed[0]) < 2 {
+		return 0, 0, 0, fmt.Errorf("contingency table must be at least 2x2")
+	}
+	cols := len(observed[0])
+	rowSums := make([]float64, rows)
+	colSums := make([]float64, cols)
+	total := 0.0
+	for i, row := range observed {
+		if len(row) != cols {
+			return 0, 0, 0, fmt.Errorf("contingency table rows must have equal length")
+		}
+		for j, v := range row {
+			if v < 0 {
+				return 0, 0, 0, fmt.Errorf("counts must be non-negative")
+			}
+			rowSums[i] += v
+			colSums[j] += v
+			total += v
+		}
+	}
+	for _, sum := range rowSums {
+		if sum == 0 {
+			return 0, 0, 0, fmt.Errorf("contingency table has an empty row")
+		}
+	}
+	observedCols := 0
+	for _, sum := range colSums {
+		if sum > 0 {
+			observedCols++
+		}
+	}
+	if observedCols < 2 {
+		return 0, 0, 1, nil
+	}
+	for i, row := range observed {
+		for j, v := range row {
+			if colSums[j] == 0 {
+				continue
+			}
+			expected := rowSums[i] * colSums[j] / total
+			chi2 += (v - expected) * (v - expected) / expected
+		}
+	}
+	df = (rows - 1) * (observedCols - 1)
+	return chi2, df, 1 - ChiSquareCDF(chi2, float64(df)), nil
+}
+
+// WelchTTest tests whether the means of two samples differ without assuming
+// equal variances. It returns t (positive when the first mean is larger),
+// the Welch-Satterthwaite degrees of freedom and the two-sided p-value.
+func WelchTTest(a, b []float64) (t, df, p float64, err error) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, 0, 0, fmt.Errorf("each sample needs at least two values")
+	}
+	na, nb := float64(len(a)), float64(len(b))
+	va, vb := StdDev(a)*StdDev(a)/na, StdDev(b)*StdDev(b)/nb
+	se := math.Sqrt(va + vb)
+	if se == 0 {
+		return 0, 0, 0, fmt.Errorf("both samples have zero variance")
+	}
+	t = (Mean(a) - Mean(b)) / se
+	df = (va + vb) * (va + vb) / (va*va/(na-1) + vb*vb/(nb-1))
+	return t, df, 2 * (1 - StudentTCDF(math.Abs(t), df)), nil
+}
+
+// BootstrapCI returns the percentile bootstrap interval of stat at the given
+// confidence level. Each resample draws every sample with replacement, at
+// its own size; the rng is seeded so results are reproducible.
+func BootstrapCI(samples [][]float64, stat func(samples [][]float64) float64, resamples int, confidence float64, seed uint64) (lower, upper float64) {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	draws := make([][]float64, len(samples))
+	for i, s := range samples {
+		draws[i] = make([]float64, len(s))
+	}
+	estimates := make([]float64, resamples)
+	for r := range estimates {
+		for i, s := range samples {
+			for j := range draws[i] {
+				draws[i][j] = s[rng.IntN(len(s))]
+			}
+		}
+		estimates[r] = stat(draws)
+	}
+	sort.Float64s(estimates)
+	alpha := (1 - confidence) / 2
+	return percentileSorted(estimates, alpha), percentileSorted(estimates, 1-alpha)
+}
+
+// ChiSquareCDF returns the chi-square cumulative distribution with df
+// degrees of freedom at x.
+func ChiSquareCDF(x, df float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return regularizedGammaP(df/2, x/2)
+}
+
+// StudentTCDF returns Student's t cumulative distribution with df degrees of
+// freedom at t.
+func StudentTCDF(t, df float64) float64 {
+	tail := 0.5 * regularizedBeta(df/(df+t*t), df/2, 0.5)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// regularizedGammaP is the regularized lower incomplete gamma P(a, x), by
+// series for x < a+1 and by continued fraction otherwise.
+func regularizedGammaP(a, x float64) float64 {
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 500; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return sum * prefix
+	}
+	// Lentz's method for the upper tail Q(a, x).
+	b := x + 1 - a
+	c := 1 / 1e-300
+	d := 1 / b
+	h := d
+	for i := 1; i < 500; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < 1e-300 {
+			d = 1e-300
+		}
+		c = b + an/c
+		if math.Abs(c) < 1e-300 {
+			c = 1e-300
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return 1 - prefix*h
+}
+
+// regularizedBeta is the regularized incomplete beta I_x(a, b).
+func regularizedBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges fast for x < (a+1)/(a+b+2).
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the incomplete beta continued fraction by
+// Lentz's method.
+func betaContinuedFraction(x, a, b float64) float64 {
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m < 500; m++ {
+		fm := float64(m)
+		even := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + even*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + even/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		odd := -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + odd*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + odd/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
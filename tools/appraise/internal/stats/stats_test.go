@@ -0,0 +1,229 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-9
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+func TestMean(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{name: "empty", values: nil, want: 0},
+		{name: "single", values: []float64{4}, want: 4},
+		{name: "several", values: []float64{1, 2, 3, 6}, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mean(tt.values); !almostEqual(got, tt.want) {
+				t.Errorf("Mean = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{40, 10, 30, 20, 50}
+
+	tests := []struct {
+		name string
+		p    float64
+		want float64
+	}{
+		{name: "min", p: 0, want: 10},
+		{name: "median", p: 0.5, want: 30},
+		{name: "max", p: 1, want: 50},
+		{name: "interpolated_p90", p: 0.9, want: 46},
+		{name: "interpolated_p10", p: 0.1, want: 14},
+		{name: "clamped_above_one", p: 1.5, want: 50},
+		{name: "clamped_below_zero", p: -0.2, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(values, tt.p); !almostEqual(got, tt.want) {
+				t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+
+	if values[0] != 40 {
+		t.Error("Percentile modified its input")
+	}
+	if got := Percentile(nil, 0.5); got != 0 {
+		t.Errorf("Percentile(nil) = %v, want 0", got)
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	if got := StdDev([]float64{2, 4, 4, 4, 5, 5, 7, 9}); math.Abs(got-2.138089935299395) > epsilon {
+		t.Errorf("StdDev = %v, want 2.1381", got)
+	}
+	if got := StdDev([]float64{3}); got != 0 {
+		t.Errorf("StdDev(single) = %v, want 0", got)
+	}
+}
+
+func TestNormalCDF(t *testing.T) {
+	tests := []struct {
+		z    float64
+		want float64
+	}{
+		{0, 0.5},
+		{1.959963984540054, 0.975},
+		{-1, 0.15865525393145707},
+	}
+
+	for _, tt := range tests {
+		if got := NormalCDF(tt.z); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("NormalCDF(%v) = %v, want %v", tt.z, got, tt.want)
+		}
+	}
+}
+
+func TestNormalQuantile(t *testing.T) {
+	for _, p := range []float64{0.025, 0.5, 0.9, 0.975} {
+		if got := NormalCDF(NormalQuantile(p)); math.Abs(got-p) > 1e-12 {
+			t.Errorf("NormalCDF(NormalQuantile(%v)) = %v", p, got)
+		}
+	}
+	if got := NormalQuantile(0.975); math.Abs(got-1.959963984540054) > 1e-9 {
+		t.Errorf("NormalQuantile(0.975) = %v, want 1.96", got)
+	}
+}
+
+func TestCholesky(t *testing.T) {
+	l, err := Cholesky([][]float64{{4, 2}, {2, 10}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]float64{{2, 0}, {1, 3}}
+	for i := range want {
+		for j := range want[i] {
+			if !almostEqual(l[i][j], want[i][j]) {
+				t.Errorf("L[%d][%d] = %v, want %v", i, j, l[i][j], want[i][j])
+			}
+		}
+	}
+
+	if _, err := Cholesky([][]float64{{1, 2}, {2, 1}}); err == nil {
+		t.Error("expected error for non-positive-definite matrix")
+	}
+}
+
+func TestChiSquareCDF(t *testing.T) {
+	for _, x := range []float64{0.1, 1, 3.84, 10} {
+		// Closed forms for 1, 2 and 4 degrees of freedom.
+		if got, want := ChiSquareCDF(x, 1), math.Erf(math.Sqrt(x/2)); math.Abs(got-want) > 1e-12 {
+			t.Errorf("ChiSquareCDF(%v, 1) = %v, want %v", x, got, want)
+		}
+		if got, want := ChiSquareCDF(x, 2), 1-math.Exp(-x/2); math.Abs(got-want) > 1e-12 {
+			t.Errorf("ChiSquareCDF(%v, 2) = %v, want %v", x, got, want)
+		}
+		if got, want := ChiSquareCDF(x, 4), 1-math.Exp(-x/2)*(1+x/2); math.Abs(got-want) > 1e-12 {
+			t.Errorf("ChiSquareCDF(%v, 4) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestStudentTCDF(t *testing.T) {
+	for _, x := range []float64{-3, -0.5, 0, 0.7, 2, 12.7} {
+		// Closed forms for 1 and 2 degrees of freedom.
+		if got, want := StudentTCDF(x, 1), 0.5+math.Atan(x)/math.Pi; math.Abs(got-want) > 1e-12 {
+			t.Errorf("StudentTCDF(%v, 1) = %v, want %v", x, got, want)
+		}
+		if got, want := StudentTCDF(x, 2), 0.5+x/(2*math.Sqrt(2+x*x)); math.Abs(got-want) > 1e-12 {
+			t.Errorf("StudentTCDF(%v, 2) = %v, want %v", x, got, want)
+		}
+	}
+	// Table value: t(0.975, 10) = 2.228
+	if got := StudentTCDF(2.228138851986274, 10); math.Abs(got-0.975) > 1e-9 {
+		t.Errorf("StudentTCDF(2.228, 10) = %v, want 0.975", got)
+	}
+}
+
+func TestTwoProportionZTest(t *testing.T) {
+	z, p, err := TwoProportionZTest(30, 100, 20, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantZ := 0.1 / math.Sqrt(0.25*0.75*0.02)
+	if !almostEqual(z, wantZ) || !almostEqual(p, 2*(1-NormalCDF(wantZ))) {
+		t.Errorf("z, p = %v, %v, want %v", z, p, wantZ)
+	}
+
+	// A 2x2 chi-square test is the squared z-test.
+	chi2, df, chiP, err := ChiSquareTest([][]float64{{30, 70}, {20, 80}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !almostEqual(chi2, wantZ*wantZ) || df != 1 || math.Abs(chiP-p) > 1e-12 {
+		t.Errorf("chi2, df, p = %v, %d, %v, want %v, 1, %v", chi2, df, chiP, wantZ*wantZ, p)
+	}
+
+	if _, _, err := TwoProportionZTest(5, 4, 1, 10); err == nil {
+		t.Error("expected error for successes above sample size")
+	}
+	if _, _, _, err := ChiSquareTest([][]float64{{1, 2}, {0, 0}}); err == nil {
+		t.Error("expected error for empty row")
+	}
+	// Nobody converts in either arm: no difference to test.
+	if chi2, df, p, err := ChiSquareTest([][]float64{{0, 100}, {0, 100}}); err != nil || chi2 != 0 || df != 0 || p != 1 {
+		t.Errorf("empty column: chi2, df, p, err = %v, %d, %v, %v, want 0, 0, 1, nil", chi2, df, p, err)
+	}
+}
+
+func TestWilsonInterval(t *testing.T) {
+	lower, upper := WilsonInterval(60, 1000, 0.95)
+	if !(lower < 0.06 && 0.06 < upper) || lower < 0.04 || upper > 0.08 {
+		t.Errorf("CI = [%v, %v], want around 0.06", lower, upper)
+	}
+	// All-or-nothing samples reach the ends of the scale exactly.
+	if lower, _ := WilsonInterval(0, 100, 0.95); lower != 0 {
+		t.Errorf("lower bound with no successes = %v, want 0", lower)
+	}
+	if _, upper := WilsonInterval(100, 100, 0.95); upper != 1 {
+		t.Errorf("upper bound with all successes = %v, want 1", upper)
+	}
+}
+
+func TestWelchTTest(t *testing.T) {
+	tStat, df, p, err := WelchTTest([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Standard errors 0.5 and 2 (squared), df = 2.5^2 / (0.5^2/4 + 2^2/4)
+	if !almostEqual(tStat, -3/math.Sqrt(2.5)) || !almostEqual(df, 6.25/1.0625) {
+		t.Errorf("t, df = %v, %v", tStat, df)
+	}
+	if !almostEqual(p, 2*StudentTCDF(tStat, df)) || p < 0.05 || p > 0.2 {
+		t.Errorf("p = %v", p)
+	}
+
+	if _, _, _, err := WelchTTest([]float64{1}, []float64{1, 2}); err == nil {
+		t.Error("expected error for a one-value sample")
+	}
+}
+
+func TestBootstrapCI(t *testing.T) {
+	sample := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	mean := func(s [][]float64) float64 { return Mean(s[0]) }
+
+	lower, upper := BootstrapCI([][]float64{sample}, mean, 2000, 0.95, 1)
+	if !(lower < 5.5 && 5.5 < upper) || lower < 3 || upper > 8 {
+		t.Errorf("CI = [%v, %v], want around 5.5", lower, upper)
+	}
+	// Same seed, same interval.
+	if l2, u2 := BootstrapCI([][]float64{sample}, mean, 2000, 0.95, 1); l2 != lower || u2 != upper {
+		t.Errorf("CI not reproducible: [%v, %v] vs [%v, %v]", l2, u2, lower, upper)
+	}
+}
@@ -0,0 +1,87 @@
+package valuation
+
+import (
+	"fmt"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// BVR returns the Bundle Value Ratio, sum of standalone prices / bundle
+// price, and the standalone sum.
+func BVR(p *domain.ProductDefinition) (bvr, standaloneSum float64, err error) {
+	if p == nil {
+		return 0, 0, fmt.Errorf("product definition required")
+	}
+	if len(p.Components) == 0 {
+		return 0, 0, fmt.Errorf("product must have components for BVR calculation")
+	}
+	if p.Price <= 0 {
+		return 0, 0, fmt.Errorf("product price must be positive")
+	}
+	standaloneSum = StandaloneSum(p)
+	return standaloneSum / p.Price, standaloneSum, nil
+}
+
+// StandaloneSum returns the sum of the components' standalone prices.
+func StandaloneSum(p *domain.ProductDefinition) float64 {
+	sum := 0.0
+	for _, comp := range p.Components {
+		sum += comp.StandalonePrice
+	}
+	return sum
+}
+
+// BundleDiscount returns 1 - bundle price / sum of standalone prices.
+func BundleDiscount(p *domain.ProductDefinition) (float64, error) {
+	if p == nil {
+		return 0, fmt.Errorf("product definition required")
+	}
+	if len(p.Components) == 0 {
+		return 0, fmt.Errorf("product must have components")
+	}
+	standaloneSum := StandaloneSum(p)
+	if standaloneSum <= 0 {
+		return 0, fmt.Errorf("standalone sum must be positive")
+	}
+	return 1.0 - (p.Price / standaloneSum), nil
+}
+
+// CostFloor returns the minimum viable price:
+// direct + partner/licensing + shared + CAC(amortized) + service, grossed up
+// by the target margin. For physical goods without direct_cost_per_customer,
+// the landed unit cost is used as the direct cost and returned as landed.
+func CostFloor(f *domain.FinancialData) (floor float64, landed *float64, err error) {
+	if f == nil {
+		return 0, nil, fmt.Errorf("financial data required")
+	}
+
+	if f.DirectCostPerCustomer != nil {
+		floor += *f.DirectCostPerCustomer
+	} else if f.PhysicalGoods != nil {
+		lc, err := LandedCost(f.PhysicalGoods)
+		if err != nil {
+			return 0, nil, fmt.Errorf("landed cost: %w", err)
+		}
+		landed = &lc.LandedCost
+		floor += lc.LandedCost
+	}
+	if f.PartnerLicensingCost != nil {
+		floor += *f.PartnerLicensingCost
+	}
+	if f.SharedCostPerCustomer != nil {
+		floor += *f.SharedCostPerCustomer
+	}
+	if f.TotalAcquisitionSpend != nil && f.NewCustomersAcquired != nil && *f.NewCustomersAcquired > 0 {
+		floor += *f.TotalAcquisitionSpend / *f.NewCustomersAcquired
+	}
+	if f.CustomerServiceCost != nil {
+		floor += *f.CustomerServiceCost
+	}
+	if f.TargetMinMargin != nil {
+		// Add margin as absolute amount: floor / (1 - margin%) - floor
+		if *f.TargetMinMargin < 1.0 {
+			floor = floor / (1.0 - *f.TargetMinMargin)
+		}
+	}
+	return floor, landed, nil
+}
@@ -0,0 +1,84 @@
+// Package valuation holds the cost and value formulas that more than one
+// calculator module needs, so the modules share them without importing each
+// other. Functions take the plain input sections they read and return values
+// or domain results without interpretation; that stays with the calculators.
+package valuation
+
+import (
+	"fmt"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+// LandedCost builds the per-unit landed cost of a physical product:
+//
+//	ex-works = BOM (or unit_cost) + assembly + packaging
+//	duty     = duty_rate * (ex-works + freight)
+//	landed   = ex-works + freight + duty + other_landed_cost
+//
+// With inventory_turns it also reports days inventory outstanding, and with
+// annual_units the annual landed cost and average inventory value.
+func LandedCost(pg *domain.PhysicalGoodsData) (*domain.LandedCostResult, error) {
+	if pg == nil {
+		return nil, fmt.Errorf("financials.physical_goods required")
+	}
+
+	result := &domain.LandedCostResult{
+		AssemblyCost:    ValueOr(pg.AssemblyCost, 0),
+		PackagingCost:   ValueOr(pg.PackagingCost, 0),
+		Freight:         ValueOr(pg.FreightPerUnit, 0),
+		OtherLandedCost: ValueOr(pg.OtherLandedCost, 0),
+	}
+	switch {
+	case len(pg.BillOfMaterials) > 0:
+		for _, item := range pg.BillOfMaterials {
+			qty := ValueOr(item.Quantity, 1)
+			if item.UnitCost < 0 || qty < 0 {
+				return nil, fmt.Errorf("bill_of_materials %q: unit_cost and quantity must be non-negative", item.Name)
+			}
+			result.BOMCost += item.UnitCost * qty
+		}
+	case pg.UnitCost != nil:
+		result.BOMCost = *pg.UnitCost
+	default:
+		return nil, fmt.Errorf("physical_goods.bill_of_materials or unit_cost required")
+	}
+	dutyRate := ValueOr(pg.DutyRate, 0)
+	if dutyRate < 0 {
+		return nil, fmt.Errorf("duty_rate must be non-negative")
+	}
+
+	result.ExWorksCost = result.BOMCost + result.AssemblyCost + result.PackagingCost
+	result.Duty = dutyRate * (result.ExWorksCost + result.Freight)
+	logistics := result.Freight + result.Duty + result.OtherLandedCost
+	result.LandedCost = result.ExWorksCost + logistics
+	if result.LandedCost > 0 {
+		result.LogisticsShare = logistics / result.LandedCost
+	}
+
+	if pg.InventoryTurns != nil {
+		if *pg.InventoryTurns <= 0 {
+			return nil, fmt.Errorf("inventory_turns must be positive")
+		}
+		dio := 365 / *pg.InventoryTurns
+		result.DaysInventoryOutstanding = &dio
+	}
+	if pg.AnnualUnits != nil {
+		annual := result.LandedCost * *pg.AnnualUnits
+		result.AnnualLandedCost = &annual
+		if pg.InventoryTurns != nil {
+			inventory := annual / *pg.InventoryTurns
+			result.AverageInventoryValue = &inventory
+		}
+	}
+
+	return result, nil
+}
+
+// ValueOr dereferences v, or returns fallback when v is nil.
+func ValueOr(v *float64, fallback float64) float64 {
+	if v == nil {
+		return fallback
+	}
+	return *v
+}
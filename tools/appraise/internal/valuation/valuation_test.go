@@ -0,0 +1,122 @@
+package valuation
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ivalx1s/skill-product-appraisal/tools/appraise/internal/domain"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func product(price float64, standalone ...float64) *domain.ProductDefinition {
+	p := &domain.ProductDefinition{Name: "Bundle", Price: price}
+	for _, s := range standalone {
+		p.Components = append(p.Components, domain.Component{StandalonePrice: s})
+	}
+	return p
+}
+
+func TestBVRAndBundleDiscount(t *testing.T) {
+	tests := []struct {
+		name         string
+		product      *domain.ProductDefinition
+		wantBVR      float64
+		wantDiscount float64
+		bvrErr       string
+		discountErr  string
+	}{
+		{"bundle", product(100, 60, 40, 50), 1.5, 1 - 100.0/150, "", ""},
+		{"no product", nil, 0, 0, "product definition required", "product definition required"},
+		{"no components", product(100), 0, 0, "must have components", "must have components"},
+		{"free bundle", product(0, 10), 0, 1, "price must be positive", ""},
+		{"free components", product(10, 0), 0, 0, "", "standalone sum must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bvr, _, err := BVR(tt.product)
+			if tt.bvrErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.bvrErr) {
+					t.Errorf("BVR error = %v, want containing %q", err, tt.bvrErr)
+				}
+			} else if err != nil || math.Abs(bvr-tt.wantBVR) > 1e-9 {
+				t.Errorf("BVR = %v (%v), want %v", bvr, err, tt.wantBVR)
+			}
+
+			discount, err := BundleDiscount(tt.product)
+			if tt.discountErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.discountErr) {
+					t.Errorf("BundleDiscount error = %v, want containing %q", err, tt.discountErr)
+				}
+			} else if err != nil || math.Abs(discount-tt.wantDiscount) > 1e-9 {
+				t.Errorf("BundleDiscount = %v (%v), want %v", discount, err, tt.wantDiscount)
+			}
+		})
+	}
+}
+
+func TestCostFloor(t *testing.T) {
+	physical := &domain.PhysicalGoodsData{
+		BillOfMaterials: []domain.BOMItem{{Name: "board", UnitCost: 10, Quantity: ptr(2)}, {Name: "case", UnitCost: 5}},
+		FreightPerUnit:  ptr(5),
+		DutyRate:        ptr(0.1),
+	}
+
+	tests := []struct {
+		name       string
+		financials *domain.FinancialData
+		wantFloor  float64
+		wantLanded *float64
+		wantErr    string
+	}{
+		{
+			name: "direct cost with margin",
+			financials: &domain.FinancialData{
+				DirectCostPerCustomer: ptr(30), PartnerLicensingCost: ptr(10), CustomerServiceCost: ptr(8),
+				TotalAcquisitionSpend: ptr(1000), NewCustomersAcquired: ptr(50), TargetMinMargin: ptr(0.2),
+			},
+			wantFloor: (30 + 10 + 8 + 20) / 0.8,
+		},
+		{
+			// ex-works 25 + freight 5 + duty 3
+			name:       "landed cost as direct cost",
+			financials: &domain.FinancialData{PhysicalGoods: physical, SharedCostPerCustomer: ptr(2)},
+			wantFloor:  35,
+			wantLanded: ptr(33),
+		},
+		{
+			name:       "direct cost wins over landed",
+			financials: &domain.FinancialData{DirectCostPerCustomer: ptr(12), PhysicalGoods: physical},
+			wantFloor:  12,
+		},
+		{"no financials", nil, 0, nil, "financial data required"},
+		{
+			"bad physical goods",
+			&domain.FinancialData{PhysicalGoods: &domain.PhysicalGoodsData{}},
+			0, nil, "landed cost: physical_goods.bill_of_materials or unit_cost required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			floor, landed, err := CostFloor(tt.financials)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(floor-tt.wantFloor) > 1e-9 {
+				t.Errorf("floor = %v, want %v", floor, tt.wantFloor)
+			}
+			if (landed == nil) != (tt.wantLanded == nil) || (landed != nil && math.Abs(*landed-*tt.wantLanded) > 1e-9) {
+				t.Errorf("landed = %v, want %v", landed, tt.wantLanded)
+			}
+		})
+	}
+}